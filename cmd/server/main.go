@@ -2,8 +2,10 @@ package main
 
 import (
 	"context"
-	"log"
+	"encoding/json"
+	"log/slog"
 	"net/http"
+	"net/url"
 	"os"
 	"os/signal"
 	"strconv"
@@ -11,21 +13,37 @@ import (
 	"time"
 
 	"github.com/dipak0000812/orchestrix/internal/api"
+	"github.com/dipak0000812/orchestrix/internal/blobstore"
+	"github.com/dipak0000812/orchestrix/internal/config"
 	"github.com/dipak0000812/orchestrix/internal/executor"
-	"github.com/dipak0000812/orchestrix/internal/job/model"
 	"github.com/dipak0000812/orchestrix/internal/job/repository"
 	"github.com/dipak0000812/orchestrix/internal/job/service"
 	"github.com/dipak0000812/orchestrix/internal/job/state"
 	"github.com/dipak0000812/orchestrix/internal/metrics"
 	"github.com/dipak0000812/orchestrix/internal/scheduler"
 	"github.com/dipak0000812/orchestrix/internal/worker"
+	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 )
 
 func main() {
-	log.Println("Starting Orchestrix...")
+	logger := config.NewLogger(config.LoggingConfig{
+		Level:  getEnv("LOG_LEVEL", "info"),
+		Format: getEnv("LOG_FORMAT", "text"),
+	})
+	slog.SetDefault(logger)
+
+	slog.Info("Starting Orchestrix...")
+
+	shutdownTimeout := time.Duration(getEnvInt("SHUTDOWN_TIMEOUT_SECONDS", 30)) * time.Second
 
 	// 1. Create database connection
+	dbParams, err := url.ParseQuery(getEnv("DB_PARAMS", ""))
+	if err != nil {
+		slog.Error("Invalid DB_PARAMS", "error", err)
+		os.Exit(1)
+	}
+
 	dbConfig := repository.DBConfig{
 		Host:            getEnv("DB_HOST", "localhost"),
 		Port:            getEnvInt("DB_PORT", 5434),
@@ -37,75 +55,204 @@ func main() {
 		MinConnections:  2,
 		MaxConnLifetime: 30 * time.Minute,
 		MaxConnIdleTime: 5 * time.Minute,
+		PingAttempts:    getEnvInt("DB_PING_ATTEMPTS", 10),
+		PingBackoff:     500 * time.Millisecond,
+		PingMaxBackoff:  5 * time.Second,
+		AcquireTimeout:  time.Duration(getEnvInt("DB_ACQUIRE_TIMEOUT_MS", 5000)) * time.Millisecond,
+		Params:          make(map[string]string),
+	}
+	for key := range dbParams {
+		dbConfig.Params[key] = dbParams.Get(key)
 	}
 
 	pool, err := repository.NewConnectionPool(context.Background(), dbConfig)
 	if err != nil {
-		log.Fatalf("Failed to connect to database: %v", err)
+		slog.Error("Failed to connect to database", "error", err)
+		os.Exit(1)
 	}
 	defer repository.ClosePool(pool)
-	log.Println("Connected to database")
+	slog.Info("Connected to database")
 
 	// 2. Create repository and service
 	repo := repository.NewPostgresJobRepository(pool)
+	repo.SetAcquireTimeout(dbConfig.AcquireTimeout)
+	claimPolicy, err := repository.ParseClaimPolicy(getEnv("CLAIM_POLICY", "fifo"))
+	if err != nil {
+		slog.Error("Invalid CLAIM_POLICY", "error", err)
+		os.Exit(1)
+	}
+	repo.SetClaimPolicy(claimPolicy)
+	repo.SetMaxConcurrentClaims(getEnvInt("MAX_CONCURRENT_CLAIMS", 0))
+	repo.SetPriorityAging(time.Duration(getEnvInt("PRIORITY_AGING_SECONDS", 0)) * time.Second)
 	stateMachine := state.NewStateMachine()
 	idGen := service.NewULIDGenerator()
 	retryConfig := service.DefaultRetryConfig()
+	retryConfig.PriorityBoost = getEnvInt("RETRY_PRIORITY_BOOST", 0)
+	retryConfig.MaxPriority = getEnvInt("RETRY_MAX_PRIORITY", 10)
+	retryConfig.MaxAllowedAttempts = getEnvInt("MAX_ALLOWED_ATTEMPTS", retryConfig.MaxAllowedAttempts)
+	retryConfig.MaxElapsed = time.Duration(getEnvInt("RETRY_MAX_ELAPSED_SECONDS", 0)) * time.Second
 	jobService := service.NewJobService(repo, stateMachine, idGen, retryConfig)
+	defer jobService.Close()
+	jobService.SetMaxListLimit(getEnvInt("MAX_LIST_LIMIT", service.DefaultMaxListLimit))
+	typePrefixedIDs, _ := strconv.ParseBool(getEnv("JOB_ID_TYPE_PREFIX", "false"))
+	jobService.SetTypePrefixedIDs(typePrefixedIDs)
+
+	// Optionally keep large payloads out of the database
+	if blobDir := getEnv("PAYLOAD_BLOB_DIR", ""); blobDir != "" {
+		blobs, err := blobstore.NewFSBlobStore(blobDir)
+		if err != nil {
+			slog.Error("Failed to open payload blob store", "error", err)
+			os.Exit(1)
+		}
+		jobService.SetBlobStore(blobs, getEnvInt("PAYLOAD_BLOB_THRESHOLD_BYTES", 1<<20))
+		slog.Info("Storing large payloads externally", "dir", blobDir)
+	}
 
 	// 3. Create executor registry
 	executors := executor.NewExecutorRegistry()
 	executors.Register("demo_job", executor.NewDemoExecutor(1*time.Second))
-	log.Println("Registered executors: demo_job")
+	executors.Register("scripted_job", executor.NewScriptedExecutor())
+	slog.Info("Registered executors", "types", []string{"demo_job", "scripted_job"})
 
 	// 4. Create job channel and metrics
-	jobChannel := make(chan *model.Job, 100)
-	m := metrics.NewMetrics()
+	jobChannel := scheduler.NewJobChannel(getEnvInt("JOB_CHANNEL_SIZE", scheduler.DefaultChannelSize))
+	m := metrics.NewMetrics(prometheus.DefaultRegisterer)
+
+	// 5. Resume jobs a previous run left SCHEDULED or RUNNING, then start scheduler
+	recovered, err := jobService.RecoverInterruptedJobs(context.Background())
+	if err != nil {
+		slog.Error("Failed to recover interrupted jobs", "error", err)
+		os.Exit(1)
+	}
+	slog.Info("Recovered interrupted jobs",
+		"requeued", recovered.Requeued,
+		"retried", recovered.Retried,
+		"failed", recovered.Failed,
+	)
 
-	// 5. Create and start scheduler
 	sched := scheduler.NewScheduler(
 		repo,
 		1*time.Second,
 		10,
 		jobChannel,
+		m,
+		time.Duration(getEnvInt("SCHEDULER_SEND_TIMEOUT_MS", 5000))*time.Millisecond,
 	)
-	sched.Start()
-	defer sched.Stop()
 
 	// 6. Create and start worker pool
 	workers := worker.NewWorkerPool(
 		5,
+		getEnvInt("WORKER_MAX_CONCURRENT", 5),
 		jobChannel,
 		executors,
 		jobService,
 		m, // ← Added: metrics
 		10*time.Second,
 	)
+	workers.SetStopTimeout(shutdownTimeout)
+
+	// Job types paused in the pool are left unclaimed
+	sched.SetPausedTypes(workers.PausedTypes)
+	sched.Start()
+	defer sched.Stop()
+
 	workers.Start()
-	defer workers.Stop()
+	defer func() {
+		// Let running jobs finish; whatever is left at the deadline is interrupted
+		drainTimeout := time.Duration(getEnvInt("WORKER_DRAIN_SECONDS", 30)) * time.Second
+		ctx, cancel := context.WithTimeout(context.Background(), drainTimeout)
+		defer cancel()
+		workers.Drain(ctx)
+	}()
+
+	// Requeue jobs that were claimed but never started running
+	reaper := scheduler.NewReaper(
+		repo,
+		30*time.Second,
+		time.Duration(getEnvInt("SCHEDULED_STALE_SECONDS", 300))*time.Second,
+		m,
+	)
+	reaper.SetRequeueAlert(
+		getEnvInt("REAPER_ALERT_THRESHOLD", 0),
+		time.Duration(getEnvInt("REAPER_ALERT_WINDOW_SECONDS", 600))*time.Second,
+		nil,
+	)
+
+	// Periodic housekeeping shares one lifecycle
+	background := scheduler.NewBackgroundRunner()
+	if err := background.Register("reaper", reaper.Interval(), reaper.Run); err != nil {
+		slog.Error("Failed to register background task", "error", err)
+		os.Exit(1)
+	}
+	background.Start()
+	defer background.Stop()
 
 	// 7. Create HTTP handler and router
-	handler := api.NewHandler(jobService, m)
+	handler := api.NewHandler(jobService, executors, workers, m)
+	handler.SetAdmissionControl(
+		getEnvInt("QUEUE_HIGH_WATER_MARK", 0),
+		time.Duration(getEnvInt("QUEUE_RETRY_AFTER_SECONDS", 5))*time.Second,
+	)
+	var redactions api.Redactions
+	if raw := getEnv("RESULT_REDACTIONS", ""); raw != "" {
+		if err := json.Unmarshal([]byte(raw), &redactions); err != nil {
+			slog.Error("Invalid RESULT_REDACTIONS", "error", err)
+			os.Exit(1)
+		}
+	}
+	handler.SetRedactions(redactions)
+	strictStateFilter, _ := strconv.ParseBool(getEnv("STRICT_STATE_FILTER", "false"))
+	handler.SetStrictStateFilter(strictStateFilter)
+	handler.AddLivenessCheck("scheduler", sched)
+	handler.AddLivenessCheck("workers", workers)
+	handler.AddPauseControl("scheduling", sched)
+	handler.AddPauseControl("execution", workers)
+	handler.SetTypePauseControl(workers)
+	adminToken := getEnv("ADMIN_TOKEN", "")
 
 	router := http.NewServeMux()
 	router.HandleFunc("POST /api/v1/jobs", handler.CreateJob)
+	router.HandleFunc("POST /api/v1/jobs/batch", handler.CreateJobs)
+	router.HandleFunc("POST /api/v1/jobs/validate", handler.ValidateJob)
+	router.HandleFunc("POST /api/v1/jobs/states", handler.JobStates)
 	router.HandleFunc("GET /api/v1/jobs/{id}", handler.GetJob)
+	router.HandleFunc("GET /api/v1/jobs/{id}/errors", handler.GetJobErrors)
 	router.HandleFunc("GET /api/v1/jobs", handler.ListJobs)
+	router.HandleFunc("GET /api/v1/types", handler.ListTypes)
+	router.HandleFunc("GET /api/v1/queue", handler.PeekQueue)
+	router.HandleFunc("GET /api/v1/queue/oldest", handler.OldestPending)
+	router.HandleFunc("GET /api/v1/errors/top", handler.TopErrors)
+	router.HandleFunc("GET /api/v1/stats/matrix", handler.StatsMatrix)
 	router.HandleFunc("DELETE /api/v1/jobs/{id}", handler.CancelJob)
+	router.HandleFunc("POST /api/v1/jobs/{id}/retry", handler.RetryJob)
+	router.HandleFunc("POST /api/v1/jobs/{id}/rerun", handler.RerunJob)
+	router.HandleFunc("POST /api/v1/jobs/{id}/fail", handler.FailJob)
+	router.HandleFunc("PATCH /api/v1/jobs/{id}/max-attempts", handler.SetMaxAttempts)
+	router.Handle("POST /admin/executors", api.RequireAdminToken(adminToken, http.HandlerFunc(handler.RegisterExecutor)))
+	router.Handle("DELETE /admin/executors/{type}", api.RequireAdminToken(adminToken, http.HandlerFunc(handler.UnregisterExecutor)))
+	router.Handle("GET /admin/pause", api.RequireAdminToken(adminToken, http.HandlerFunc(handler.PauseStatus)))
+	router.Handle("POST /admin/pause/{name}", api.RequireAdminToken(adminToken, http.HandlerFunc(handler.Pause)))
+	router.Handle("POST /admin/resume/{name}", api.RequireAdminToken(adminToken, http.HandlerFunc(handler.Resume)))
+	router.Handle("POST /admin/pause/types/{type}", api.RequireAdminToken(adminToken, http.HandlerFunc(handler.PauseType)))
+	router.Handle("POST /admin/resume/types/{type}", api.RequireAdminToken(adminToken, http.HandlerFunc(handler.ResumeType)))
 	router.HandleFunc("GET /health", handler.Health)
+	router.HandleFunc("GET /healthz/detail", handler.HealthDetail)
 	router.Handle("GET /metrics", promhttp.Handler())
 
 	// 8. Create HTTP server
+	// Middleware chain: Recover is outermost so it covers every other
+	// middleware too; AccessLog logs a panicking request as the 500 it becomes
 	server := &http.Server{
 		Addr:    ":8080",
-		Handler: router,
+		Handler: api.Recover(api.AccessLog(api.RequestID(router))),
 	}
 
 	// 9. Start HTTP server in goroutine
 	go func() {
-		log.Println("HTTP server listening on :8080")
+		slog.Info("HTTP server listening", "addr", server.Addr)
 		if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
-			log.Fatalf("HTTP server error: %v", err)
+			slog.Error("HTTP server error", "error", err)
+			os.Exit(1)
 		}
 	}()
 
@@ -114,17 +261,17 @@ func main() {
 	signal.Notify(sigChan, os.Interrupt, syscall.SIGTERM)
 	<-sigChan
 
-	log.Println("Shutting down gracefully...")
+	slog.Info("Shutting down gracefully...")
 
 	// 11. Graceful shutdown
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	ctx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
 	defer cancel()
 
 	if err := server.Shutdown(ctx); err != nil {
-		log.Printf("Server shutdown error: %v", err)
+		slog.Error("Server shutdown error", "error", err)
 	}
 
-	log.Println("Shutdown complete")
+	slog.Info("Shutdown complete")
 }
 
 func getEnv(key, defaultValue string) string {
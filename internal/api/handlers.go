@@ -1,32 +1,151 @@
 package api
 
 import (
+	"context"
 	"encoding/json"
-	"log"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
 	"net/http"
+	"net/url"
 	"strconv"
+	"strings"
 	"time"
 
+	"github.com/dipak0000812/orchestrix/internal/executor"
+	"github.com/dipak0000812/orchestrix/internal/job/model"
+	"github.com/dipak0000812/orchestrix/internal/job/repository"
 	"github.com/dipak0000812/orchestrix/internal/job/service"
 	"github.com/dipak0000812/orchestrix/internal/job/state"
 	"github.com/dipak0000812/orchestrix/internal/metrics"
 )
 
+// JobAborter stops jobs that are executing locally.
+// Implemented by worker.WorkerPool.
+type JobAborter interface {
+	Abort(jobID string) bool
+}
+
+// LivenessChecker reports whether a background subsystem is still running.
+// Implemented by scheduler.Scheduler and worker.WorkerPool.
+type LivenessChecker interface {
+	Alive() bool
+	LastTick() time.Time
+}
+
+// Pauser is a background subsystem that can be paused and resumed.
+// Implemented by scheduler.Scheduler and worker.WorkerPool.
+type Pauser interface {
+	Pause()
+	Resume()
+	Paused() bool
+}
+
+// TypePauser pauses execution of individual job types.
+// Implemented by worker.WorkerPool.
+type TypePauser interface {
+	PauseType(jobType string)
+	ResumeType(jobType string)
+	PausedTypes() []string
+}
+
 // Handler holds dependencies for HTTP handlers.
 type Handler struct {
 	jobService *service.JobService
+	executors  *executor.ExecutorRegistry
+	aborter    JobAborter
 	metrics    *metrics.Metrics
+
+	// Admission control, disabled while highWaterMark is 0.
+	highWaterMark int
+	retryAfter    time.Duration
+
+	// redactions masks sensitive fields of job data in responses.
+	redactions Redactions
+
+	// strictStateFilter makes ListJobs require ?state= instead of
+	// defaulting to PENDING.
+	strictStateFilter bool
+
+	// liveness holds the subsystems reported by HealthDetail, by name.
+	liveness map[string]LivenessChecker
+
+	// pausers holds the subsystems the pause endpoints control, by name.
+	pausers map[string]Pauser
+
+	// typePauser backs the per-type pause endpoints; nil disables them.
+	typePauser TypePauser
 }
 
 // NewHandler creates a new API handler.
-func NewHandler(jobService *service.JobService, m *metrics.Metrics) *Handler {
+// aborter may be nil if no jobs execute in this process.
+func NewHandler(jobService *service.JobService, executors *executor.ExecutorRegistry, aborter JobAborter, m *metrics.Metrics) *Handler {
 	return &Handler{
 		jobService: jobService,
+		executors:  executors,
+		aborter:    aborter,
 		metrics:    m,
 	}
 }
 
+// SetRedactions sets the per-type JSON paths masked in job responses.
+// Nothing is redacted by default.
+func (h *Handler) SetRedactions(r Redactions) {
+	h.redactions = r
+}
+
+// SetStrictStateFilter makes ListJobs reject a missing or empty ?state=
+// with 400 rather than listing PENDING jobs. Off by default, since
+// existing clients may rely on the PENDING default. Queries filtering by
+// result are unaffected; they only ever match SUCCEEDED jobs.
+func (h *Handler) SetStrictStateFilter(strict bool) {
+	h.strictStateFilter = strict
+}
+
+// SetAdmissionControl makes CreateJob reject new jobs with 503 once
+// highWaterMark jobs are PENDING, telling clients to come back after
+// retryAfter. A highWaterMark of 0 disables it (the default).
+func (h *Handler) SetAdmissionControl(highWaterMark int, retryAfter time.Duration) {
+	h.highWaterMark = highWaterMark
+	h.retryAfter = retryAfter
+}
+
+// AddLivenessCheck reports c under name in HealthDetail.
+// Call it before serving requests.
+func (h *Handler) AddLivenessCheck(name string, c LivenessChecker) {
+	if h.liveness == nil {
+		h.liveness = make(map[string]LivenessChecker)
+	}
+	h.liveness[name] = c
+}
+
+// AddPauseControl lets the pause endpoints pause and resume p under name.
+// Call it before serving requests.
+func (h *Handler) AddPauseControl(name string, p Pauser) {
+	if h.pausers == nil {
+		h.pausers = make(map[string]Pauser)
+	}
+	h.pausers[name] = p
+}
+
+// SetTypePauseControl lets the pause endpoints pause and resume single
+// job types through p. Call it before serving requests.
+func (h *Handler) SetTypePauseControl(p TypePauser) {
+	h.typePauser = p
+}
+
+// CreateJob creates a job and returns it with 201. With ?wait=true it
+// instead blocks until the job finishes (200 with the final job) or the
+// ?timeout= elapses (202 with the job as it stands).
 func (h *Handler) CreateJob(w http.ResponseWriter, r *http.Request) {
+	wait, waitTimeout, err := parseWait(r.URL.Query())
+	if err != nil {
+		h.metrics.HTTPRequests.WithLabelValues("POST", "/api/v1/jobs", "400").Inc()
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
 	var req CreateJobRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		h.metrics.HTTPRequests.WithLabelValues("POST", "/api/v1/jobs", "400").Inc()
@@ -34,23 +153,273 @@ func (h *Handler) CreateJob(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if req.Type == "" {
+	if problems := h.validateCreateJob(req); len(problems) > 0 {
 		h.metrics.HTTPRequests.WithLabelValues("POST", "/api/v1/jobs", "400").Inc()
-		respondError(w, http.StatusBadRequest, "job type is required")
+		respondError(w, http.StatusBadRequest, strings.Join(problems, "; "))
+		return
+	}
+
+	if h.queueFull(r.Context()) {
+		h.metrics.HTTPRequests.WithLabelValues("POST", "/api/v1/jobs", "503").Inc()
+		w.Header().Set("Retry-After", strconv.Itoa(int(h.retryAfter.Seconds())))
+		respondError(w, http.StatusServiceUnavailable, "job queue is full, retry later")
 		return
 	}
 
-	job, err := h.jobService.CreateJob(r.Context(), req.Type, req.Payload)
+	job, err := h.jobService.CreateJobWithOptions(r.Context(), req.Type, req.Payload, req.options())
+	if errors.Is(err, repository.ErrDuplicateJob) {
+		h.metrics.HTTPRequests.WithLabelValues("POST", "/api/v1/jobs", "409").Inc()
+		respondError(w, http.StatusConflict, "job already exists")
+		return
+	}
+	if errors.Is(err, context.Canceled) {
+		// Client went away before the job was written; nobody to respond to
+		slog.Info("Job creation cancelled by client", "type", req.Type)
+		h.metrics.HTTPRequests.WithLabelValues("POST", "/api/v1/jobs", "499").Inc()
+		return
+	}
 	if err != nil {
-		log.Printf("Failed to create job: %v", err)
+		slog.Error("Failed to create job", "error", err)
 		h.metrics.HTTPRequests.WithLabelValues("POST", "/api/v1/jobs", "400").Inc()
 		respondError(w, http.StatusBadRequest, err.Error())
 		return
 	}
 
 	h.metrics.JobsCreated.Inc()
+	w.Header().Set("Location", "/api/v1/jobs/"+url.PathEscape(job.ID))
+	if wait {
+		h.waitForJob(w, r, job, waitTimeout)
+		return
+	}
+
 	h.metrics.HTTPRequests.WithLabelValues("POST", "/api/v1/jobs", "201").Inc()
-	respondJSON(w, http.StatusCreated, toJobResponse(job))
+	respondJSONFor(w, r, http.StatusCreated, h.jobResponse(job))
+}
+
+// Bounds for CreateJob's ?timeout= when waiting for the job to finish.
+const (
+	defaultWaitTimeout = 30 * time.Second
+	maxWaitTimeout     = 5 * time.Minute
+)
+
+// parseWait parses CreateJob's ?wait= and ?timeout= parameters.
+// The timeout only applies when waiting, and is capped at maxWaitTimeout.
+func parseWait(query url.Values) (wait bool, timeout time.Duration, err error) {
+	if param := query.Get("wait"); param != "" {
+		wait, err = strconv.ParseBool(param)
+		if err != nil {
+			return false, 0, errors.New("wait must be true or false")
+		}
+	}
+
+	timeout = defaultWaitTimeout
+	if param := query.Get("timeout"); param != "" {
+		timeout, err = time.ParseDuration(param)
+		if err != nil || timeout <= 0 {
+			return false, 0, errors.New("timeout must be a positive duration such as 30s")
+		}
+		timeout = min(timeout, maxWaitTimeout)
+	}
+
+	return wait, timeout, nil
+}
+
+// waitForJob responds to a ?wait=true CreateJob once the job finishes,
+// or with 202 and its current state once timeout elapses.
+func (h *Handler) waitForJob(w http.ResponseWriter, r *http.Request, job *model.Job, timeout time.Duration) {
+	ctx, cancel := context.WithTimeout(r.Context(), timeout)
+	defer cancel()
+
+	final, err := h.jobService.WaitForJob(ctx, job.ID)
+	switch {
+	case err == nil:
+		h.metrics.HTTPRequests.WithLabelValues("POST", "/api/v1/jobs", "200").Inc()
+		respondJSONFor(w, r, http.StatusOK, h.jobResponse(final))
+	case r.Context().Err() != nil:
+		// Client stopped waiting; the job carries on regardless
+		slog.Info("Client stopped waiting for job", "job_id", job.ID)
+		h.metrics.HTTPRequests.WithLabelValues("POST", "/api/v1/jobs", "499").Inc()
+	case errors.Is(err, context.DeadlineExceeded):
+		// A deadline hit mid-read leaves no job; the created one will do
+		if final == nil {
+			final = job
+		}
+		h.metrics.HTTPRequests.WithLabelValues("POST", "/api/v1/jobs", "202").Inc()
+		respondJSONFor(w, r, http.StatusAccepted, h.jobResponse(final))
+	default:
+		// The job was created; report it as accepted rather than lose its ID
+		slog.Error("Failed to wait for job", "job_id", job.ID, "error", err)
+		h.metrics.HTTPRequests.WithLabelValues("POST", "/api/v1/jobs", "202").Inc()
+		respondJSONFor(w, r, http.StatusAccepted, h.jobResponse(job))
+	}
+}
+
+// maxBatchJobs caps how many jobs one CreateJobs request may create.
+const maxBatchJobs = 1000
+
+// CreateJobs creates many jobs in one request. By default the batch is
+// atomic: if any job is invalid, nothing is created and the response is
+// 400. With ?best_effort=true the valid jobs are created anyway and the
+// response is 207 listing the failed items (201 if none failed).
+func (h *Handler) CreateJobs(w http.ResponseWriter, r *http.Request) {
+	var bestEffort bool
+	if param := r.URL.Query().Get("best_effort"); param != "" {
+		var err error
+		if bestEffort, err = strconv.ParseBool(param); err != nil {
+			h.metrics.HTTPRequests.WithLabelValues("POST", "/api/v1/jobs/batch", "400").Inc()
+			respondError(w, http.StatusBadRequest, "best_effort must be true or false")
+			return
+		}
+	}
+
+	var req CreateJobsRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.metrics.HTTPRequests.WithLabelValues("POST", "/api/v1/jobs/batch", "400").Inc()
+		respondError(w, http.StatusBadRequest, "invalid JSON body")
+		return
+	}
+
+	if len(req.Jobs) == 0 {
+		h.metrics.HTTPRequests.WithLabelValues("POST", "/api/v1/jobs/batch", "400").Inc()
+		respondError(w, http.StatusBadRequest, "jobs is required")
+		return
+	}
+	if len(req.Jobs) > maxBatchJobs {
+		h.metrics.HTTPRequests.WithLabelValues("POST", "/api/v1/jobs/batch", "400").Inc()
+		respondError(w, http.StatusBadRequest, "at most "+strconv.Itoa(maxBatchJobs)+" jobs per request")
+		return
+	}
+
+	if h.queueFull(r.Context()) {
+		h.metrics.HTTPRequests.WithLabelValues("POST", "/api/v1/jobs/batch", "503").Inc()
+		w.Header().Set("Retry-After", strconv.Itoa(int(h.retryAfter.Seconds())))
+		respondError(w, http.StatusServiceUnavailable, "job queue is full, retry later")
+		return
+	}
+
+	if bestEffort {
+		h.createJobsBestEffort(w, r, req.Jobs)
+		return
+	}
+
+	specs := make([]service.JobSpec, len(req.Jobs))
+	for i, item := range req.Jobs {
+		if problems := h.validateCreateJob(item); len(problems) > 0 {
+			h.metrics.HTTPRequests.WithLabelValues("POST", "/api/v1/jobs/batch", "400").Inc()
+			respondError(w, http.StatusBadRequest, fmt.Sprintf("job %d: %s", i, strings.Join(problems, "; ")))
+			return
+		}
+		specs[i] = service.JobSpec{Type: item.Type, Payload: item.Payload, Options: item.options()}
+	}
+
+	jobs, err := h.jobService.CreateJobs(r.Context(), specs)
+	if errors.Is(err, repository.ErrDuplicateJob) {
+		h.metrics.HTTPRequests.WithLabelValues("POST", "/api/v1/jobs/batch", "409").Inc()
+		respondError(w, http.StatusConflict, "job already exists")
+		return
+	}
+	if errors.Is(err, context.Canceled) {
+		slog.Info("Batch job creation cancelled by client", "count", len(specs))
+		h.metrics.HTTPRequests.WithLabelValues("POST", "/api/v1/jobs/batch", "499").Inc()
+		return
+	}
+	if err != nil {
+		slog.Error("Failed to create jobs", "count", len(specs), "error", err)
+		h.metrics.HTTPRequests.WithLabelValues("POST", "/api/v1/jobs/batch", "500").Inc()
+		respondError(w, http.StatusInternalServerError, "failed to create jobs")
+		return
+	}
+
+	h.metrics.JobsCreated.Add(float64(len(jobs)))
+	h.metrics.HTTPRequests.WithLabelValues("POST", "/api/v1/jobs/batch", "201").Inc()
+	respondJSONFor(w, r, http.StatusCreated, CreateJobsResponse{Jobs: h.jobResponses(jobs)})
+}
+
+// createJobsBestEffort creates each valid job on its own, so one bad item
+// doesn't sink the batch, and reports the rest by index.
+func (h *Handler) createJobsBestEffort(w http.ResponseWriter, r *http.Request, items []CreateJobRequest) {
+	resp := CreateJobsResponse{Jobs: []JobResponse{}}
+	for i, item := range items {
+		if problems := h.validateCreateJob(item); len(problems) > 0 {
+			resp.Errors = append(resp.Errors, BatchItemError{Index: i, Error: strings.Join(problems, "; ")})
+			continue
+		}
+
+		job, err := h.jobService.CreateJobWithOptions(r.Context(), item.Type, item.Payload, item.options())
+		if err != nil {
+			slog.Error("Failed to create job in batch", "index", i, "type", item.Type, "error", err)
+			resp.Errors = append(resp.Errors, BatchItemError{Index: i, Error: err.Error()})
+			continue
+		}
+		resp.Jobs = append(resp.Jobs, h.jobResponse(job))
+	}
+
+	h.metrics.JobsCreated.Add(float64(len(resp.Jobs)))
+
+	status := http.StatusCreated
+	if len(resp.Errors) > 0 {
+		status = http.StatusMultiStatus
+	}
+	h.metrics.HTTPRequests.WithLabelValues("POST", "/api/v1/jobs/batch", strconv.Itoa(status)).Inc()
+	respondJSONFor(w, r, status, resp)
+}
+
+// queueFull reports whether admission control should turn new jobs away.
+// If the backlog can't be counted, jobs are accepted rather than rejected.
+func (h *Handler) queueFull(ctx context.Context) bool {
+	if h.highWaterMark <= 0 {
+		return false
+	}
+
+	pending, err := h.jobService.CountJobsByState(ctx, state.PENDING)
+	if err != nil {
+		slog.Error("Failed to count pending jobs", "error", err)
+		return false
+	}
+	return pending >= h.highWaterMark
+}
+
+// ValidateJob is a dry run of CreateJob: it runs the same validation
+// and reports the result without writing anything.
+func (h *Handler) ValidateJob(w http.ResponseWriter, r *http.Request) {
+	var req CreateJobRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondJSONFor(w, r, http.StatusBadRequest, ValidateJobResponse{
+			Valid:  false,
+			Errors: []string{"invalid JSON body"},
+		})
+		return
+	}
+
+	if problems := h.validateCreateJob(req); len(problems) > 0 {
+		respondJSONFor(w, r, http.StatusBadRequest, ValidateJobResponse{
+			Valid:  false,
+			Errors: problems,
+		})
+		return
+	}
+
+	respondJSONFor(w, r, http.StatusOK, ValidateJobResponse{Valid: true})
+}
+
+// validateCreateJob runs the checks shared by CreateJob and ValidateJob,
+// so the dry run can't drift from real creation. Returns every problem found.
+func (h *Handler) validateCreateJob(req CreateJobRequest) []string {
+	var problems []string
+
+	if req.Type == "" {
+		return append(problems, "job type is required")
+	}
+
+	if !h.executors.Has(req.Type) {
+		problems = append(problems, "no executor registered for job type: "+req.Type)
+	}
+
+	if err := h.jobService.ValidateJob(req.Type, req.Payload, req.options()); err != nil {
+		problems = append(problems, err.Error())
+	}
+
+	return problems
 }
 
 func (h *Handler) GetJob(w http.ResponseWriter, r *http.Request) {
@@ -60,26 +429,145 @@ func (h *Handler) GetJob(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	includeHistory, includeErrors, err := parseJobIncludes(r.URL.Query().Get("include"))
+	if err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
 	job, err := h.jobService.GetJob(r.Context(), id)
 	if err != nil {
-		log.Printf("Failed to get job %s: %v", id, err)
+		slog.Error("Failed to get job", "job_id", id, "error", err)
+		respondError(w, http.StatusNotFound, "job not found")
+		return
+	}
+
+	if !includeHistory && !includeErrors {
+		respondJSONFor(w, r, http.StatusOK, h.jobResponse(job))
+		return
+	}
+
+	// History is partly reconstructed from the error list, so both need it
+	attemptErrs, err := h.jobService.ListJobErrors(r.Context(), id)
+	if err != nil {
+		slog.Error("Failed to get job errors", "job_id", id, "error", err)
+		respondError(w, http.StatusInternalServerError, "failed to get job errors")
+		return
+	}
+
+	resp := ExpandedJobResponse{JobResponse: h.jobResponse(job)}
+	if includeHistory {
+		resp.History = toJobHistory(job, attemptErrs)
+	}
+	if includeErrors {
+		resp.Errors = toJobErrorResponses(attemptErrs)
+	}
+
+	respondJSONFor(w, r, http.StatusOK, resp)
+}
+
+// parseJobIncludes parses a comma-separated ?include= value for GetJob.
+func parseJobIncludes(param string) (history, errs bool, err error) {
+	if param == "" {
+		return false, false, nil
+	}
+
+	for _, include := range strings.Split(param, ",") {
+		switch strings.TrimSpace(include) {
+		case "history":
+			history = true
+		case "errors":
+			errs = true
+		default:
+			return false, false, errors.New("unknown include: " + include)
+		}
+	}
+	return history, errs, nil
+}
+
+// resultFilterPrefix marks ListJobs query parameters that filter on the job result.
+const resultFilterPrefix = "result."
+
+// parseResultFilter finds a ?result.<key>=<value> filter for ListJobs.
+// At most one result filter is supported.
+func parseResultFilter(query url.Values) (key, value string, ok bool, err error) {
+	for param, values := range query {
+		if !strings.HasPrefix(param, resultFilterPrefix) {
+			continue
+		}
+		if ok {
+			return "", "", false, errors.New("only one result filter is supported")
+		}
+		key = strings.TrimPrefix(param, resultFilterPrefix)
+		if key == "" {
+			return "", "", false, errors.New("result filter key is required")
+		}
+		value, ok = values[0], true
+	}
+	return key, value, ok, nil
+}
+
+// maxJobStatesIDs caps how many jobs one JobStates request may look up.
+const maxJobStatesIDs = 1000
+
+// JobStates returns the current state of many jobs at once, keyed by ID,
+// for clients polling a batch. Unknown IDs are left out of the response.
+func (h *Handler) JobStates(w http.ResponseWriter, r *http.Request) {
+	var req JobStatesRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, http.StatusBadRequest, "invalid JSON body")
+		return
+	}
+
+	if len(req.IDs) == 0 {
+		respondError(w, http.StatusBadRequest, "ids is required")
+		return
+	}
+	if len(req.IDs) > maxJobStatesIDs {
+		respondError(w, http.StatusBadRequest, "at most "+strconv.Itoa(maxJobStatesIDs)+" ids per request")
+		return
+	}
+
+	states, err := h.jobService.GetJobStates(r.Context(), req.IDs)
+	if err != nil {
+		slog.Error("Failed to get job states", "error", err)
+		respondError(w, http.StatusInternalServerError, "failed to get job states")
+		return
+	}
+
+	respondJSONFor(w, r, http.StatusOK, states)
+}
+
+func (h *Handler) GetJobErrors(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	if id == "" {
+		respondError(w, http.StatusBadRequest, "job ID is required")
+		return
+	}
+
+	attemptErrs, err := h.jobService.ListJobErrors(r.Context(), id)
+	if err != nil {
+		slog.Error("Failed to get job errors", "job_id", id, "error", err)
 		respondError(w, http.StatusNotFound, "job not found")
 		return
 	}
 
-	respondJSON(w, http.StatusOK, toJobResponse(job))
+	respondJSONFor(w, r, http.StatusOK, ListJobErrorsResponse{
+		Errors: toJobErrorResponses(attemptErrs),
+	})
 }
 
 func (h *Handler) ListJobs(w http.ResponseWriter, r *http.Request) {
 	stateParam := r.URL.Query().Get("state")
 	limitParam := r.URL.Query().Get("limit")
 
-	limit := 10
+	limit := 0
 	if limitParam != "" {
 		if parsed, err := strconv.Atoi(limitParam); err == nil && parsed > 0 {
 			limit = parsed
 		}
 	}
+	limit = h.jobService.ListLimit(limit)
 
 	jobState := state.PENDING
 	if stateParam != "" {
@@ -90,24 +578,136 @@ func (h *Handler) ListJobs(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
-	jobs, err := h.jobService.ListJobsByState(r.Context(), jobState, limit)
+	order := repository.SortAsc
+	switch r.URL.Query().Get("order") {
+	case "", "asc":
+	case "desc":
+		order = repository.SortDesc
+	default:
+		respondError(w, http.StatusBadRequest, "order must be asc or desc")
+		return
+	}
+
+	resultKey, resultValue, byResult, err := parseResultFilter(r.URL.Query())
 	if err != nil {
-		log.Printf("Failed to list jobs: %v", err)
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if h.strictStateFilter && stateParam == "" && !byResult {
+		respondError(w, http.StatusBadRequest, "state parameter is required")
+		return
+	}
+	if byResult && stateParam != "" && jobState != state.SUCCEEDED {
+		respondError(w, http.StatusBadRequest, "result filters only match SUCCEEDED jobs")
+		return
+	}
+
+	var jobs []*model.Job
+	if byResult {
+		jobs, err = h.jobService.ListJobsByResult(r.Context(), resultKey, resultValue, limit)
+	} else {
+		jobs, err = h.jobService.ListJobsByStateOrdered(r.Context(), jobState, limit, order)
+	}
+	if err != nil {
+		slog.Error("Failed to list jobs", "error", err)
 		respondError(w, http.StatusInternalServerError, "failed to list jobs")
 		return
 	}
 
-	jobResponses := make([]JobResponse, len(jobs))
-	for i, job := range jobs {
-		jobResponses[i] = toJobResponse(job)
+	jobResponses := h.jobResponses(jobs)
+
+	respondJSONFor(w, r, http.StatusOK, ListJobsResponse{
+		Jobs:  jobResponses,
+		Total: len(jobResponses),
+		Limit: limit,
+	})
+}
+
+// PeekQueue lists the jobs the scheduler will claim next, in claim order.
+// Read-only: nothing is claimed or locked.
+func (h *Handler) PeekQueue(w http.ResponseWriter, r *http.Request) {
+	limit := 0
+	if limitParam := r.URL.Query().Get("limit"); limitParam != "" {
+		if parsed, err := strconv.Atoi(limitParam); err == nil && parsed > 0 {
+			limit = parsed
+		}
+	}
+	limit = h.jobService.ListLimit(limit)
+
+	jobs, err := h.jobService.PeekQueue(r.Context(), limit)
+	if err != nil {
+		slog.Error("Failed to peek queue", "error", err)
+		respondError(w, http.StatusInternalServerError, "failed to peek queue")
+		return
 	}
 
-	respondJSON(w, http.StatusOK, ListJobsResponse{
+	jobResponses := h.jobResponses(jobs)
+
+	respondJSONFor(w, r, http.StatusOK, ListJobsResponse{
 		Jobs:  jobResponses,
 		Total: len(jobResponses),
+		Limit: limit,
 	})
 }
 
+// OldestPending reports how long the oldest due PENDING job has been
+// waiting, a single number to alert on for queue health.
+func (h *Handler) OldestPending(w http.ResponseWriter, r *http.Request) {
+	age, err := h.jobService.OldestPendingAge(r.Context())
+	if err != nil {
+		slog.Error("Failed to get oldest pending job", "error", err)
+		respondError(w, http.StatusInternalServerError, "failed to get oldest pending job")
+		return
+	}
+
+	respondJSONFor(w, r, http.StatusOK, OldestPendingResponse{AgeSeconds: age.Seconds()})
+}
+
+// StatsMatrix returns how many jobs of each type are in each state,
+// for a types × states dashboard grid.
+func (h *Handler) StatsMatrix(w http.ResponseWriter, r *http.Request) {
+	counts, err := h.jobService.CountJobsByTypeAndState(r.Context())
+	if err != nil {
+		slog.Error("Failed to count jobs by type and state", "error", err)
+		respondError(w, http.StatusInternalServerError, "failed to count jobs")
+		return
+	}
+
+	respondJSONFor(w, r, http.StatusOK, StatsMatrixResponse{Counts: counts})
+}
+
+// ListTypes returns the job types that currently have jobs stored.
+// Executor-registered types with no jobs yet are not included.
+func (h *Handler) ListTypes(w http.ResponseWriter, r *http.Request) {
+	types, err := h.jobService.ListJobTypes(r.Context())
+	if err != nil {
+		slog.Error("Failed to list job types", "error", err)
+		respondError(w, http.StatusInternalServerError, "failed to list job types")
+		return
+	}
+
+	respondJSONFor(w, r, http.StatusOK, ListTypesResponse{Types: types})
+}
+
+// TopErrors lists the most common error messages among FAILED jobs,
+// most frequent first. ?limit= caps how many messages are returned.
+func (h *Handler) TopErrors(w http.ResponseWriter, r *http.Request) {
+	limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+
+	counts, err := h.jobService.TopErrors(r.Context(), limit)
+	if err != nil {
+		slog.Error("Failed to list top errors", "error", err)
+		respondError(w, http.StatusInternalServerError, "failed to list top errors")
+		return
+	}
+
+	resp := make([]ErrorCountResponse, len(counts))
+	for i, count := range counts {
+		resp[i] = ErrorCountResponse{Error: count.Error, Count: count.Count}
+	}
+	respondJSONFor(w, r, http.StatusOK, resp)
+}
+
 func (h *Handler) CancelJob(w http.ResponseWriter, r *http.Request) {
 	id := r.PathValue("id")
 	if id == "" {
@@ -116,7 +716,7 @@ func (h *Handler) CancelJob(w http.ResponseWriter, r *http.Request) {
 	}
 
 	if err := h.jobService.CancelJob(r.Context(), id); err != nil {
-		log.Printf("Failed to cancel job %s: %v", id, err)
+		slog.Error("Failed to cancel job", "job_id", id, "error", err)
 		respondError(w, http.StatusBadRequest, err.Error())
 		return
 	}
@@ -125,17 +725,296 @@ func (h *Handler) CancelJob(w http.ResponseWriter, r *http.Request) {
 	w.WriteHeader(http.StatusNoContent)
 }
 
+func (h *Handler) RetryJob(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	if id == "" {
+		respondError(w, http.StatusBadRequest, "job ID is required")
+		return
+	}
+
+	// Body is optional; an empty body means no additional attempts
+	var req RetryJobRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && !errors.Is(err, io.EOF) {
+		respondError(w, http.StatusBadRequest, "invalid JSON body")
+		return
+	}
+
+	job, err := h.jobService.RequeueJob(r.Context(), id, req.AdditionalAttempts)
+	if err != nil {
+		slog.Error("Failed to retry job", "job_id", id, "error", err)
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	respondJSONFor(w, r, http.StatusOK, h.jobResponse(job))
+}
+
+// RerunJob runs a finished job again with the same payload.
+func (h *Handler) RerunJob(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	if id == "" {
+		respondError(w, http.StatusBadRequest, "job ID is required")
+		return
+	}
+
+	job, err := h.jobService.RerunJob(r.Context(), id)
+	if err != nil {
+		slog.Error("Failed to rerun job", "job_id", id, "error", err)
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	respondJSONFor(w, r, http.StatusOK, h.jobResponse(job))
+}
+
+// SetMaxAttempts changes the attempt budget of a job that hasn't finished.
+func (h *Handler) SetMaxAttempts(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	if id == "" {
+		respondError(w, http.StatusBadRequest, "job ID is required")
+		return
+	}
+
+	var req SetMaxAttemptsRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, http.StatusBadRequest, "invalid JSON body")
+		return
+	}
+
+	job, err := h.jobService.SetMaxAttempts(r.Context(), id, req.MaxAttempts)
+	if err != nil {
+		slog.Error("Failed to set max attempts", "job_id", id, "error", err)
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	respondJSONFor(w, r, http.StatusOK, h.jobResponse(job))
+}
+
+// FailJob force-fails a stuck job and aborts it if it is executing locally.
+func (h *Handler) FailJob(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	if id == "" {
+		respondError(w, http.StatusBadRequest, "job ID is required")
+		return
+	}
+
+	// Body is optional; an empty body uses the default reason
+	var req FailJobRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && !errors.Is(err, io.EOF) {
+		respondError(w, http.StatusBadRequest, "invalid JSON body")
+		return
+	}
+	if req.Reason == "" {
+		req.Reason = "force-failed via API"
+	}
+
+	job, err := h.jobService.ForceFail(r.Context(), id, req.Reason)
+	if err != nil {
+		slog.Error("Failed to force-fail job", "job_id", id, "error", err)
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if h.aborter != nil && h.aborter.Abort(id) {
+		slog.Info("Aborted local execution of job", "job_id", id)
+	}
+
+	h.metrics.JobsFailed.Inc()
+	respondJSONFor(w, r, http.StatusOK, h.jobResponse(job))
+}
+
+// RegisterExecutor registers an HTTP callback executor for a job type at runtime.
+func (h *Handler) RegisterExecutor(w http.ResponseWriter, r *http.Request) {
+	var req RegisterExecutorRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, http.StatusBadRequest, "invalid JSON body")
+		return
+	}
+
+	if req.Type == "" {
+		respondError(w, http.StatusBadRequest, "executor type is required")
+		return
+	}
+
+	callbackURL, err := url.Parse(req.URL)
+	if err != nil || (callbackURL.Scheme != "http" && callbackURL.Scheme != "https") || callbackURL.Host == "" {
+		respondError(w, http.StatusBadRequest, "url must be an absolute http(s) URL")
+		return
+	}
+
+	if !h.executors.RegisterIfAbsent(req.Type, executor.NewHTTPExecutor(req.URL, nil)) {
+		respondError(w, http.StatusConflict, "executor already registered for type: "+req.Type)
+		return
+	}
+
+	slog.Info("Registered HTTP executor", "type", req.Type, "url", req.URL)
+	respondJSONFor(w, r, http.StatusCreated, ExecutorResponse{
+		Type: req.Type,
+		URL:  req.URL,
+	})
+}
+
+// UnregisterExecutor removes the executor for a job type at runtime.
+func (h *Handler) UnregisterExecutor(w http.ResponseWriter, r *http.Request) {
+	jobType := r.PathValue("type")
+	if jobType == "" {
+		respondError(w, http.StatusBadRequest, "executor type is required")
+		return
+	}
+
+	if !h.executors.Unregister(jobType) {
+		respondError(w, http.StatusNotFound, "no executor registered for type: "+jobType)
+		return
+	}
+
+	slog.Info("Unregistered executor", "type", jobType)
+	w.WriteHeader(http.StatusNoContent)
+}
+
 func (h *Handler) Health(w http.ResponseWriter, r *http.Request) {
-	respondJSON(w, http.StatusOK, HealthResponse{
+	respondJSONFor(w, r, http.StatusOK, HealthResponse{
 		Status:    "healthy",
-		Timestamp: time.Now().Format(time.RFC3339),
+		Timestamp: newTimestamp(time.Now()),
 	})
 }
 
+// PauseStatus reports which subsystems are paused.
+func (h *Handler) PauseStatus(w http.ResponseWriter, r *http.Request) {
+	respondJSONFor(w, r, http.StatusOK, h.pauseStatus())
+}
+
+// Pause pauses the subsystem named in the path. Work already in progress
+// finishes; the subsystem just stops picking up more.
+func (h *Handler) Pause(w http.ResponseWriter, r *http.Request) {
+	h.setPaused(w, r, true)
+}
+
+// Resume resumes the subsystem named in the path.
+func (h *Handler) Resume(w http.ResponseWriter, r *http.Request) {
+	h.setPaused(w, r, false)
+}
+
+func (h *Handler) setPaused(w http.ResponseWriter, r *http.Request, paused bool) {
+	name := r.PathValue("name")
+	p, ok := h.pausers[name]
+	if !ok {
+		respondError(w, http.StatusNotFound, "unknown subsystem: "+name)
+		return
+	}
+
+	if paused {
+		p.Pause()
+	} else {
+		p.Resume()
+	}
+	respondJSONFor(w, r, http.StatusOK, h.pauseStatus())
+}
+
+// PauseType stops executing jobs of the type named in the path while other
+// types carry on. Jobs of the type already executing finish.
+func (h *Handler) PauseType(w http.ResponseWriter, r *http.Request) {
+	h.setTypePaused(w, r, true)
+}
+
+// ResumeType resumes the job type named in the path.
+func (h *Handler) ResumeType(w http.ResponseWriter, r *http.Request) {
+	h.setTypePaused(w, r, false)
+}
+
+func (h *Handler) setTypePaused(w http.ResponseWriter, r *http.Request, paused bool) {
+	if h.typePauser == nil {
+		respondError(w, http.StatusNotFound, "job types can't be paused in this process")
+		return
+	}
+
+	jobType := r.PathValue("type")
+	if paused {
+		h.typePauser.PauseType(jobType)
+	} else {
+		h.typePauser.ResumeType(jobType)
+	}
+	respondJSONFor(w, r, http.StatusOK, h.pauseStatus())
+}
+
+func (h *Handler) pauseStatus() PauseStatusResponse {
+	resp := PauseStatusResponse{Paused: make(map[string]bool, len(h.pausers))}
+	for name, p := range h.pausers {
+		resp.Paused[name] = p.Paused()
+	}
+	if h.typePauser != nil {
+		resp.PausedTypes = h.typePauser.PausedTypes()
+	}
+	return resp
+}
+
+// HealthDetail reports the liveness of each background subsystem.
+// Responds 503 if any of them has stopped, so load balancers can act on it.
+func (h *Handler) HealthDetail(w http.ResponseWriter, r *http.Request) {
+	resp := HealthDetailResponse{
+		Status:     "healthy",
+		Timestamp:  newTimestamp(time.Now()),
+		Subsystems: make(map[string]SubsystemHealth, len(h.liveness)),
+	}
+
+	status := http.StatusOK
+	for name, c := range h.liveness {
+		health := SubsystemHealth{Alive: c.Alive()}
+		if last := c.LastTick(); !last.IsZero() {
+			ts := newTimestamp(last)
+			health.LastTick = &ts
+		}
+		if !health.Alive {
+			resp.Status = "unhealthy"
+			status = http.StatusServiceUnavailable
+		}
+		resp.Subsystems[name] = health
+	}
+
+	respondJSONFor(w, r, status, resp)
+}
+
+// jobResponse converts job to its response, with the fields the job
+// service derives filled in.
+func (h *Handler) jobResponse(job *model.Job) JobResponse {
+	resp := toJobResponse(job, h.redactions)
+	if delay, ok := h.jobService.RetryDelay(job); ok {
+		seconds := delay.Seconds()
+		resp.NextRetryInSeconds = &seconds
+	}
+	return resp
+}
+
+// jobResponses converts a list of jobs with jobResponse.
+// Always returns a non-nil slice so it marshals as [] rather than null.
+func (h *Handler) jobResponses(jobs []*model.Job) []JobResponse {
+	responses := make([]JobResponse, len(jobs))
+	for i, job := range jobs {
+		responses[i] = h.jobResponse(job)
+	}
+	return responses
+}
+
 func respondJSON(w http.ResponseWriter, status int, data interface{}) {
+	writeJSON(w, status, data, false)
+}
+
+// respondJSONFor is respondJSON with opt-in pretty-printing via ?pretty=true,
+// handy when reading responses with curl. Compact stays the default.
+func respondJSONFor(w http.ResponseWriter, r *http.Request, status int, data interface{}) {
+	pretty, _ := strconv.ParseBool(r.URL.Query().Get("pretty"))
+	writeJSON(w, status, data, pretty)
+}
+
+func writeJSON(w http.ResponseWriter, status int, data interface{}, pretty bool) {
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(status)
-	json.NewEncoder(w).Encode(data)
+
+	encoder := json.NewEncoder(w)
+	if pretty {
+		encoder.SetIndent("", "  ")
+	}
+	encoder.Encode(data)
 }
 
 func respondError(w http.ResponseWriter, status int, message string) {
@@ -0,0 +1,1160 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dipak0000812/orchestrix/internal/executor"
+	"github.com/dipak0000812/orchestrix/internal/job/model"
+	"github.com/dipak0000812/orchestrix/internal/job/repository"
+	"github.com/dipak0000812/orchestrix/internal/job/service"
+	"github.com/dipak0000812/orchestrix/internal/job/state"
+	"github.com/dipak0000812/orchestrix/internal/metrics"
+	"github.com/dipak0000812/orchestrix/internal/scheduler"
+	"github.com/dipak0000812/orchestrix/internal/worker"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// newTestMetrics returns metrics on a fresh registry, isolated from other tests.
+func newTestMetrics() *metrics.Metrics {
+	return metrics.NewMetrics(prometheus.NewRegistry())
+}
+
+// setupTestHandler creates a handler backed by the in-memory repository.
+func setupTestHandler() (*Handler, *service.JobService) {
+	jobService := service.NewJobService(
+		repository.NewMemoryJobRepository(),
+		state.NewStateMachine(),
+		service.NewULIDGenerator(),
+		service.DefaultRetryConfig(),
+	)
+
+	executors := executor.NewExecutorRegistry()
+	executors.Register("test_job", executor.NewDemoExecutor(0))
+
+	return NewHandler(jobService, executors, nil, newTestMetrics()), jobService
+}
+
+func TestGetJobErrors(t *testing.T) {
+	handler, jobService := setupTestHandler()
+	ctx := context.Background()
+
+	job, _ := jobService.CreateJob(ctx, "test_job", []byte(`{}`))
+
+	// Fail once, then succeed on retry
+	jobService.TransitionState(ctx, job.ID, state.SCHEDULED)
+	jobService.TransitionState(ctx, job.ID, state.RUNNING)
+	jobService.HandleFailure(ctx, job.ID, errors.New("upstream timeout"))
+	jobService.TransitionState(ctx, job.ID, state.SCHEDULED)
+	jobService.TransitionState(ctx, job.ID, state.RUNNING)
+	jobService.TransitionState(ctx, job.ID, state.SUCCEEDED)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/jobs/"+job.ID+"/errors", nil)
+	req.SetPathValue("id", job.ID)
+	rec := httptest.NewRecorder()
+	handler.GetJobErrors(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Status = %d, want 200", rec.Code)
+	}
+
+	var resp ListJobErrorsResponse
+	if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+
+	if len(resp.Errors) != 1 {
+		t.Fatalf("Expected 1 error, got %d", len(resp.Errors))
+	}
+	if resp.Errors[0].Attempt != 1 || resp.Errors[0].Error != "upstream timeout" {
+		t.Errorf("Error = %+v, want attempt 1 \"upstream timeout\"", resp.Errors[0])
+	}
+}
+
+func TestGetJobErrors_NeverErrored(t *testing.T) {
+	handler, jobService := setupTestHandler()
+
+	job, _ := jobService.CreateJob(context.Background(), "test_job", []byte(`{}`))
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/jobs/"+job.ID+"/errors", nil)
+	req.SetPathValue("id", job.ID)
+	rec := httptest.NewRecorder()
+	handler.GetJobErrors(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Status = %d, want 200", rec.Code)
+	}
+
+	want := `{"errors":[]}`
+	if got := rec.Body.String(); got != want+"\n" {
+		t.Errorf("Body = %q, want %q", got, want)
+	}
+}
+
+func TestGetJobErrors_NotFound(t *testing.T) {
+	handler, _ := setupTestHandler()
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/jobs/missing/errors", nil)
+	req.SetPathValue("id", "missing")
+	rec := httptest.NewRecorder()
+	handler.GetJobErrors(rec, req)
+
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("Status = %d, want 404", rec.Code)
+	}
+}
+
+func TestGetJob_IncludeHistoryAndErrors(t *testing.T) {
+	handler, jobService := setupTestHandler()
+	ctx := context.Background()
+
+	job, _ := jobService.CreateJob(ctx, "test_job", []byte(`{}`))
+
+	// Fail once, then succeed on retry
+	jobService.TransitionState(ctx, job.ID, state.SCHEDULED)
+	jobService.TransitionState(ctx, job.ID, state.RUNNING)
+	jobService.HandleFailure(ctx, job.ID, errors.New("upstream timeout"))
+	jobService.TransitionState(ctx, job.ID, state.SCHEDULED)
+	jobService.TransitionState(ctx, job.ID, state.RUNNING)
+	jobService.TransitionState(ctx, job.ID, state.SUCCEEDED)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/jobs/"+job.ID+"?include=history,errors", nil)
+	req.SetPathValue("id", job.ID)
+	rec := httptest.NewRecorder()
+	handler.GetJob(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Status = %d, want 200: %s", rec.Code, rec.Body.String())
+	}
+
+	var resp ExpandedJobResponse
+	if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+
+	if resp.ID != job.ID || resp.State != string(state.SUCCEEDED) {
+		t.Errorf("Job = %s/%s, want %s/SUCCEEDED", resp.ID, resp.State, job.ID)
+	}
+
+	if len(resp.Errors) != 1 || resp.Errors[0].Error != "upstream timeout" {
+		t.Errorf("Errors = %+v, want one \"upstream timeout\"", resp.Errors)
+	}
+
+	wantStates := []state.State{state.PENDING, state.RETRYING, state.SCHEDULED, state.RUNNING, state.SUCCEEDED}
+	if len(resp.History) != len(wantStates) {
+		t.Fatalf("History = %+v, want states %v", resp.History, wantStates)
+	}
+	for i, want := range wantStates {
+		if resp.History[i].State != string(want) {
+			t.Errorf("History[%d].State = %s, want %s", i, resp.History[i].State, want)
+		}
+		if i > 0 && resp.History[i].At.Before(resp.History[i-1].At.Time) {
+			t.Errorf("History[%d] is out of order", i)
+		}
+	}
+}
+
+func TestGetJob_IncludeOmitted(t *testing.T) {
+	handler, jobService := setupTestHandler()
+
+	job, _ := jobService.CreateJob(context.Background(), "test_job", []byte(`{}`))
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/jobs/"+job.ID, nil)
+	req.SetPathValue("id", job.ID)
+	rec := httptest.NewRecorder()
+	handler.GetJob(rec, req)
+
+	var body map[string]any
+	if err := json.NewDecoder(rec.Body).Decode(&body); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	for _, key := range []string{"history", "errors"} {
+		if _, ok := body[key]; ok {
+			t.Errorf("Base response should not contain %q", key)
+		}
+	}
+}
+
+func TestGetJob_NextRetryInSeconds(t *testing.T) {
+	handler, jobService := setupTestHandler()
+	ctx := context.Background()
+
+	job, _ := jobService.CreateJob(ctx, "test_job", []byte(`{}`))
+
+	getJob := func() JobResponse {
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/jobs/"+job.ID, nil)
+		req.SetPathValue("id", job.ID)
+		rec := httptest.NewRecorder()
+		handler.GetJob(rec, req)
+
+		var resp JobResponse
+		json.NewDecoder(rec.Body).Decode(&resp)
+		return resp
+	}
+
+	if resp := getJob(); resp.NextRetryInSeconds != nil {
+		t.Errorf("NextRetryInSeconds = %v for a PENDING job, want none", *resp.NextRetryInSeconds)
+	}
+
+	jobService.TransitionState(ctx, job.ID, state.SCHEDULED)
+	jobService.TransitionState(ctx, job.ID, state.RUNNING)
+	jobService.HandleFailure(ctx, job.ID, errors.New("boom"))
+
+	want, _ := jobService.NextRetryDelay(ctx, job.ID)
+	resp := getJob()
+	if resp.NextRetryInSeconds == nil || *resp.NextRetryInSeconds != want.Seconds() {
+		t.Errorf("NextRetryInSeconds = %v, want %v", resp.NextRetryInSeconds, want.Seconds())
+	}
+}
+
+func TestGetJob_IncludeUnknown(t *testing.T) {
+	handler, jobService := setupTestHandler()
+
+	job, _ := jobService.CreateJob(context.Background(), "test_job", []byte(`{}`))
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/jobs/"+job.ID+"?include=payload", nil)
+	req.SetPathValue("id", job.ID)
+	rec := httptest.NewRecorder()
+	handler.GetJob(rec, req)
+
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("Status = %d, want 400", rec.Code)
+	}
+}
+
+func TestListJobs_Empty(t *testing.T) {
+	handler, _ := setupTestHandler()
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/jobs?state=SUCCEEDED", nil)
+	rec := httptest.NewRecorder()
+	handler.ListJobs(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Status = %d, want 200", rec.Code)
+	}
+
+	want := `{"jobs":[],"total":0,"limit":10}`
+	if got := rec.Body.String(); got != want+"\n" {
+		t.Errorf("Body = %q, want %q", got, want)
+	}
+}
+
+func TestListJobs_StrictStateFilter(t *testing.T) {
+	handler, jobService := setupTestHandler()
+	jobService.CreateJob(context.Background(), "test_job", []byte(`{}`))
+
+	listJobs := func(query string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/jobs"+query, nil)
+		rec := httptest.NewRecorder()
+		handler.ListJobs(rec, req)
+		return rec
+	}
+
+	// By default an empty state lists PENDING jobs
+	if rec := listJobs("?state="); rec.Code != http.StatusOK {
+		t.Fatalf("Status without strict mode = %d, want 200", rec.Code)
+	}
+
+	handler.SetStrictStateFilter(true)
+	for _, query := range []string{"?state=", ""} {
+		rec := listJobs(query)
+		if rec.Code != http.StatusBadRequest {
+			t.Errorf("Status for %q in strict mode = %d, want 400", query, rec.Code)
+		}
+	}
+	if rec := listJobs("?state=PENDING"); rec.Code != http.StatusOK {
+		t.Errorf("Status for an explicit state in strict mode = %d, want 200", rec.Code)
+	}
+}
+
+func TestListJobs_Order(t *testing.T) {
+	repo := repository.NewMemoryJobRepository()
+	jobService := service.NewJobService(repo, state.NewStateMachine(), service.NewULIDGenerator(), service.DefaultRetryConfig())
+	handler := NewHandler(jobService, executor.NewExecutorRegistry(), nil, newTestMetrics())
+	now := time.Now()
+
+	for i, id := range []string{"job_old", "job_mid", "job_new"} {
+		repo.Create(context.Background(), &model.Job{
+			ID:          id,
+			Type:        "test_job",
+			Payload:     []byte(`{}`),
+			State:       state.PENDING,
+			Attempt:     1,
+			MaxAttempts: 3,
+			CreatedAt:   now.Add(time.Duration(i) * time.Minute),
+		})
+	}
+
+	tests := []struct {
+		query     string
+		wantFirst string
+	}{
+		{"?state=PENDING", "job_old"},
+		{"?state=PENDING&order=asc", "job_old"},
+		{"?state=PENDING&order=desc", "job_new"},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/jobs"+tt.query, nil)
+		rec := httptest.NewRecorder()
+		handler.ListJobs(rec, req)
+
+		var resp ListJobsResponse
+		json.NewDecoder(rec.Body).Decode(&resp)
+		if rec.Code != http.StatusOK || len(resp.Jobs) != 3 {
+			t.Fatalf("%s: status %d with %d jobs, want 200 with 3", tt.query, rec.Code, len(resp.Jobs))
+		}
+		if resp.Jobs[0].ID != tt.wantFirst {
+			t.Errorf("%s: first job = %s, want %s", tt.query, resp.Jobs[0].ID, tt.wantFirst)
+		}
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/jobs?state=PENDING&order=sideways", nil)
+	rec := httptest.NewRecorder()
+	handler.ListJobs(rec, req)
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("Status for an invalid order = %d, want 400", rec.Code)
+	}
+}
+
+func TestGetJob_RedactsConfiguredPaths(t *testing.T) {
+	repo := repository.NewMemoryJobRepository()
+	jobService := service.NewJobService(repo, state.NewStateMachine(), service.NewULIDGenerator(), service.DefaultRetryConfig())
+	handler := NewHandler(jobService, executor.NewExecutorRegistry(), nil, newTestMetrics())
+	handler.SetRedactions(Redactions{"provision": {"credentials.password", "keys.secret"}})
+	ctx := context.Background()
+
+	result := `{"user":"acme","credentials":{"password":"hunter2"},"keys":[{"id":"k1","secret":"s1"}]}`
+	for id, jobType := range map[string]string{"job_provision": "provision", "job_other": "other"} {
+		repo.Create(ctx, &model.Job{
+			ID:          id,
+			Type:        jobType,
+			Payload:     []byte(`{}`),
+			State:       state.SUCCEEDED,
+			Attempt:     1,
+			MaxAttempts: 3,
+			CreatedAt:   time.Now(),
+			Result:      []byte(result),
+		})
+	}
+
+	getResult := func(id string) string {
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/jobs/"+id, nil)
+		req.SetPathValue("id", id)
+		rec := httptest.NewRecorder()
+		handler.GetJob(rec, req)
+		if rec.Code != http.StatusOK {
+			t.Fatalf("Status = %d, want 200", rec.Code)
+		}
+
+		var resp JobResponse
+		json.NewDecoder(rec.Body).Decode(&resp)
+		return string(resp.Result)
+	}
+
+	want := `{"credentials":{"password":"[REDACTED]"},"keys":[{"id":"k1","secret":"[REDACTED]"}],"user":"acme"}`
+	if got := getResult("job_provision"); got != want {
+		t.Errorf("Result = %s, want %s", got, want)
+	}
+	if got := getResult("job_other"); got != result {
+		t.Errorf("Result for a type without redactions = %s, want it unchanged", got)
+	}
+}
+
+func TestOldestPending(t *testing.T) {
+	repo := repository.NewMemoryJobRepository()
+	jobService := service.NewJobService(repo, state.NewStateMachine(), service.NewULIDGenerator(), service.DefaultRetryConfig())
+	handler := NewHandler(jobService, executor.NewExecutorRegistry(), nil, newTestMetrics())
+
+	repo.Create(context.Background(), &model.Job{
+		ID:          "job_backdated",
+		Type:        "test",
+		Payload:     []byte(`{}`),
+		State:       state.PENDING,
+		Attempt:     1,
+		MaxAttempts: 3,
+		CreatedAt:   time.Now().Add(-10 * time.Minute),
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/queue/oldest", nil)
+	rec := httptest.NewRecorder()
+	handler.OldestPending(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Status = %d, want 200", rec.Code)
+	}
+
+	var resp OldestPendingResponse
+	json.NewDecoder(rec.Body).Decode(&resp)
+	if resp.AgeSeconds < 600 || resp.AgeSeconds > 610 {
+		t.Errorf("age_seconds = %v, want about 600", resp.AgeSeconds)
+	}
+}
+
+func TestListJobs_ByResultKey(t *testing.T) {
+	repo := repository.NewMemoryJobRepository()
+	jobService := service.NewJobService(repo, state.NewStateMachine(), service.NewULIDGenerator(), service.DefaultRetryConfig())
+	handler := NewHandler(jobService, executor.NewExecutorRegistry(), nil, newTestMetrics())
+	ctx := context.Background()
+
+	results := map[string]string{
+		"job_report_ok":     `{"status": "ok", "rows": 10}`,
+		"job_report_failed": `{"status": "partial", "rows": 3}`,
+	}
+	for id, result := range results {
+		repo.Create(ctx, &model.Job{
+			ID:          id,
+			Type:        "report",
+			Payload:     []byte(`{}`),
+			State:       state.SUCCEEDED,
+			Attempt:     1,
+			MaxAttempts: 3,
+			CreatedAt:   time.Now(),
+			Result:      []byte(result),
+		})
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/jobs?result.status=ok", nil)
+	rec := httptest.NewRecorder()
+	handler.ListJobs(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Status = %d, want 200: %s", rec.Code, rec.Body.String())
+	}
+
+	var resp ListJobsResponse
+	json.NewDecoder(rec.Body).Decode(&resp)
+	if resp.Total != 1 || resp.Jobs[0].ID != "job_report_ok" {
+		t.Fatalf("Jobs = %+v, want only job_report_ok", resp.Jobs)
+	}
+	if got := string(resp.Jobs[0].Result); got != `{"status":"ok","rows":10}` {
+		t.Errorf("Result = %s, want the stored result", got)
+	}
+
+	// Only SUCCEEDED jobs have results to match
+	req = httptest.NewRequest(http.MethodGet, "/api/v1/jobs?state=FAILED&result.status=ok", nil)
+	rec = httptest.NewRecorder()
+	handler.ListJobs(rec, req)
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("Status with state=FAILED = %d, want 400", rec.Code)
+	}
+}
+
+func TestJobStates(t *testing.T) {
+	handler, jobService := setupTestHandler()
+	ctx := context.Background()
+
+	pending, _ := jobService.CreateJob(ctx, "test_job", []byte(`{}`))
+	scheduled, _ := jobService.CreateJob(ctx, "test_job", []byte(`{}`))
+	jobService.TransitionState(ctx, scheduled.ID, state.SCHEDULED)
+	cancelled, _ := jobService.CreateJob(ctx, "test_job", []byte(`{}`))
+	jobService.CancelJob(ctx, cancelled.ID)
+
+	body := fmt.Sprintf(`{"ids": [%q, %q, %q, "missing"]}`, pending.ID, scheduled.ID, cancelled.ID)
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/jobs/states", strings.NewReader(body))
+	rec := httptest.NewRecorder()
+	handler.JobStates(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Status = %d, want 200: %s", rec.Code, rec.Body.String())
+	}
+
+	var states map[string]string
+	json.NewDecoder(rec.Body).Decode(&states)
+
+	want := map[string]string{
+		pending.ID:   "PENDING",
+		scheduled.ID: "SCHEDULED",
+		cancelled.ID: "CANCELLED",
+	}
+	if len(states) != len(want) {
+		t.Fatalf("States = %v, want %v", states, want)
+	}
+	for id, wantState := range want {
+		if states[id] != wantState {
+			t.Errorf("State of %s = %q, want %q", id, states[id], wantState)
+		}
+	}
+}
+
+func TestListJobs_LimitClamped(t *testing.T) {
+	handler, jobService := setupTestHandler()
+	jobService.SetMaxListLimit(5)
+	ctx := context.Background()
+
+	for i := 0; i < 8; i++ {
+		jobService.CreateJob(ctx, "test_job", []byte(`{}`))
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/jobs?state=PENDING&limit=99999", nil)
+	rec := httptest.NewRecorder()
+	handler.ListJobs(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Status = %d, want 200", rec.Code)
+	}
+
+	var resp ListJobsResponse
+	json.NewDecoder(rec.Body).Decode(&resp)
+	if resp.Total != 5 || len(resp.Jobs) != 5 {
+		t.Errorf("Returned %d jobs, want the cap of 5", len(resp.Jobs))
+	}
+	if resp.Limit != 5 {
+		t.Errorf("Limit = %d, want clamped 5", resp.Limit)
+	}
+}
+
+func TestRegisterExecutor_RoundTrip(t *testing.T) {
+	handler, _ := setupTestHandler()
+
+	// Register
+	body := `{"type": "plugin_job", "url": "http://plugins.internal/run"}`
+	req := httptest.NewRequest(http.MethodPost, "/admin/executors", strings.NewReader(body))
+	rec := httptest.NewRecorder()
+	handler.RegisterExecutor(rec, req)
+
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("Register status = %d, want 201: %s", rec.Code, rec.Body.String())
+	}
+	if !handler.executors.Has("plugin_job") {
+		t.Fatal("Executor should be registered")
+	}
+
+	// Registering the same type again conflicts
+	req = httptest.NewRequest(http.MethodPost, "/admin/executors", strings.NewReader(body))
+	rec = httptest.NewRecorder()
+	handler.RegisterExecutor(rec, req)
+
+	if rec.Code != http.StatusConflict {
+		t.Errorf("Duplicate register status = %d, want 409", rec.Code)
+	}
+
+	// Unregister
+	req = httptest.NewRequest(http.MethodDelete, "/admin/executors/plugin_job", nil)
+	req.SetPathValue("type", "plugin_job")
+	rec = httptest.NewRecorder()
+	handler.UnregisterExecutor(rec, req)
+
+	if rec.Code != http.StatusNoContent {
+		t.Fatalf("Unregister status = %d, want 204", rec.Code)
+	}
+	if handler.executors.Has("plugin_job") {
+		t.Error("Executor should be unregistered")
+	}
+
+	// Unregistering again is a 404
+	rec = httptest.NewRecorder()
+	handler.UnregisterExecutor(rec, req)
+
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("Second unregister status = %d, want 404", rec.Code)
+	}
+}
+
+func TestRegisterExecutor_InvalidURL(t *testing.T) {
+	handler, _ := setupTestHandler()
+
+	body := `{"type": "plugin_job", "url": "not-a-url"}`
+	req := httptest.NewRequest(http.MethodPost, "/admin/executors", strings.NewReader(body))
+	rec := httptest.NewRecorder()
+	handler.RegisterExecutor(rec, req)
+
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("Status = %d, want 400", rec.Code)
+	}
+}
+
+func TestValidateJob_Valid(t *testing.T) {
+	handler, jobService := setupTestHandler()
+
+	body := `{"type": "test_job", "payload": {"to": "user@example.com"}}`
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/jobs/validate", strings.NewReader(body))
+	rec := httptest.NewRecorder()
+	handler.ValidateJob(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Status = %d, want 200: %s", rec.Code, rec.Body.String())
+	}
+
+	var resp ValidateJobResponse
+	json.NewDecoder(rec.Body).Decode(&resp)
+	if !resp.Valid {
+		t.Errorf("Expected valid, got errors %v", resp.Errors)
+	}
+
+	// Dry run must not create anything
+	jobs, _ := jobService.ListJobsByState(context.Background(), state.PENDING, 10)
+	if len(jobs) != 0 {
+		t.Errorf("Validate created %d jobs, want 0", len(jobs))
+	}
+}
+
+func TestValidateJob_Invalid(t *testing.T) {
+	handler, _ := setupTestHandler()
+
+	tests := []struct {
+		name string
+		body string
+		want string
+	}{
+		{"missing type", `{"payload": {}}`, "job type is required"},
+		{"unknown executor", `{"type": "unknown_job", "payload": {}}`, "no executor registered for job type: unknown_job"},
+		{"malformed body", `{"type": `, "invalid JSON body"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/api/v1/jobs/validate", strings.NewReader(tt.body))
+			rec := httptest.NewRecorder()
+			handler.ValidateJob(rec, req)
+
+			if rec.Code != http.StatusBadRequest {
+				t.Fatalf("Status = %d, want 400", rec.Code)
+			}
+
+			var resp ValidateJobResponse
+			json.NewDecoder(rec.Body).Decode(&resp)
+			if resp.Valid {
+				t.Fatal("Expected invalid")
+			}
+			if len(resp.Errors) != 1 || resp.Errors[0] != tt.want {
+				t.Errorf("Errors = %v, want [%q]", resp.Errors, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetJob_PrettyJSON(t *testing.T) {
+	handler, jobService := setupTestHandler()
+
+	job, _ := jobService.CreateJob(context.Background(), "test_job", []byte(`{}`))
+
+	get := func(target string) string {
+		req := httptest.NewRequest(http.MethodGet, target, nil)
+		req.SetPathValue("id", job.ID)
+		rec := httptest.NewRecorder()
+		handler.GetJob(rec, req)
+
+		if rec.Code != http.StatusOK {
+			t.Fatalf("Status = %d, want 200", rec.Code)
+		}
+		// Encode always appends a trailing newline; ignore it
+		return strings.TrimSuffix(rec.Body.String(), "\n")
+	}
+
+	if body := get("/api/v1/jobs/" + job.ID); strings.Contains(body, "\n") {
+		t.Errorf("Default body should be compact, got %q", body)
+	}
+	if body := get("/api/v1/jobs/" + job.ID + "?pretty=true"); !strings.Contains(body, "\n  \"id\"") {
+		t.Errorf("Pretty body should be indented, got %q", body)
+	}
+}
+
+func TestListTypes(t *testing.T) {
+	handler, jobService := setupTestHandler()
+	ctx := context.Background()
+
+	jobService.CreateJob(ctx, "send_email", []byte(`{}`))
+	jobService.CreateJob(ctx, "resize_image", []byte(`{}`))
+	jobService.CreateJob(ctx, "send_email", []byte(`{}`))
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/types", nil)
+	rec := httptest.NewRecorder()
+	handler.ListTypes(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Status = %d, want 200", rec.Code)
+	}
+
+	var resp ListTypesResponse
+	if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+
+	want := []string{"resize_image", "send_email"}
+	if strings.Join(resp.Types, ",") != strings.Join(want, ",") {
+		t.Errorf("Types = %v, want %v", resp.Types, want)
+	}
+}
+
+// fixedIDGenerator always returns the same ID, to force duplicates.
+type fixedIDGenerator struct{}
+
+func (fixedIDGenerator) Generate() string { return "fixed_job_id" }
+
+func TestCreateJob_LocationHeader(t *testing.T) {
+	handler, _ := setupTestHandler()
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/jobs", strings.NewReader(`{"type": "test_job", "payload": {}}`))
+	rec := httptest.NewRecorder()
+	handler.CreateJob(rec, req)
+
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("Status = %d, want 201", rec.Code)
+	}
+
+	var resp JobResponse
+	json.NewDecoder(rec.Body).Decode(&resp)
+	if got, want := rec.Header().Get("Location"), "/api/v1/jobs/"+resp.ID; got != want {
+		t.Errorf("Location = %q, want %q", got, want)
+	}
+}
+
+func TestCreateJob_DuplicateID(t *testing.T) {
+	jobService := service.NewJobService(
+		repository.NewMemoryJobRepository(),
+		state.NewStateMachine(),
+		fixedIDGenerator{},
+		service.DefaultRetryConfig(),
+	)
+	executors := executor.NewExecutorRegistry()
+	executors.Register("test_job", executor.NewDemoExecutor(0))
+	handler := NewHandler(jobService, executors, nil, newTestMetrics())
+
+	create := func() *httptest.ResponseRecorder {
+		body := strings.NewReader(`{"type": "test_job", "payload": {}}`)
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/jobs", body)
+		rec := httptest.NewRecorder()
+		handler.CreateJob(rec, req)
+		return rec
+	}
+
+	if rec := create(); rec.Code != http.StatusCreated {
+		t.Fatalf("First create status = %d, want 201", rec.Code)
+	}
+
+	rec := create()
+	if rec.Code != http.StatusConflict {
+		t.Fatalf("Second create status = %d, want 409", rec.Code)
+	}
+
+	var resp ErrorResponse
+	json.NewDecoder(rec.Body).Decode(&resp)
+	if resp.Error != "job already exists" {
+		t.Errorf("Error = %q, want \"job already exists\"", resp.Error)
+	}
+}
+
+func TestCreateJob_MaxAttemptsCap(t *testing.T) {
+	handler, _ := setupTestHandler()
+	limit := service.DefaultRetryConfig().MaxAllowedAttempts
+
+	tests := []struct {
+		name        string
+		maxAttempts int
+		want        int
+	}{
+		{"at cap", limit, http.StatusCreated},
+		{"over cap", limit + 1, http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			body := fmt.Sprintf(`{"type": "test_job", "payload": {}, "max_attempts": %d}`, tt.maxAttempts)
+			req := httptest.NewRequest(http.MethodPost, "/api/v1/jobs", strings.NewReader(body))
+			rec := httptest.NewRecorder()
+			handler.CreateJob(rec, req)
+
+			if rec.Code != tt.want {
+				t.Errorf("Status = %d, want %d", rec.Code, tt.want)
+			}
+		})
+	}
+}
+
+func TestRetryJob_MaxAttemptsCap(t *testing.T) {
+	handler, jobService := setupTestHandler()
+	ctx := context.Background()
+	limit := service.DefaultRetryConfig().MaxAllowedAttempts
+
+	job, _ := jobService.CreateJob(ctx, "test_job", []byte(`{}`))
+	jobService.TransitionState(ctx, job.ID, state.SCHEDULED)
+	jobService.TransitionState(ctx, job.ID, state.RUNNING)
+	jobService.ForceFail(ctx, job.ID, "broken")
+
+	retry := func(additional int) int {
+		body := fmt.Sprintf(`{"additional_attempts": %d}`, additional)
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/jobs/"+job.ID+"/retry", strings.NewReader(body))
+		req.SetPathValue("id", job.ID)
+		rec := httptest.NewRecorder()
+		handler.RetryJob(rec, req)
+		return rec.Code
+	}
+
+	if code := retry(limit - job.MaxAttempts + 1); code != http.StatusBadRequest {
+		t.Errorf("Status over cap = %d, want 400", code)
+	}
+	if code := retry(limit - job.MaxAttempts); code != http.StatusOK {
+		t.Errorf("Status at cap = %d, want 200", code)
+	}
+}
+
+func TestCreateJob_AdmissionControl(t *testing.T) {
+	handler, jobService := setupTestHandler()
+	handler.SetAdmissionControl(2, 30*time.Second)
+	ctx := context.Background()
+
+	createJob := func() *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/jobs", strings.NewReader(`{"type": "test_job", "payload": {}}`))
+		rec := httptest.NewRecorder()
+		handler.CreateJob(rec, req)
+		return rec
+	}
+
+	// Below the high-water mark: accepted
+	if rec := createJob(); rec.Code != http.StatusCreated {
+		t.Fatalf("Status = %d, want 201", rec.Code)
+	}
+
+	// Queue reaches the mark: rejected with Retry-After
+	jobService.CreateJob(ctx, "test_job", []byte(`{}`))
+
+	rec := createJob()
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Fatalf("Status = %d, want 503", rec.Code)
+	}
+	if got := rec.Header().Get("Retry-After"); got != "30" {
+		t.Errorf("Retry-After = %q, want \"30\"", got)
+	}
+	if pending, _ := jobService.CountJobsByState(ctx, state.PENDING); pending != 2 {
+		t.Errorf("Pending jobs = %d, want 2 (rejected job must not be stored)", pending)
+	}
+}
+
+func TestCreateJob_AdmissionControlDisabled(t *testing.T) {
+	handler, jobService := setupTestHandler()
+
+	for i := 0; i < 5; i++ {
+		jobService.CreateJob(context.Background(), "test_job", []byte(`{}`))
+	}
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/jobs", strings.NewReader(`{"type": "test_job", "payload": {}}`))
+	rec := httptest.NewRecorder()
+	handler.CreateJob(rec, req)
+
+	if rec.Code != http.StatusCreated {
+		t.Errorf("Status = %d, want 201 with admission control off", rec.Code)
+	}
+}
+
+func TestHealthDetail_StoppedScheduler(t *testing.T) {
+	handler, _ := setupTestHandler()
+
+	sched := scheduler.NewScheduler(repository.NewMemoryJobRepository(), time.Hour, 10, scheduler.NewJobChannel(1), newTestMetrics(), 0)
+	handler.AddLivenessCheck("scheduler", sched)
+
+	get := func() (*httptest.ResponseRecorder, HealthDetailResponse) {
+		req := httptest.NewRequest(http.MethodGet, "/healthz/detail", nil)
+		rec := httptest.NewRecorder()
+		handler.HealthDetail(rec, req)
+
+		var resp HealthDetailResponse
+		json.NewDecoder(rec.Body).Decode(&resp)
+		return rec, resp
+	}
+
+	sched.Start()
+	if rec, resp := get(); rec.Code != http.StatusOK || !resp.Subsystems["scheduler"].Alive {
+		t.Fatalf("Running scheduler: status %d, %+v, want 200 and alive", rec.Code, resp)
+	}
+
+	sched.Stop()
+	rec, resp := get()
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Errorf("Status = %d, want 503", rec.Code)
+	}
+	if resp.Status != "unhealthy" || resp.Subsystems["scheduler"].Alive {
+		t.Errorf("Response = %+v, want unhealthy with scheduler not alive", resp)
+	}
+	if resp.Subsystems["scheduler"].LastTick == nil {
+		t.Error("Expected the scheduler's last tick to be reported")
+	}
+}
+
+func TestCreateJob_Wait(t *testing.T) {
+	repo := repository.NewMemoryJobRepository()
+	jobService := service.NewJobService(repo, state.NewStateMachine(), service.NewULIDGenerator(), service.DefaultRetryConfig())
+	executors := executor.NewExecutorRegistry()
+	executors.Register("test_job", executor.NewDemoExecutor(10*time.Millisecond))
+	m := newTestMetrics()
+	handler := NewHandler(jobService, executors, nil, m)
+
+	jobChannel := scheduler.NewJobChannel(10)
+	sched := scheduler.NewScheduler(repo, 10*time.Millisecond, 10, jobChannel, m, 0)
+	workers := worker.NewWorkerPool(1, 1, jobChannel, executors, jobService, m, 5*time.Second)
+	sched.Start()
+	defer sched.Stop()
+	workers.Start()
+	defer workers.Stop()
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/jobs?wait=true&timeout=5s", strings.NewReader(`{"type": "test_job", "payload": {}}`))
+	rec := httptest.NewRecorder()
+	handler.CreateJob(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Status = %d, want 200; body %s", rec.Code, rec.Body)
+	}
+	var resp JobResponse
+	if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if resp.State != string(state.SUCCEEDED) {
+		t.Errorf("State = %s, want SUCCEEDED", resp.State)
+	}
+}
+
+func TestCreateJob_WaitTimeout(t *testing.T) {
+	// Nothing runs the job, so the wait can only time out
+	handler, _ := setupTestHandler()
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/jobs?wait=true&timeout=20ms", strings.NewReader(`{"type": "test_job", "payload": {}}`))
+	rec := httptest.NewRecorder()
+	handler.CreateJob(rec, req)
+
+	if rec.Code != http.StatusAccepted {
+		t.Fatalf("Status = %d, want 202", rec.Code)
+	}
+	var resp JobResponse
+	json.NewDecoder(rec.Body).Decode(&resp)
+	if resp.ID == "" || resp.State != string(state.PENDING) {
+		t.Errorf("Response = %+v, want the pending job", resp)
+	}
+}
+
+// slowReadRepository blocks reads made under a deadline until it
+// passes, like a database too slow to answer before the wait times out.
+type slowReadRepository struct {
+	*repository.MemoryJobRepository
+}
+
+func (r *slowReadRepository) GetByID(ctx context.Context, id string) (*model.Job, error) {
+	if _, ok := ctx.Deadline(); ok {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	}
+	return r.MemoryJobRepository.GetByID(ctx, id)
+}
+
+func TestCreateJob_WaitTimeoutDuringRead(t *testing.T) {
+	repo := &slowReadRepository{MemoryJobRepository: repository.NewMemoryJobRepository()}
+	jobService := service.NewJobService(repo, state.NewStateMachine(), service.NewULIDGenerator(), service.DefaultRetryConfig())
+	executors := executor.NewExecutorRegistry()
+	executors.Register("test_job", executor.NewDemoExecutor(10*time.Millisecond))
+	handler := NewHandler(jobService, executors, nil, newTestMetrics())
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/jobs?wait=true&timeout=20ms", strings.NewReader(`{"type": "test_job", "payload": {}}`))
+	rec := httptest.NewRecorder()
+	handler.CreateJob(rec, req)
+
+	if rec.Code != http.StatusAccepted {
+		t.Fatalf("Status = %d, want 202", rec.Code)
+	}
+	var resp JobResponse
+	json.NewDecoder(rec.Body).Decode(&resp)
+	if resp.ID == "" || resp.State != string(state.PENDING) {
+		t.Errorf("Response = %+v, want the created job", resp)
+	}
+}
+
+func TestCreateJob_WaitInvalidTimeout(t *testing.T) {
+	handler, jobService := setupTestHandler()
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/jobs?wait=true&timeout=soon", strings.NewReader(`{"type": "test_job", "payload": {}}`))
+	rec := httptest.NewRecorder()
+	handler.CreateJob(rec, req)
+
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("Status = %d, want 400", rec.Code)
+	}
+	if pending, _ := jobService.CountJobsByState(context.Background(), state.PENDING); pending != 0 {
+		t.Errorf("Pending jobs = %d, want 0 (rejected job must not be stored)", pending)
+	}
+}
+
+func TestTopErrors(t *testing.T) {
+	handler, jobService := setupTestHandler()
+	ctx := context.Background()
+
+	for _, msg := range []string{"upstream timeout", "bad payload", "upstream timeout"} {
+		job, _ := jobService.CreateJobWithOptions(ctx, "test_job", []byte(`{}`), service.JobOptions{MaxAttempts: 1})
+		jobService.TransitionState(ctx, job.ID, state.SCHEDULED)
+		jobService.TransitionState(ctx, job.ID, state.RUNNING)
+		jobService.HandleFailure(ctx, job.ID, errors.New(msg))
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/errors/top", nil)
+	rec := httptest.NewRecorder()
+	handler.TopErrors(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Status = %d, want 200", rec.Code)
+	}
+	var resp []ErrorCountResponse
+	if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	want := []ErrorCountResponse{{Error: "upstream timeout", Count: 2}, {Error: "bad payload", Count: 1}}
+	if len(resp) != len(want) || resp[0] != want[0] || resp[1] != want[1] {
+		t.Errorf("Response = %+v, want %+v", resp, want)
+	}
+}
+
+func TestStatsMatrix(t *testing.T) {
+	handler, jobService := setupTestHandler()
+	ctx := context.Background()
+
+	jobService.CreateJob(ctx, "test_job", []byte(`{}`))
+	cancelled, _ := jobService.CreateJob(ctx, "test_job", []byte(`{}`))
+	jobService.CancelJob(ctx, cancelled.ID)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/stats/matrix", nil)
+	rec := httptest.NewRecorder()
+	handler.StatsMatrix(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Status = %d, want 200", rec.Code)
+	}
+	var resp struct {
+		Counts map[string]map[string]int `json:"counts"`
+	}
+	if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	want := map[string]int{"PENDING": 1, "CANCELLED": 1}
+	if got := resp.Counts["test_job"]; len(got) != len(want) || got["PENDING"] != 1 || got["CANCELLED"] != 1 {
+		t.Errorf("Counts for test_job = %v, want %v", got, want)
+	}
+}
+
+func TestCreateJobs_BestEffort(t *testing.T) {
+	handler, jobService := setupTestHandler()
+
+	body := `{"jobs": [
+		{"type": "test_job", "payload": {"n": 0}},
+		{"type": "unknown_job", "payload": {}},
+		{"type": "test_job", "payload": {"n": 2}},
+		{"type": "", "payload": {}}
+	]}`
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/jobs/batch?best_effort=true", strings.NewReader(body))
+	rec := httptest.NewRecorder()
+	handler.CreateJobs(rec, req)
+
+	if rec.Code != http.StatusMultiStatus {
+		t.Fatalf("Status = %d, want 207", rec.Code)
+	}
+	var resp CreateJobsResponse
+	if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if len(resp.Jobs) != 2 {
+		t.Errorf("Created %d jobs, want 2", len(resp.Jobs))
+	}
+	if len(resp.Errors) != 2 || resp.Errors[0].Index != 1 || resp.Errors[1].Index != 3 {
+		t.Errorf("Errors = %+v, want items 1 and 3", resp.Errors)
+	}
+	if pending, _ := jobService.CountJobsByState(context.Background(), state.PENDING); pending != 2 {
+		t.Errorf("Pending jobs = %d, want 2", pending)
+	}
+}
+
+func TestCreateJobs_AtomicByDefault(t *testing.T) {
+	handler, jobService := setupTestHandler()
+
+	body := `{"jobs": [{"type": "test_job", "payload": {}}, {"type": "unknown_job", "payload": {}}]}`
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/jobs/batch", strings.NewReader(body))
+	rec := httptest.NewRecorder()
+	handler.CreateJobs(rec, req)
+
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("Status = %d, want 400", rec.Code)
+	}
+	if pending, _ := jobService.CountJobsByState(context.Background(), state.PENDING); pending != 0 {
+		t.Errorf("Pending jobs = %d, want 0 (nothing created when one job is invalid)", pending)
+	}
+
+	// All valid: every job is created
+	body = `{"jobs": [{"type": "test_job", "payload": {}}, {"type": "test_job", "payload": {}}]}`
+	req = httptest.NewRequest(http.MethodPost, "/api/v1/jobs/batch", strings.NewReader(body))
+	rec = httptest.NewRecorder()
+	handler.CreateJobs(rec, req)
+
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("Status = %d, want 201", rec.Code)
+	}
+	var resp CreateJobsResponse
+	json.NewDecoder(rec.Body).Decode(&resp)
+	if len(resp.Jobs) != 2 || len(resp.Errors) != 0 {
+		t.Errorf("Response = %+v, want 2 jobs and no errors", resp)
+	}
+}
+
+func TestPauseAndResume(t *testing.T) {
+	handler, _ := setupTestHandler()
+
+	sched := scheduler.NewScheduler(repository.NewMemoryJobRepository(), time.Hour, 10, scheduler.NewJobChannel(1), newTestMetrics(), 0)
+	handler.AddPauseControl("scheduling", sched)
+
+	call := func(h http.HandlerFunc, name string) (*httptest.ResponseRecorder, PauseStatusResponse) {
+		req := httptest.NewRequest(http.MethodPost, "/admin/pause/"+name, nil)
+		req.SetPathValue("name", name)
+		rec := httptest.NewRecorder()
+		h(rec, req)
+
+		var resp PauseStatusResponse
+		json.NewDecoder(rec.Body).Decode(&resp)
+		return rec, resp
+	}
+
+	rec, resp := call(handler.Pause, "scheduling")
+	if rec.Code != http.StatusOK || !resp.Paused["scheduling"] || !sched.Paused() {
+		t.Fatalf("Pause: status %d, %+v, want 200 and paused", rec.Code, resp)
+	}
+
+	rec, resp = call(handler.Resume, "scheduling")
+	if rec.Code != http.StatusOK || resp.Paused["scheduling"] || sched.Paused() {
+		t.Errorf("Resume: status %d, %+v, want 200 and not paused", rec.Code, resp)
+	}
+
+	if rec, _ := call(handler.Pause, "nonexistent"); rec.Code != http.StatusNotFound {
+		t.Errorf("Unknown subsystem status = %d, want 404", rec.Code)
+	}
+}
+
+func TestPauseAndResumeType(t *testing.T) {
+	handler, jobService := setupTestHandler()
+
+	call := func(h http.HandlerFunc, jobType string) (*httptest.ResponseRecorder, PauseStatusResponse) {
+		req := httptest.NewRequest(http.MethodPost, "/admin/pause/types/"+jobType, nil)
+		req.SetPathValue("type", jobType)
+		rec := httptest.NewRecorder()
+		h(rec, req)
+
+		var resp PauseStatusResponse
+		json.NewDecoder(rec.Body).Decode(&resp)
+		return rec, resp
+	}
+
+	if rec, _ := call(handler.PauseType, "process_video"); rec.Code != http.StatusNotFound {
+		t.Errorf("Status without a type pause control = %d, want 404", rec.Code)
+	}
+
+	workers := worker.NewWorkerPool(1, 1, scheduler.NewJobChannel(1), executor.NewExecutorRegistry(), jobService, newTestMetrics(), time.Second)
+	handler.SetTypePauseControl(workers)
+
+	rec, resp := call(handler.PauseType, "process_video")
+	if rec.Code != http.StatusOK || !slices.Equal(resp.PausedTypes, []string{"process_video"}) || !workers.TypePaused("process_video") {
+		t.Fatalf("PauseType: status %d, %+v, want 200 and process_video paused", rec.Code, resp)
+	}
+
+	rec, resp = call(handler.ResumeType, "process_video")
+	if rec.Code != http.StatusOK || len(resp.PausedTypes) != 0 || workers.TypePaused("process_video") {
+		t.Errorf("ResumeType: status %d, %+v, want 200 and nothing paused", rec.Code, resp)
+	}
+}
@@ -0,0 +1,171 @@
+package api
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"log/slog"
+	"net/http"
+	"runtime/debug"
+	"time"
+)
+
+// RequestIDHeader is the header used to propagate request IDs.
+const RequestIDHeader = "X-Request-ID"
+
+type contextKey int
+
+const requestIDKey contextKey = iota
+
+// RequestID ensures every request carries an ID.
+// An incoming X-Request-ID header is reused; otherwise a new ID is generated.
+// The ID is echoed in the response header and stored in the request context.
+func RequestID(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := r.Header.Get(RequestIDHeader)
+		if id == "" {
+			id = newRequestID()
+		}
+
+		w.Header().Set(RequestIDHeader, id)
+		ctx := context.WithValue(r.Context(), requestIDKey, id)
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// RequestIDFromContext returns the request ID stored by RequestID, or "" if none.
+func RequestIDFromContext(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey).(string)
+	return id
+}
+
+// Recover catches panics from downstream handlers, logs the stack trace,
+// and returns a 500 JSON error instead of dropping the connection.
+// Apply it outermost so it also covers other middleware.
+func Recover(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		defer func() {
+			rec := recover()
+			if rec == nil {
+				return
+			}
+
+			// net/http uses ErrAbortHandler to abort a response on purpose
+			if rec == http.ErrAbortHandler {
+				panic(rec)
+			}
+
+			// Recover sits outside RequestID, so read the ID from the response header
+			requestID := w.Header().Get(RequestIDHeader)
+			slog.Error("PANIC handling request",
+				"method", r.Method, "path", r.URL.Path, "request_id", requestID,
+				"panic", rec, "stack", string(debug.Stack()))
+
+			respondError(w, http.StatusInternalServerError, "internal server error")
+		}()
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// accessLogSkipPaths are polled constantly and would drown out real traffic.
+var accessLogSkipPaths = map[string]bool{
+	"/health":  true,
+	"/metrics": true,
+}
+
+// AccessLog logs method, path, status, bytes written, latency, and request
+// ID for every request except health checks and metric scrapes.
+// Apply it inside Recover: a request that panics is logged with the 500
+// Recover answers it with, and the panic carries on up to Recover.
+func AccessLog(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if accessLogSkipPaths[r.URL.Path] {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		start := time.Now()
+		rec := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+		panicked := true
+		defer func() {
+			status := rec.status
+			if panicked {
+				status = http.StatusInternalServerError
+			}
+
+			// AccessLog sits outside RequestID, so read the ID from the response header
+			slog.Info("HTTP request",
+				"method", r.Method,
+				"path", r.URL.Path,
+				"status", status,
+				"bytes", rec.bytes,
+				"latency", time.Since(start),
+				"request_id", w.Header().Get(RequestIDHeader),
+			)
+		}()
+
+		next.ServeHTTP(rec, r)
+		panicked = false
+	})
+}
+
+// statusRecorder captures the status code and body size of a response.
+type statusRecorder struct {
+	http.ResponseWriter
+	status      int
+	bytes       int
+	wroteHeader bool
+}
+
+func (r *statusRecorder) WriteHeader(status int) {
+	if !r.wroteHeader {
+		r.status = status
+		r.wroteHeader = true
+	}
+	r.ResponseWriter.WriteHeader(status)
+}
+
+func (r *statusRecorder) Write(b []byte) (int, error) {
+	r.wroteHeader = true
+	n, err := r.ResponseWriter.Write(b)
+	r.bytes += n
+	return n, err
+}
+
+// Unwrap lets http.ResponseController reach the underlying writer.
+func (r *statusRecorder) Unwrap() http.ResponseWriter {
+	return r.ResponseWriter
+}
+
+// RequireAdminToken guards admin endpoints with a bearer token.
+// Requests must send "Authorization: Bearer <token>". An empty token
+// disables the admin API entirely rather than leaving it open.
+func RequireAdminToken(token string, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if token == "" {
+			respondError(w, http.StatusForbidden, "admin API is disabled")
+			return
+		}
+
+		// Constant-time compare so the token can't be guessed by timing
+		want := []byte("Bearer " + token)
+		got := []byte(r.Header.Get("Authorization"))
+		if subtle.ConstantTimeCompare(got, want) != 1 {
+			respondError(w, http.StatusUnauthorized, "invalid or missing admin token")
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// newRequestID generates a random 16-character hex ID.
+func newRequestID() string {
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		return "unknown"
+	}
+	return hex.EncodeToString(b)
+}
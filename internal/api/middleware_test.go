@@ -0,0 +1,203 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestRecover_PanickingHandler(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /panic", func(w http.ResponseWriter, r *http.Request) {
+		var job *JobResponse
+		_ = job.ID // nil dereference
+	})
+	mux.HandleFunc("GET /ok", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+
+	server := httptest.NewServer(Recover(RequestID(mux)))
+	defer server.Close()
+
+	resp, err := http.Get(server.URL + "/panic")
+	if err != nil {
+		t.Fatalf("Request failed: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusInternalServerError {
+		t.Errorf("Status = %d, want 500", resp.StatusCode)
+	}
+
+	var body ErrorResponse
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		t.Fatalf("Failed to decode error body: %v", err)
+	}
+	if body.Error == "" {
+		t.Error("Expected error message in body")
+	}
+
+	if resp.Header.Get(RequestIDHeader) == "" {
+		t.Error("Expected request ID header on panic response")
+	}
+
+	// Server must still serve requests after the panic
+	resp2, err := http.Get(server.URL + "/ok")
+	if err != nil {
+		t.Fatalf("Request after panic failed: %v", err)
+	}
+	resp2.Body.Close()
+
+	if resp2.StatusCode != http.StatusOK {
+		t.Errorf("Status after panic = %d, want 200", resp2.StatusCode)
+	}
+}
+
+func TestRequestID_ReusesIncomingHeader(t *testing.T) {
+	var seen string
+	handler := RequestID(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		seen = RequestIDFromContext(r.Context())
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set(RequestIDHeader, "abc123")
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	if seen != "abc123" {
+		t.Errorf("Request ID in context = %q, want abc123", seen)
+	}
+	if got := rec.Header().Get(RequestIDHeader); got != "abc123" {
+		t.Errorf("Response header = %q, want abc123", got)
+	}
+}
+
+func TestRequireAdminToken(t *testing.T) {
+	ok := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+
+	tests := []struct {
+		name   string
+		token  string
+		header string
+		want   int
+	}{
+		{"valid token", "secret", "Bearer secret", http.StatusOK},
+		{"wrong token", "secret", "Bearer wrong", http.StatusUnauthorized},
+		{"missing header", "secret", "", http.StatusUnauthorized},
+		{"admin disabled", "", "Bearer ", http.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/admin/executors", nil)
+			if tt.header != "" {
+				req.Header.Set("Authorization", tt.header)
+			}
+			rec := httptest.NewRecorder()
+			RequireAdminToken(tt.token, ok).ServeHTTP(rec, req)
+
+			if rec.Code != tt.want {
+				t.Errorf("Status = %d, want %d", rec.Code, tt.want)
+			}
+		})
+	}
+}
+
+func TestAccessLog_LogsStatusAndLatency(t *testing.T) {
+	var logs bytes.Buffer
+	previous := slog.Default()
+	slog.SetDefault(slog.New(slog.NewJSONHandler(&logs, nil)))
+	t.Cleanup(func() { slog.SetDefault(previous) })
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /slow", func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(20 * time.Millisecond)
+		respondError(w, http.StatusTeapot, "short and stout")
+	})
+	mux.HandleFunc("GET /health", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	handler := AccessLog(RequestID(mux))
+
+	req := httptest.NewRequest(http.MethodGet, "/slow", nil)
+	req.Header.Set(RequestIDHeader, "req-123")
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/health", nil))
+
+	lines := bytes.Split(bytes.TrimSpace(logs.Bytes()), []byte("\n"))
+	if len(lines) != 1 {
+		t.Fatalf("Got %d log lines, want 1 (health checks are skipped):\n%s", len(lines), logs.String())
+	}
+
+	var entry struct {
+		Msg       string `json:"msg"`
+		Method    string `json:"method"`
+		Path      string `json:"path"`
+		Status    int    `json:"status"`
+		Bytes     int    `json:"bytes"`
+		Latency   int64  `json:"latency"`
+		RequestID string `json:"request_id"`
+	}
+	if err := json.Unmarshal(lines[0], &entry); err != nil {
+		t.Fatalf("Failed to decode log line: %v", err)
+	}
+
+	if entry.Method != http.MethodGet || entry.Path != "/slow" {
+		t.Errorf("Logged %s %s, want GET /slow", entry.Method, entry.Path)
+	}
+	if entry.Status != http.StatusTeapot {
+		t.Errorf("Logged status = %d, want %d", entry.Status, http.StatusTeapot)
+	}
+	if entry.Bytes != rec.Body.Len() {
+		t.Errorf("Logged bytes = %d, want %d", entry.Bytes, rec.Body.Len())
+	}
+	if latency := time.Duration(entry.Latency); latency < 20*time.Millisecond {
+		t.Errorf("Logged latency = %v, want at least 20ms", latency)
+	}
+	if entry.RequestID != "req-123" {
+		t.Errorf("Logged request_id = %q, want req-123", entry.RequestID)
+	}
+}
+
+func TestAccessLog_LogsPanicAs500(t *testing.T) {
+	var logs bytes.Buffer
+	previous := slog.Default()
+	slog.SetDefault(slog.New(slog.NewJSONHandler(&logs, nil)))
+	t.Cleanup(func() { slog.SetDefault(previous) })
+
+	handler := Recover(AccessLog(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		panic("boom")
+	})))
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/boom", nil))
+
+	if rec.Code != http.StatusInternalServerError {
+		t.Fatalf("Status = %d, want 500", rec.Code)
+	}
+
+	var found bool
+	for _, line := range bytes.Split(bytes.TrimSpace(logs.Bytes()), []byte("\n")) {
+		var entry struct {
+			Msg    string `json:"msg"`
+			Status int    `json:"status"`
+		}
+		if json.Unmarshal(line, &entry) == nil && entry.Msg == "HTTP request" {
+			found = true
+			if entry.Status != http.StatusInternalServerError {
+				t.Errorf("Logged status = %d, want 500", entry.Status)
+			}
+		}
+	}
+	if !found {
+		t.Errorf("No access log line for the panicking request:\n%s", logs.String())
+	}
+}
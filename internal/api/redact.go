@@ -0,0 +1,59 @@
+package api
+
+import (
+	"encoding/json"
+	"strings"
+)
+
+// redactedValue replaces the value at each redacted path.
+const redactedValue = "[REDACTED]"
+
+// Redactions maps a job type to the JSON paths masked in its job data
+// before it leaves the API, e.g. {"send_email": ["smtp.password"]}.
+// A path is a dot-separated list of object keys; on reaching an array,
+// the rest of the path applies to every element. Types without an entry
+// are returned as-is.
+type Redactions map[string][]string
+
+// apply returns data with the paths configured for jobType masked.
+// Data that isn't a JSON object or array is returned unchanged.
+func (r Redactions) apply(jobType string, data json.RawMessage) json.RawMessage {
+	paths := r[jobType]
+	if len(paths) == 0 || len(data) == 0 {
+		return data
+	}
+
+	var value any
+	if err := json.Unmarshal(data, &value); err != nil {
+		return data
+	}
+	for _, path := range paths {
+		redactPath(value, strings.Split(path, "."))
+	}
+
+	redacted, err := json.Marshal(value)
+	if err != nil {
+		return data
+	}
+	return redacted
+}
+
+// redactPath masks the value at path within value, in place.
+func redactPath(value any, path []string) {
+	switch v := value.(type) {
+	case map[string]any:
+		inner, ok := v[path[0]]
+		if !ok {
+			return
+		}
+		if len(path) == 1 {
+			v[path[0]] = redactedValue
+			return
+		}
+		redactPath(inner, path[1:])
+	case []any:
+		for _, elem := range v {
+			redactPath(elem, path)
+		}
+	}
+}
@@ -2,15 +2,106 @@ package api
 
 import (
 	"encoding/json"
+	"sort"
 	"time"
 
 	"github.com/dipak0000812/orchestrix/internal/job/model"
+	"github.com/dipak0000812/orchestrix/internal/job/service"
+	"github.com/dipak0000812/orchestrix/internal/job/state"
 )
 
+// Timestamp is a time in an API response. Every timestamp the API returns
+// uses the same format: RFC 3339 in UTC with whole seconds.
+type Timestamp struct {
+	time.Time
+}
+
+// newTimestamp wraps t as a Timestamp.
+func newTimestamp(t time.Time) Timestamp {
+	return Timestamp{Time: t}
+}
+
+// newTimestampPtr wraps an optional time, keeping nil as nil.
+func newTimestampPtr(t *time.Time) *Timestamp {
+	if t == nil {
+		return nil
+	}
+	ts := newTimestamp(*t)
+	return &ts
+}
+
+// MarshalJSON encodes the time as an RFC 3339 string without fractional seconds.
+func (t Timestamp) MarshalJSON() ([]byte, error) {
+	return json.Marshal(t.UTC().Format(time.RFC3339))
+}
+
 // CreateJobRequest represents the request body for creating a job.
 type CreateJobRequest struct {
-	Type    string          `json:"type"`
-	Payload json.RawMessage `json:"payload"`
+	Type        string          `json:"type"`
+	Payload     json.RawMessage `json:"payload"`
+	Priority    int             `json:"priority,omitempty"`
+	MaxAttempts int             `json:"max_attempts,omitempty"`
+	Schedule    string          `json:"schedule,omitempty"`
+
+	// IdempotencyKey makes the request safe to retry: a repeat with the
+	// same key and type returns the job the first request created.
+	IdempotencyKey string `json:"idempotency_key,omitempty"`
+}
+
+// options converts the optional request fields to service job options.
+func (req CreateJobRequest) options() service.JobOptions {
+	return service.JobOptions{
+		Priority:    req.Priority,
+		MaxAttempts: req.MaxAttempts,
+		Schedule:    req.Schedule,
+
+		IdempotencyKey: req.IdempotencyKey,
+	}
+}
+
+// CreateJobsRequest represents the request body for creating jobs in bulk.
+type CreateJobsRequest struct {
+	Jobs []CreateJobRequest `json:"jobs"`
+}
+
+// CreateJobsResponse represents the result of a bulk create. Jobs holds the
+// created jobs in request order; in best-effort mode, Errors lists the
+// items that were not created, by their index in the request.
+type CreateJobsResponse struct {
+	Jobs   []JobResponse    `json:"jobs"`
+	Errors []BatchItemError `json:"errors,omitempty"`
+}
+
+// BatchItemError reports why one item of a bulk request failed.
+type BatchItemError struct {
+	Index int    `json:"index"`
+	Error string `json:"error"`
+}
+
+// ValidateJobResponse represents the result of a dry-run job validation.
+type ValidateJobResponse struct {
+	Valid  bool     `json:"valid"`
+	Errors []string `json:"errors,omitempty"`
+}
+
+// JobStatesRequest represents the request body for bulk state lookups.
+type JobStatesRequest struct {
+	IDs []string `json:"ids"`
+}
+
+// RetryJobRequest represents the optional request body for retrying a failed job.
+type RetryJobRequest struct {
+	AdditionalAttempts int `json:"additional_attempts"`
+}
+
+// SetMaxAttemptsRequest represents the request body for changing a job's attempt budget.
+type SetMaxAttemptsRequest struct {
+	MaxAttempts int `json:"max_attempts"`
+}
+
+// FailJobRequest represents the optional request body for force-failing a job.
+type FailJobRequest struct {
+	Reason string `json:"reason"`
 }
 
 // JobResponse represents a job in API responses.
@@ -21,16 +112,98 @@ type JobResponse struct {
 	Attempt     int        `json:"attempt"`
 	MaxAttempts int        `json:"max_attempts"`
 	LastError   *string    `json:"last_error,omitempty"`
-	CreatedAt   time.Time  `json:"created_at"`
-	ScheduledAt *time.Time `json:"scheduled_at,omitempty"`
-	StartedAt   *time.Time `json:"started_at,omitempty"`
-	CompletedAt *time.Time `json:"completed_at,omitempty"`
+	CreatedAt   Timestamp  `json:"created_at"`
+	ScheduledAt *Timestamp `json:"scheduled_at,omitempty"`
+	StartedAt   *Timestamp `json:"started_at,omitempty"`
+	CompletedAt *Timestamp `json:"completed_at,omitempty"`
+	Priority    int        `json:"priority"`
+	NextRetryAt *Timestamp `json:"next_retry_at,omitempty"`
+	RunAt       *Timestamp `json:"run_at,omitempty"`
+	Schedule    *string    `json:"schedule,omitempty"`
+	ParentID    *string    `json:"parent_id,omitempty"`
+	RerunCount  int        `json:"rerun_count,omitempty"`
+
+	IdempotencyKey *string `json:"idempotency_key,omitempty"`
+
+	Result json.RawMessage `json:"result,omitempty"`
+
+	LastTransitionReason *string `json:"last_transition_reason,omitempty"`
+
+	// Derived fields, nil until the timestamps they depend on exist.
+	// NextRetryInSeconds is JobService.NextRetryDelay, for RETRYING jobs only.
+	QueueWaitSeconds   *float64 `json:"queue_wait_seconds,omitempty"`
+	RunSeconds         *float64 `json:"run_seconds,omitempty"`
+	NextRetryInSeconds *float64 `json:"next_retry_in_seconds,omitempty"`
+}
+
+// ExpandedJobResponse is a JobResponse with the sections requested via
+// ?include= embedded. Sections that weren't requested are left out entirely.
+type ExpandedJobResponse struct {
+	JobResponse
+	History []JobHistoryEntry  `json:"history,omitzero"`
+	Errors  []JobErrorResponse `json:"errors,omitzero"`
+}
+
+// JobHistoryEntry represents one state change in a job's history.
+type JobHistoryEntry struct {
+	State  string    `json:"state"`
+	At     Timestamp `json:"at"`
+	Reason *string   `json:"reason,omitempty"`
 }
 
 // ListJobsResponse represents the response for listing jobs.
+// Limit is the page size used, after clamping to the server's maximum.
 type ListJobsResponse struct {
 	Jobs  []JobResponse `json:"jobs"`
 	Total int           `json:"total"`
+	Limit int           `json:"limit"`
+}
+
+// OldestPendingResponse reports how long the oldest due PENDING job has
+// been waiting; 0 when nothing is waiting.
+type OldestPendingResponse struct {
+	AgeSeconds float64 `json:"age_seconds"`
+}
+
+// StatsMatrixResponse holds job counts keyed by type, then state.
+// States a type has no jobs in are left out.
+type StatsMatrixResponse struct {
+	Counts map[string]map[state.State]int `json:"counts"`
+}
+
+// ListTypesResponse represents the response for listing job types.
+type ListTypesResponse struct {
+	Types []string `json:"types"`
+}
+
+// ErrorCountResponse is one entry of the top errors list.
+type ErrorCountResponse struct {
+	Error string `json:"error"`
+	Count int    `json:"count"`
+}
+
+// JobErrorResponse represents one failed attempt in a job's error history.
+type JobErrorResponse struct {
+	Attempt    int       `json:"attempt"`
+	Error      string    `json:"error"`
+	OccurredAt Timestamp `json:"occurred_at"`
+}
+
+// ListJobErrorsResponse represents the response for a job's error history.
+type ListJobErrorsResponse struct {
+	Errors []JobErrorResponse `json:"errors"`
+}
+
+// RegisterExecutorRequest represents the request body for registering an HTTP executor.
+type RegisterExecutorRequest struct {
+	Type string `json:"type"`
+	URL  string `json:"url"`
+}
+
+// ExecutorResponse represents a registered executor in API responses.
+type ExecutorResponse struct {
+	Type string `json:"type"`
+	URL  string `json:"url"`
 }
 
 // ErrorResponse represents an error response.
@@ -40,22 +213,119 @@ type ErrorResponse struct {
 
 // HealthResponse represents the health check response.
 type HealthResponse struct {
-	Status    string `json:"status"`
-	Timestamp string `json:"timestamp"`
+	Status    string    `json:"status"`
+	Timestamp Timestamp `json:"timestamp"`
+}
+
+// HealthDetailResponse represents the per-subsystem health check response.
+type HealthDetailResponse struct {
+	Status     string                     `json:"status"`
+	Timestamp  Timestamp                  `json:"timestamp"`
+	Subsystems map[string]SubsystemHealth `json:"subsystems"`
+}
+
+// SubsystemHealth reports whether one background subsystem is running.
+type SubsystemHealth struct {
+	Alive    bool       `json:"alive"`
+	LastTick *Timestamp `json:"last_tick,omitempty"`
 }
 
-// toJobResponse converts a model.Job to JobResponse.
-func toJobResponse(job *model.Job) JobResponse {
-	return JobResponse{
+// PauseStatusResponse reports whether each pausable subsystem is paused,
+// and which job types are.
+type PauseStatusResponse struct {
+	Paused      map[string]bool `json:"paused"`
+	PausedTypes []string        `json:"paused_types,omitempty"`
+}
+
+// toJobResponse converts a model.Job to JobResponse, masking the job's
+// result according to redactions.
+func toJobResponse(job *model.Job, redactions Redactions) JobResponse {
+	resp := JobResponse{
 		ID:          job.ID,
 		Type:        job.Type,
 		State:       string(job.State),
 		Attempt:     job.Attempt,
 		MaxAttempts: job.MaxAttempts,
 		LastError:   job.LastError,
-		CreatedAt:   job.CreatedAt,
-		ScheduledAt: job.ScheduledAt,
-		StartedAt:   job.StartedAt,
-		CompletedAt: job.CompletedAt,
+		CreatedAt:   newTimestamp(job.CreatedAt),
+		ScheduledAt: newTimestampPtr(job.ScheduledAt),
+		StartedAt:   newTimestampPtr(job.StartedAt),
+		CompletedAt: newTimestampPtr(job.CompletedAt),
+		Priority:    job.Priority,
+		NextRetryAt: newTimestampPtr(job.NextRetryAt),
+		RunAt:       newTimestampPtr(job.RunAt),
+		Schedule:    job.Schedule,
+		ParentID:    job.ParentID,
+		RerunCount:  job.RerunCount,
+		Result:      redactions.apply(job.Type, job.Result),
+
+		IdempotencyKey:       job.IdempotencyKey,
+		LastTransitionReason: job.LastTransitionReason,
+	}
+
+	// queue wait: scheduled_at - created_at
+	if job.ScheduledAt != nil {
+		wait := job.ScheduledAt.Sub(job.CreatedAt).Seconds()
+		resp.QueueWaitSeconds = &wait
+	}
+
+	// run time: completed_at - started_at
+	if job.StartedAt != nil && job.CompletedAt != nil {
+		run := job.CompletedAt.Sub(*job.StartedAt).Seconds()
+		resp.RunSeconds = &run
+	}
+
+	return resp
+}
+
+// toJobErrorResponses converts a job's error history to API responses.
+// Always returns a non-nil slice so it marshals as [] rather than null.
+func toJobErrorResponses(attemptErrs []*model.AttemptError) []JobErrorResponse {
+	responses := make([]JobErrorResponse, len(attemptErrs))
+	for i, attemptErr := range attemptErrs {
+		responses[i] = JobErrorResponse{
+			Attempt:    attemptErr.Attempt,
+			Error:      attemptErr.Error,
+			OccurredAt: newTimestamp(attemptErr.OccurredAt),
+		}
+	}
+	return responses
+}
+
+// toJobHistory reconstructs a job's state history from its timestamps and
+// error history. There's no transition log, so scheduled/started reflect
+// only the latest attempt; earlier attempts show up as their RETRYING entry.
+func toJobHistory(job *model.Job, attemptErrs []*model.AttemptError) []JobHistoryEntry {
+	history := []JobHistoryEntry{{State: string(state.PENDING), At: newTimestamp(job.CreatedAt)}}
+
+	// Every error from an earlier attempt sent the job to RETRYING
+	for _, attemptErr := range attemptErrs {
+		if attemptErr.Attempt < job.Attempt {
+			reason := attemptErr.Error
+			history = append(history, JobHistoryEntry{
+				State:  string(state.RETRYING),
+				At:     newTimestamp(attemptErr.OccurredAt),
+				Reason: &reason,
+			})
+		}
 	}
+
+	if job.ScheduledAt != nil {
+		history = append(history, JobHistoryEntry{State: string(state.SCHEDULED), At: newTimestamp(*job.ScheduledAt)})
+	}
+	if job.StartedAt != nil {
+		history = append(history, JobHistoryEntry{State: string(state.RUNNING), At: newTimestamp(*job.StartedAt)})
+	}
+	if job.IsTerminal() && job.CompletedAt != nil {
+		history = append(history, JobHistoryEntry{
+			State:  string(job.State),
+			At:     newTimestamp(*job.CompletedAt),
+			Reason: job.LastTransitionReason,
+		})
+	}
+
+	sort.SliceStable(history, func(i, j int) bool {
+		return history[i].At.Before(history[j].At.Time)
+	})
+	return history
 }
@@ -0,0 +1,104 @@
+package api
+
+import (
+	"encoding/json"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/dipak0000812/orchestrix/internal/job/model"
+	"github.com/dipak0000812/orchestrix/internal/job/state"
+)
+
+func TestToJobResponse_DerivedFields(t *testing.T) {
+	created := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	scheduled := created.Add(2 * time.Second)
+	started := scheduled.Add(500 * time.Millisecond)
+	completed := started.Add(3 * time.Second)
+
+	job := &model.Job{
+		ID:          "job_1",
+		Type:        "test",
+		State:       state.SUCCEEDED,
+		Attempt:     1,
+		MaxAttempts: 3,
+		CreatedAt:   created,
+		ScheduledAt: &scheduled,
+		StartedAt:   &started,
+		CompletedAt: &completed,
+	}
+
+	resp := toJobResponse(job, nil)
+
+	if resp.QueueWaitSeconds == nil || *resp.QueueWaitSeconds != 2 {
+		t.Errorf("QueueWaitSeconds = %v, want 2", resp.QueueWaitSeconds)
+	}
+	if resp.RunSeconds == nil || *resp.RunSeconds != 3 {
+		t.Errorf("RunSeconds = %v, want 3", resp.RunSeconds)
+	}
+}
+
+func TestToJobResponse_DerivedFieldsPending(t *testing.T) {
+	job := &model.Job{
+		ID:          "job_1",
+		Type:        "test",
+		State:       state.PENDING,
+		Attempt:     1,
+		MaxAttempts: 3,
+		CreatedAt:   time.Now(),
+	}
+
+	resp := toJobResponse(job, nil)
+
+	if resp.QueueWaitSeconds != nil {
+		t.Errorf("QueueWaitSeconds = %v, want nil", *resp.QueueWaitSeconds)
+	}
+	if resp.RunSeconds != nil {
+		t.Errorf("RunSeconds = %v, want nil", *resp.RunSeconds)
+	}
+}
+
+func TestTimestamp_SameFormatAcrossResponses(t *testing.T) {
+	at := time.Date(2024, 1, 1, 12, 0, 0, 123456789, time.FixedZone("EST", -5*3600))
+
+	jobJSON, err := json.Marshal(toJobResponse(&model.Job{
+		ID:        "job_1",
+		Type:      "test",
+		State:     state.PENDING,
+		CreatedAt: at,
+		RunAt:     &at,
+	}, nil))
+	if err != nil {
+		t.Fatalf("Failed to marshal job response: %v", err)
+	}
+	healthJSON, err := json.Marshal(HealthResponse{Status: "healthy", Timestamp: newTimestamp(at)})
+	if err != nil {
+		t.Fatalf("Failed to marshal health response: %v", err)
+	}
+
+	var job map[string]any
+	var health map[string]any
+	json.Unmarshal(jobJSON, &job)
+	json.Unmarshal(healthJSON, &health)
+
+	want := "2024-01-01T17:00:00Z"
+	format := regexp.MustCompile(`^\d{4}-\d{2}-\d{2}T\d{2}:\d{2}:\d{2}Z$`)
+	for name, got := range map[string]any{
+		"job created_at":   job["created_at"],
+		"job run_at":       job["run_at"],
+		"health timestamp": health["timestamp"],
+	} {
+		s, _ := got.(string)
+		if !format.MatchString(s) || s != want {
+			t.Errorf("%s = %v, want %s", name, got, want)
+		}
+	}
+
+	var decoded JobResponse
+	if err := json.Unmarshal(jobJSON, &decoded); err != nil {
+		t.Fatalf("Failed to decode job response: %v", err)
+	}
+	if !decoded.CreatedAt.Equal(at.Truncate(time.Second)) {
+		t.Errorf("Decoded created_at = %v, want %v", decoded.CreatedAt, at.Truncate(time.Second))
+	}
+}
@@ -0,0 +1,48 @@
+package blobstore
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestFSBlobStore_RoundTrip(t *testing.T) {
+	store, err := NewFSBlobStore(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewFSBlobStore failed: %v", err)
+	}
+	ctx := context.Background()
+
+	data := bytes.Repeat([]byte("x"), 1<<20)
+	if err := store.Put(ctx, "job_1", data); err != nil {
+		t.Fatalf("Put failed: %v", err)
+	}
+
+	got, err := store.Get(ctx, "job_1")
+	if err != nil {
+		t.Fatalf("Get failed: %v", err)
+	}
+	if !bytes.Equal(got, data) {
+		t.Errorf("Get returned %d bytes, want the %d bytes stored", len(got), len(data))
+	}
+}
+
+func TestFSBlobStore_NotFound(t *testing.T) {
+	store, _ := NewFSBlobStore(t.TempDir())
+
+	_, err := store.Get(context.Background(), "missing")
+	if !errors.Is(err, ErrNotFound) {
+		t.Errorf("Get error = %v, want ErrNotFound", err)
+	}
+}
+
+func TestFSBlobStore_RejectsPathKeys(t *testing.T) {
+	store, _ := NewFSBlobStore(t.TempDir())
+
+	for _, key := range []string{"", "..", "../escape", "a/b"} {
+		if err := store.Put(context.Background(), key, []byte("x")); err == nil {
+			t.Errorf("Put(%q) succeeded, want invalid key error", key)
+		}
+	}
+}
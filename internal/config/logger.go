@@ -0,0 +1,36 @@
+package config
+
+import (
+	"io"
+	"log/slog"
+	"os"
+)
+
+// NewLogger builds a logger from the logging config, writing to stderr.
+// Format "json" gives one JSON object per line; anything else is text.
+func NewLogger(cfg LoggingConfig) *slog.Logger {
+	return newLogger(cfg, os.Stderr)
+}
+
+func newLogger(cfg LoggingConfig, w io.Writer) *slog.Logger {
+	opts := &slog.HandlerOptions{Level: parseLevel(cfg.Level)}
+
+	if cfg.Format == "json" {
+		return slog.New(slog.NewJSONHandler(w, opts))
+	}
+	return slog.New(slog.NewTextHandler(w, opts))
+}
+
+// parseLevel maps a config level to a slog level, defaulting to info.
+func parseLevel(level string) slog.Level {
+	switch level {
+	case "debug":
+		return slog.LevelDebug
+	case "warn":
+		return slog.LevelWarn
+	case "error":
+		return slog.LevelError
+	default:
+		return slog.LevelInfo
+	}
+}
@@ -0,0 +1,55 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestNewLogger_JSONFormat(t *testing.T) {
+	var buf bytes.Buffer
+	logger := newLogger(LoggingConfig{Level: "info", Format: "json"}, &buf)
+
+	logger.Info("job scheduled", "job_id", "job_123")
+
+	var entry map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+		t.Fatalf("Expected valid JSON, got %q: %v", buf.String(), err)
+	}
+	if entry["msg"] != "job scheduled" {
+		t.Errorf("msg = %v, want %q", entry["msg"], "job scheduled")
+	}
+	if entry["job_id"] != "job_123" {
+		t.Errorf("job_id = %v, want %q", entry["job_id"], "job_123")
+	}
+}
+
+func TestNewLogger_TextFormat(t *testing.T) {
+	var buf bytes.Buffer
+	logger := newLogger(LoggingConfig{Level: "info", Format: "text"}, &buf)
+
+	logger.Info("job scheduled", "job_id", "job_123")
+
+	if json.Valid(buf.Bytes()) {
+		t.Errorf("Expected text output, got JSON: %q", buf.String())
+	}
+	if !strings.Contains(buf.String(), "job_id=job_123") {
+		t.Errorf("Expected key=value output, got %q", buf.String())
+	}
+}
+
+func TestNewLogger_Level(t *testing.T) {
+	var buf bytes.Buffer
+	logger := newLogger(LoggingConfig{Level: "warn", Format: "json"}, &buf)
+
+	logger.Info("dropped")
+	if buf.Len() != 0 {
+		t.Fatalf("Expected info to be filtered at warn level, got %q", buf.String())
+	}
+
+	logger.Warn("kept")
+	if !strings.Contains(buf.String(), "kept") {
+		t.Errorf("Expected warn to be logged, got %q", buf.String())
+	}
+}
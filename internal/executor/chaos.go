@@ -0,0 +1,88 @@
+package executor
+
+import (
+	"context"
+	"errors"
+	"math/rand/v2"
+	"sync"
+	"time"
+)
+
+// ErrChaos is the error returned by a ChaosExecutor's injected failures.
+var ErrChaos = errors.New("chaos: injected failure")
+
+// ChaosConfig sets how often a ChaosExecutor misbehaves. Rates are
+// probabilities in [0, 1] applied to each Execute call.
+type ChaosConfig struct {
+	// FailRate is the chance a call returns ErrChaos instead of running.
+	FailRate float64
+
+	// SlowRate is the chance a call first sleeps for SlowDelay.
+	// Slowness combines with the other outcomes.
+	SlowRate  float64
+	SlowDelay time.Duration
+
+	// PanicRate is the chance a call panics instead of running.
+	// Checked before FailRate; the two together should not exceed 1.
+	PanicRate float64
+
+	// Seed makes the sequence of outcomes reproducible.
+	// Zero picks a random seed.
+	Seed uint64
+}
+
+// ChaosExecutor wraps an executor and randomly fails, slows down, or
+// panics, to exercise retry and reaper behavior. Not for production use.
+type ChaosExecutor struct {
+	base Executor
+	cfg  ChaosConfig
+
+	mu  sync.Mutex // rand.Rand is not safe for concurrent use
+	rng *rand.Rand
+}
+
+// WithChaos wraps base so each Execute misbehaves at the rates in cfg.
+func WithChaos(base Executor, cfg ChaosConfig) *ChaosExecutor {
+	seed := cfg.Seed
+	if seed == 0 {
+		seed = rand.Uint64()
+	}
+
+	return &ChaosExecutor{
+		base: base,
+		cfg:  cfg,
+		rng:  rand.New(rand.NewPCG(seed, seed)),
+	}
+}
+
+// Execute runs the base executor unless chaos strikes first.
+// An induced slowdown ends early if ctx is done.
+func (e *ChaosExecutor) Execute(ctx context.Context, payload []byte) error {
+	slow, outcome := e.roll()
+
+	if slow {
+		timer := time.NewTimer(e.cfg.SlowDelay)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		}
+	}
+
+	switch {
+	case outcome < e.cfg.PanicRate:
+		panic("chaos: injected panic")
+	case outcome < e.cfg.PanicRate+e.cfg.FailRate:
+		return ErrChaos
+	}
+	return e.base.Execute(ctx, payload)
+}
+
+// roll draws whether this call is slow and a value in [0, 1) that picks
+// between panicking, failing, and running normally.
+func (e *ChaosExecutor) roll() (slow bool, outcome float64) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.rng.Float64() < e.cfg.SlowRate, e.rng.Float64()
+}
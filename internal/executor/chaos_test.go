@@ -0,0 +1,81 @@
+package executor
+
+import (
+	"context"
+	"errors"
+	"math"
+	"testing"
+	"time"
+)
+
+func TestWithChaos_FailRateApproximatelyHonored(t *testing.T) {
+	const runs = 10000
+	base := &flakyExecutor{}
+	chaos := WithChaos(base, ChaosConfig{FailRate: 0.3, Seed: 42})
+
+	failures := 0
+	for i := 0; i < runs; i++ {
+		err := chaos.Execute(context.Background(), nil)
+		if errors.Is(err, ErrChaos) {
+			failures++
+		} else if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+	}
+
+	if rate := float64(failures) / runs; math.Abs(rate-0.3) > 0.02 {
+		t.Errorf("Failure rate = %.3f, want about 0.3", rate)
+	}
+	if base.calls != runs-failures {
+		t.Errorf("Base called %d times, want %d (once per non-failed run)", base.calls, runs-failures)
+	}
+}
+
+func TestWithChaos_SeedIsReproducible(t *testing.T) {
+	outcomes := func() []bool {
+		chaos := WithChaos(&flakyExecutor{}, ChaosConfig{FailRate: 0.5, Seed: 7})
+		var failed []bool
+		for i := 0; i < 100; i++ {
+			failed = append(failed, chaos.Execute(context.Background(), nil) != nil)
+		}
+		return failed
+	}
+
+	first, second := outcomes(), outcomes()
+	for i := range first {
+		if first[i] != second[i] {
+			t.Fatalf("Run %d differs between executors with the same seed", i)
+		}
+	}
+}
+
+func TestWithChaos_Panics(t *testing.T) {
+	chaos := WithChaos(&flakyExecutor{}, ChaosConfig{PanicRate: 1, Seed: 1})
+
+	defer func() {
+		if recover() == nil {
+			t.Error("Expected a panic with PanicRate 1")
+		}
+	}()
+	chaos.Execute(context.Background(), nil)
+}
+
+func TestWithChaos_SlownessRespectsContext(t *testing.T) {
+	base := &flakyExecutor{}
+	chaos := WithChaos(base, ChaosConfig{SlowRate: 1, SlowDelay: time.Minute, Seed: 1})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	err := chaos.Execute(ctx, nil)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Error = %v, want context.DeadlineExceeded", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("Execute took %v, want it cut short by the context", elapsed)
+	}
+	if base.calls != 0 {
+		t.Errorf("Base called %d times after the context ended, want 0", base.calls)
+	}
+}
@@ -0,0 +1,45 @@
+package executor
+
+import (
+	"context"
+	"strconv"
+)
+
+// JobContext describes the job an executor is running.
+// Workers attach it to the context passed to Execute, so executors can
+// correlate their logs and outbound calls with the orchestration layer.
+type JobContext struct {
+	ID          string
+	Type        string
+	Attempt     int
+	MaxAttempts int
+}
+
+type jobContextKey struct{}
+
+// WithJobContext returns a copy of ctx carrying jc.
+func WithJobContext(ctx context.Context, jc JobContext) context.Context {
+	return context.WithValue(ctx, jobContextKey{}, jc)
+}
+
+// JobContextFrom returns the JobContext attached to ctx, if any.
+func JobContextFrom(ctx context.Context) (JobContext, bool) {
+	jc, ok := ctx.Value(jobContextKey{}).(JobContext)
+	return jc, ok
+}
+
+// Headers sent by HTTPExecutor so callback receivers can identify the job.
+const (
+	JobIDHeader      = "X-Orchestrix-Job-ID"
+	JobTypeHeader    = "X-Orchestrix-Job-Type"
+	JobAttemptHeader = "X-Orchestrix-Job-Attempt"
+)
+
+// headers returns the HTTP headers describing the job.
+func (jc JobContext) headers() map[string]string {
+	return map[string]string{
+		JobIDHeader:      jc.ID,
+		JobTypeHeader:    jc.Type,
+		JobAttemptHeader: strconv.Itoa(jc.Attempt),
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"log/slog"
 	"time"
 )
 
@@ -11,15 +12,29 @@ import (
 // Just logs the payload and simulates work.
 type DemoExecutor struct {
 	simulatedDuration time.Duration
+
+	// failTimes is how many attempts fail before the job succeeds.
+	failTimes int
 }
 
 // NewDemoExecutor creates a demo executor.
+// A zero duration completes immediately. That can't spin the workers:
+// failed jobs wait out their retry backoff before they're claimed again.
 func NewDemoExecutor(duration time.Duration) *DemoExecutor {
 	return &DemoExecutor{
-		simulatedDuration: duration,
+		simulatedDuration: max(duration, 0),
 	}
 }
 
+// NewDemoExecutorWithBehavior creates a demo executor that fails the first
+// failTimes attempts of each job, then succeeds. Attempts are read from the
+// job context, so it's only useful for jobs run by a worker pool.
+func NewDemoExecutorWithBehavior(duration time.Duration, failTimes int) *DemoExecutor {
+	e := NewDemoExecutor(duration)
+	e.failTimes = failTimes
+	return e
+}
+
 // Execute simulates job execution.
 func (e *DemoExecutor) Execute(ctx context.Context, payload []byte) error {
 	// Parse payload (just for demonstration)
@@ -28,10 +43,19 @@ func (e *DemoExecutor) Execute(ctx context.Context, payload []byte) error {
 		return fmt.Errorf("invalid payload: %w", err)
 	}
 
+	jc, ok := JobContextFrom(ctx)
+	if ok {
+		slog.InfoContext(ctx, "Demo executor running job",
+			"job_id", jc.ID, "attempt", jc.Attempt, "max_attempts", jc.MaxAttempts,
+			"payload", SummarizePayload(payload, 128))
+	}
+
 	// Simulate work
 	select {
 	case <-time.After(e.simulatedDuration):
-		// Work completed
+		if ok && jc.Attempt <= e.failTimes {
+			return fmt.Errorf("simulated failure on attempt %d of %d", jc.Attempt, e.failTimes)
+		}
 		return nil
 	case <-ctx.Done():
 		// Context cancelled (timeout or shutdown)
@@ -0,0 +1,43 @@
+package executor
+
+import "errors"
+
+// NonRetryableError marks an execution error as permanent: the job fails
+// right away instead of using up its remaining attempts.
+type NonRetryableError struct {
+	Err error
+}
+
+// NonRetryable wraps err so the worker pool fails the job without
+// retrying it. A nil err stays nil.
+func NonRetryable(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &NonRetryableError{Err: err}
+}
+
+func (e *NonRetryableError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *NonRetryableError) Unwrap() error {
+	return e.Err
+}
+
+// ErrorClassifier reports whether a failed execution is worth retrying.
+type ErrorClassifier func(err error) bool
+
+// DefaultClassifier retries every error except a NonRetryableError.
+func DefaultClassifier(err error) bool {
+	var nonRetryable *NonRetryableError
+	return !errors.As(err, &nonRetryable)
+}
+
+// ErrorClassifierProvider is optionally implemented by executors that
+// know which of their own errors are worth retrying. It takes precedence
+// over the worker pool's classifier for that executor's jobs.
+type ErrorClassifierProvider interface {
+	// IsRetryable reports whether err, returned by Execute, should be retried.
+	IsRetryable(err error) bool
+}
@@ -0,0 +1,36 @@
+package executor
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestDefaultClassifier(t *testing.T) {
+	cause := errors.New("invalid payload")
+
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"plain error", errors.New("timeout"), true},
+		{"non-retryable", NonRetryable(cause), false},
+		{"wrapped non-retryable", fmt.Errorf("sending: %w", NonRetryable(cause)), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := DefaultClassifier(tt.err); got != tt.want {
+				t.Errorf("DefaultClassifier(%v) = %v, want %v", tt.err, got, tt.want)
+			}
+		})
+	}
+
+	if !errors.Is(NonRetryable(cause), cause) {
+		t.Error("NonRetryable should unwrap to its cause")
+	}
+	if NonRetryable(nil) != nil {
+		t.Error("NonRetryable(nil) should be nil")
+	}
+}
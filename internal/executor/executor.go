@@ -3,19 +3,34 @@ package executor
 import (
 	"context"
 	"fmt"
+	"sync"
+	"time"
 )
 
 // Executor defines the interface for job execution.
 // Each job type (send_email, process_video, etc.) implements this interface.
 type Executor interface {
 	// Execute runs the job with the given payload.
+	// ctx carries the job's metadata; see JobContextFrom.
 	// Returns error if execution fails.
 	Execute(ctx context.Context, payload []byte) error
 }
 
+// TimeoutProvider is optionally implemented by executors whose job type
+// needs a different deadline than the worker pool's default.
+type TimeoutProvider interface {
+	// DefaultTimeout bounds each execution of this executor's jobs.
+	// Zero or less means use the worker pool's default.
+	DefaultTimeout() time.Duration
+}
+
 // ExecutorRegistry maps job types to their executors.
+// Safe for concurrent use, so executors can be registered at runtime
+// while workers are looking them up.
 type ExecutorRegistry struct {
+	mu        sync.RWMutex
 	executors map[string]Executor
+	fallback  Executor // used for types without an executor; nil means none
 }
 
 // NewExecutorRegistry creates a new executor registry.
@@ -27,21 +42,68 @@ func NewExecutorRegistry() *ExecutorRegistry {
 
 // Register adds an executor for a specific job type.
 func (r *ExecutorRegistry) Register(jobType string, executor Executor) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.executors[jobType] = executor
+}
+
+// RegisterIfAbsent adds an executor only if the job type has none yet.
+// Returns false if an executor was already registered.
+func (r *ExecutorRegistry) RegisterIfAbsent(jobType string, executor Executor) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.executors[jobType]; exists {
+		return false
+	}
 	r.executors[jobType] = executor
+	return true
 }
 
-// Get retrieves the executor for a job type.
-// Returns error if executor not found.
+// SetDefault sets a catch-all executor for job types with no executor of
+// their own, e.g. one that dead-letters or forwards to a generic handler.
+// Pass nil to remove it, so unknown types are rejected again.
+func (r *ExecutorRegistry) SetDefault(executor Executor) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.fallback = executor
+}
+
+// Unregister removes the executor for a job type.
+// Returns false if no executor was registered.
+func (r *ExecutorRegistry) Unregister(jobType string) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.executors[jobType]; !exists {
+		return false
+	}
+	delete(r.executors, jobType)
+	return true
+}
+
+// Get retrieves the executor for a job type, or the default executor if
+// the type has none. Returns error if neither exists.
 func (r *ExecutorRegistry) Get(jobType string) (Executor, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
 	executor, exists := r.executors[jobType]
 	if !exists {
+		if r.fallback != nil {
+			return r.fallback, nil
+		}
 		return nil, fmt.Errorf("no executor registered for job type: %s", jobType)
 	}
 	return executor, nil
 }
 
-// Has checks if an executor is registered for a job type.
+// Has checks if jobs of a type can be executed: an executor is
+// registered for it or a default is set.
 func (r *ExecutorRegistry) Has(jobType string) bool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
 	_, exists := r.executors[jobType]
-	return exists
+	return exists || r.fallback != nil
 }
@@ -0,0 +1,43 @@
+package executor
+
+import (
+	"testing"
+	"time"
+)
+
+func TestExecutorRegistry_NoDefault(t *testing.T) {
+	registry := NewExecutorRegistry()
+
+	if _, err := registry.Get("unknown"); err == nil {
+		t.Error("Get for an unregistered type should fail without a default")
+	}
+	if registry.Has("unknown") {
+		t.Error("Has for an unregistered type should be false without a default")
+	}
+}
+
+func TestExecutorRegistry_DefaultFallback(t *testing.T) {
+	registry := NewExecutorRegistry()
+	specific := NewDemoExecutor(time.Millisecond)
+	fallback := NewFailingExecutor()
+	registry.Register("demo_job", specific)
+	registry.SetDefault(fallback)
+
+	exec, err := registry.Get("demo_job")
+	if err != nil || exec != specific {
+		t.Errorf("Get(demo_job) = %v, %v; want the registered executor", exec, err)
+	}
+
+	exec, err = registry.Get("unknown")
+	if err != nil || exec != fallback {
+		t.Errorf("Get(unknown) = %v, %v; want the default executor", exec, err)
+	}
+	if !registry.Has("unknown") {
+		t.Error("Has for an unregistered type should be true with a default")
+	}
+
+	registry.SetDefault(nil)
+	if _, err := registry.Get("unknown"); err == nil {
+		t.Error("Get for an unregistered type should fail once the default is removed")
+	}
+}
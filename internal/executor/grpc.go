@@ -0,0 +1,62 @@
+package executor
+
+import (
+	"context"
+	"fmt"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/dipak0000812/orchestrix/internal/executor/workerpb"
+)
+
+// GRPCExecutor executes jobs by calling a remote JobWorker service
+// (proto/worker/v1/worker.proto). Used for job types whose logic lives
+// in a separate service, possibly written in another language.
+type GRPCExecutor struct {
+	client workerpb.JobWorkerClient
+}
+
+// NewGRPCExecutor creates an executor that calls the JobWorker service on conn.
+// The caller owns conn and closes it on shutdown.
+func NewGRPCExecutor(conn grpc.ClientConnInterface) *GRPCExecutor {
+	return &GRPCExecutor{
+		client: workerpb.NewJobWorkerClient(conn),
+	}
+}
+
+// Execute sends the job to the remote worker, which sees the job's
+// deadline as the call's deadline. A failure the worker reports in its
+// JobResult becomes the job's error, non-retryable if marked fatal.
+// RPC errors are retryable, except when the worker rejected the call
+// outright (InvalidArgument, Unimplemented): retrying won't change that.
+func (e *GRPCExecutor) Execute(ctx context.Context, payload []byte) error {
+	req := &workerpb.JobRequest{Payload: payload}
+	if jc, ok := JobContextFrom(ctx); ok {
+		req.JobId = jc.ID
+		req.Type = jc.Type
+		req.Attempt = int32(jc.Attempt)
+		req.MaxAttempts = int32(jc.MaxAttempts)
+	}
+
+	result, err := e.client.Execute(ctx, req)
+	if err != nil {
+		err = fmt.Errorf("worker call failed: %w", err)
+		switch status.Code(err) {
+		case codes.InvalidArgument, codes.Unimplemented:
+			return NonRetryable(err)
+		}
+		return err
+	}
+
+	if result.GetError() == "" {
+		return nil
+	}
+
+	jobErr := fmt.Errorf("worker reported failure: %s", result.GetError())
+	if result.GetFatal() {
+		return NonRetryable(jobErr)
+	}
+	return jobErr
+}
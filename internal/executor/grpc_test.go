@@ -0,0 +1,123 @@
+package executor
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+
+	"github.com/dipak0000812/orchestrix/internal/executor/workerpb"
+)
+
+// fakeWorker answers every call with result or err and keeps the last request.
+type fakeWorker struct {
+	workerpb.UnimplementedJobWorkerServer
+
+	result *workerpb.JobResult
+	err    error
+
+	lastReq      *workerpb.JobRequest
+	lastDeadline time.Time
+}
+
+func (w *fakeWorker) Execute(ctx context.Context, req *workerpb.JobRequest) (*workerpb.JobResult, error) {
+	w.lastReq = req
+	w.lastDeadline, _ = ctx.Deadline()
+	if w.err != nil {
+		return nil, w.err
+	}
+	return w.result, nil
+}
+
+// startFakeWorker serves worker in-process and returns a connection to it.
+func startFakeWorker(t *testing.T, worker *fakeWorker) *grpc.ClientConn {
+	t.Helper()
+
+	lis := bufconn.Listen(1 << 20)
+	server := grpc.NewServer()
+	workerpb.RegisterJobWorkerServer(server, worker)
+	go server.Serve(lis)
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return lis.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatalf("Failed to dial fake worker: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return conn
+}
+
+func TestGRPCExecutor_ForwardsJobAndDeadline(t *testing.T) {
+	worker := &fakeWorker{result: &workerpb.JobResult{}}
+	exec := NewGRPCExecutor(startFakeWorker(t, worker))
+
+	ctx := WithJobContext(context.Background(), JobContext{ID: "job_1", Type: "resize", Attempt: 2, MaxAttempts: 3})
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+	deadline, _ := ctx.Deadline()
+
+	if err := exec.Execute(ctx, []byte(`{"w":100}`)); err != nil {
+		t.Fatalf("Execute failed: %v", err)
+	}
+
+	req := worker.lastReq
+	if req.GetJobId() != "job_1" || req.GetType() != "resize" || req.GetAttempt() != 2 || req.GetMaxAttempts() != 3 {
+		t.Errorf("Request = %v, want the job's context", req)
+	}
+	if string(req.GetPayload()) != `{"w":100}` {
+		t.Errorf("Payload = %s, want {\"w\":100}", req.GetPayload())
+	}
+	if diff := deadline.Sub(worker.lastDeadline).Abs(); diff > time.Second {
+		t.Errorf("Worker deadline = %v, want about the job's %v", worker.lastDeadline, deadline)
+	}
+}
+
+func TestGRPCExecutor_ErrorMapping(t *testing.T) {
+	tests := []struct {
+		name          string
+		worker        *fakeWorker
+		wantRetryable bool
+	}{
+		{"job failure", &fakeWorker{result: &workerpb.JobResult{Error: "disk full"}}, true},
+		{"fatal job failure", &fakeWorker{result: &workerpb.JobResult{Error: "bad image", Fatal: true}}, false},
+		{"worker unavailable", &fakeWorker{err: status.Error(codes.Unavailable, "overloaded")}, true},
+		{"request rejected", &fakeWorker{err: status.Error(codes.InvalidArgument, "no such type")}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			exec := NewGRPCExecutor(startFakeWorker(t, tt.worker))
+
+			err := exec.Execute(context.Background(), []byte(`{}`))
+			if err == nil {
+				t.Fatal("Expected an error")
+			}
+			if got := DefaultClassifier(err); got != tt.wantRetryable {
+				t.Errorf("Retryable = %v, want %v (error: %v)", got, tt.wantRetryable, err)
+			}
+		})
+	}
+}
+
+func TestGRPCExecutor_ContextCancelled(t *testing.T) {
+	exec := NewGRPCExecutor(startFakeWorker(t, &fakeWorker{result: &workerpb.JobResult{}}))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	err := exec.Execute(ctx, []byte(`{}`))
+	if status.Code(err) != codes.Canceled {
+		t.Errorf("Error = %v, want a cancelled call", err)
+	}
+}
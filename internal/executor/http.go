@@ -0,0 +1,63 @@
+package executor
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// HTTPExecutor executes jobs by POSTing the payload to a callback URL.
+// Used for job types deployed as external plugins.
+type HTTPExecutor struct {
+	url    string
+	client *http.Client
+}
+
+// NewHTTPExecutor creates an executor that calls url for each job.
+// If client is nil, http.DefaultClient is used; the job context
+// still bounds each call.
+func NewHTTPExecutor(url string, client *http.Client) *HTTPExecutor {
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	return &HTTPExecutor{
+		url:    url,
+		client: client,
+	}
+}
+
+// URL returns the callback URL this executor posts to.
+func (e *HTTPExecutor) URL() string {
+	return e.url
+}
+
+// Execute POSTs the payload as JSON and treats any 2xx response as success.
+func (e *HTTPExecutor) Execute(ctx context.Context, payload []byte) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, e.url, bytes.NewReader(payload))
+	if err != nil {
+		return fmt.Errorf("failed to build callback request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if jc, ok := JobContextFrom(ctx); ok {
+		for name, value := range jc.headers() {
+			req.Header.Set(name, value)
+		}
+	}
+
+	resp, err := e.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("callback request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		// Include a bounded slice of the body to aid debugging
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("callback returned status %d: %s", resp.StatusCode, bytes.TrimSpace(body))
+	}
+
+	return nil
+}
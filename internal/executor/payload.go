@@ -0,0 +1,76 @@
+package executor
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// defaultSummaryLen is the summary length used when SummarizePayload gets max <= 0.
+const defaultSummaryLen = 256
+
+// redactedKeys are object keys whose values SummarizePayload hides,
+// matched case-insensitively anywhere in the payload.
+var redactedKeys = map[string]bool{
+	"password":      true,
+	"secret":        true,
+	"token":         true,
+	"api_key":       true,
+	"apikey":        true,
+	"authorization": true,
+}
+
+// SummarizePayload returns a short, log-safe rendering of a job payload.
+// JSON payloads are compacted, values of sensitive keys like "password"
+// are replaced with "[REDACTED]", and the result is cut to about max bytes.
+// Anything that isn't JSON is described by its size, never echoed.
+func SummarizePayload(payload []byte, max int) string {
+	if max <= 0 {
+		max = defaultSummaryLen
+	}
+	if len(payload) == 0 {
+		return "<empty>"
+	}
+
+	var value any
+	if err := json.Unmarshal(payload, &value); err != nil {
+		return fmt.Sprintf("<%d bytes, not JSON>", len(payload))
+	}
+
+	compact, err := json.Marshal(redact(value))
+	if err != nil {
+		return fmt.Sprintf("<%d bytes>", len(payload))
+	}
+
+	summary := string(compact)
+	if len(summary) <= max {
+		return summary
+	}
+
+	// Cut on a rune boundary so the summary stays valid UTF-8
+	cut := max
+	for cut > 0 && !utf8.RuneStart(summary[cut]) {
+		cut--
+	}
+	return fmt.Sprintf("%s... (%d bytes)", summary[:cut], len(payload))
+}
+
+// redact returns value with the values of redactedKeys replaced.
+func redact(value any) any {
+	switch v := value.(type) {
+	case map[string]any:
+		for key, inner := range v {
+			if redactedKeys[strings.ToLower(key)] {
+				v[key] = "[REDACTED]"
+			} else {
+				v[key] = redact(inner)
+			}
+		}
+	case []any:
+		for i, inner := range v {
+			v[i] = redact(inner)
+		}
+	}
+	return value
+}
@@ -0,0 +1,50 @@
+package executor
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestSummarizePayload_Truncates(t *testing.T) {
+	payload := []byte(`{"message": "` + strings.Repeat("é", 200) + `"}`)
+
+	got := SummarizePayload(payload, 50)
+	if !strings.HasPrefix(got, `{"message":"éé`) {
+		t.Errorf("Summary = %q, want it to start with the compacted payload", got)
+	}
+	if !strings.HasSuffix(got, "... (415 bytes)") {
+		t.Errorf("Summary = %q, want a truncation marker with the full size", got)
+	}
+	if !utf8.ValidString(got) {
+		t.Errorf("Summary %q is not valid UTF-8", got)
+	}
+
+	short := SummarizePayload([]byte(`{"a": 1}`), 50)
+	if short != `{"a":1}` {
+		t.Errorf("Summary = %q, want the whole compacted payload", short)
+	}
+}
+
+func TestSummarizePayload_NonJSON(t *testing.T) {
+	got := SummarizePayload([]byte{0xff, 0x00, 0x1b, 'h', 'i'}, 50)
+	if got != "<5 bytes, not JSON>" {
+		t.Errorf("Summary = %q, want \"<5 bytes, not JSON>\"", got)
+	}
+
+	if got := SummarizePayload(nil, 50); got != "<empty>" {
+		t.Errorf("Summary = %q, want \"<empty>\"", got)
+	}
+}
+
+func TestSummarizePayload_Redacts(t *testing.T) {
+	payload := []byte(`{"user": "ada", "Password": "hunter2", "auth": {"token": "abc"}}`)
+
+	got := SummarizePayload(payload, 200)
+	if strings.Contains(got, "hunter2") || strings.Contains(got, "abc") {
+		t.Errorf("Summary = %q, want secrets redacted", got)
+	}
+	if !strings.Contains(got, `"user":"ada"`) {
+		t.Errorf("Summary = %q, want non-sensitive fields kept", got)
+	}
+}
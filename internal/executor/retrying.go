@@ -0,0 +1,53 @@
+package executor
+
+import (
+	"context"
+	"log/slog"
+	"time"
+)
+
+// RetryingExecutor retries a failing executor within a single job attempt,
+// so a brief outage (e.g. an HTTP 503) doesn't use up one of the job's
+// attempts. Only the last error is reported once every try has failed.
+type RetryingExecutor struct {
+	base     Executor
+	attempts int
+	backoff  time.Duration
+}
+
+// Retrying wraps base so each Execute tries up to attempts times,
+// waiting backoff between tries. attempts <= 1 means no retries.
+func Retrying(base Executor, attempts int, backoff time.Duration) *RetryingExecutor {
+	return &RetryingExecutor{
+		base:     base,
+		attempts: max(attempts, 1),
+		backoff:  backoff,
+	}
+}
+
+// Execute runs the base executor until it succeeds, the tries run out,
+// or ctx is done. A cancelled context stops retrying immediately.
+func (e *RetryingExecutor) Execute(ctx context.Context, payload []byte) error {
+	var err error
+	for try := 1; try <= e.attempts; try++ {
+		if err = e.base.Execute(ctx, payload); err == nil {
+			return nil
+		}
+
+		if try == e.attempts || ctx.Err() != nil {
+			break
+		}
+
+		slog.DebugContext(ctx, "Executor failed, retrying within attempt",
+			"try", try, "of", e.attempts, "error", err)
+
+		timer := time.NewTimer(e.backoff)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return err
+		}
+	}
+	return err
+}
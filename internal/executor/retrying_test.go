@@ -0,0 +1,63 @@
+package executor
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// flakyExecutor fails the first e.failures calls, then succeeds.
+type flakyExecutor struct {
+	failures int
+	calls    int
+}
+
+func (e *flakyExecutor) Execute(ctx context.Context, payload []byte) error {
+	e.calls++
+	if e.calls <= e.failures {
+		return errors.New("503 service unavailable")
+	}
+	return nil
+}
+
+func TestRetrying_SucceedsWithinOneCall(t *testing.T) {
+	base := &flakyExecutor{failures: 2}
+
+	if err := Retrying(base, 3, time.Millisecond).Execute(context.Background(), nil); err != nil {
+		t.Fatalf("Execute failed: %v", err)
+	}
+	if base.calls != 3 {
+		t.Errorf("Base called %d times, want 3", base.calls)
+	}
+}
+
+func TestRetrying_ReportsLastErrorWhenExhausted(t *testing.T) {
+	base := &flakyExecutor{failures: 5}
+
+	err := Retrying(base, 2, time.Millisecond).Execute(context.Background(), nil)
+	if err == nil {
+		t.Fatal("Expected error once tries ran out")
+	}
+	if base.calls != 2 {
+		t.Errorf("Base called %d times, want 2", base.calls)
+	}
+}
+
+func TestRetrying_StopsWhenContextDone(t *testing.T) {
+	base := &flakyExecutor{failures: 5}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	if err := Retrying(base, 5, time.Hour).Execute(ctx, nil); err == nil {
+		t.Fatal("Expected error when the context ends mid-backoff")
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("Execute took %v, want it to stop at the deadline", elapsed)
+	}
+	if base.calls != 1 {
+		t.Errorf("Base called %d times, want 1", base.calls)
+	}
+}
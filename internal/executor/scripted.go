@@ -0,0 +1,62 @@
+package executor
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ScriptedExecutor runs jobs whose outcome is set by their payload,
+// so one registered type can exercise success, failure, and timeouts:
+//
+//	{"sleep_ms": 200}               sleeps 200ms, then succeeds
+//	{"fail": true}                  fails immediately
+//	{"sleep_ms": 50, "fail": true}  sleeps, then fails
+//	{"fail": true, "error": "boom"} fails with the given message
+//
+// An empty payload succeeds at once.
+type ScriptedExecutor struct{}
+
+// scriptedPayload is the payload understood by ScriptedExecutor.
+type scriptedPayload struct {
+	Fail    bool   `json:"fail"`
+	Error   string `json:"error"`
+	SleepMS int    `json:"sleep_ms"`
+}
+
+// NewScriptedExecutor creates a scripted executor.
+func NewScriptedExecutor() *ScriptedExecutor {
+	return &ScriptedExecutor{}
+}
+
+// Execute sleeps and succeeds or fails as the payload says.
+// Cancelling ctx interrupts the sleep.
+func (e *ScriptedExecutor) Execute(ctx context.Context, payload []byte) error {
+	var script scriptedPayload
+	if len(payload) > 0 {
+		if err := json.Unmarshal(payload, &script); err != nil {
+			return fmt.Errorf("invalid payload: %w", err)
+		}
+	}
+
+	if script.SleepMS > 0 {
+		timer := time.NewTimer(time.Duration(script.SleepMS) * time.Millisecond)
+		defer timer.Stop()
+
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+
+	if script.Fail {
+		if script.Error != "" {
+			return errors.New(script.Error)
+		}
+		return fmt.Errorf("scripted failure")
+	}
+	return nil
+}
@@ -0,0 +1,65 @@
+package executor
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestScriptedExecutor_Succeeds(t *testing.T) {
+	exec := NewScriptedExecutor()
+
+	for _, payload := range []string{``, `{}`, `{"fail": false}`} {
+		if err := exec.Execute(context.Background(), []byte(payload)); err != nil {
+			t.Errorf("Execute(%q) = %v, want nil", payload, err)
+		}
+	}
+}
+
+func TestScriptedExecutor_Fails(t *testing.T) {
+	exec := NewScriptedExecutor()
+
+	if err := exec.Execute(context.Background(), []byte(`{"fail": true}`)); err == nil {
+		t.Error("Expected scripted failure")
+	}
+
+	err := exec.Execute(context.Background(), []byte(`{"fail": true, "error": "disk full"}`))
+	if err == nil || err.Error() != "disk full" {
+		t.Errorf("Execute error = %v, want \"disk full\"", err)
+	}
+}
+
+func TestScriptedExecutor_Sleeps(t *testing.T) {
+	exec := NewScriptedExecutor()
+
+	start := time.Now()
+	if err := exec.Execute(context.Background(), []byte(`{"sleep_ms": 50}`)); err != nil {
+		t.Fatalf("Execute failed: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed < 50*time.Millisecond {
+		t.Errorf("Execute returned after %v, want at least 50ms", elapsed)
+	}
+}
+
+func TestScriptedExecutor_SleepHonorsTimeout(t *testing.T) {
+	exec := NewScriptedExecutor()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	err := exec.Execute(ctx, []byte(`{"sleep_ms": 5000}`))
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Execute error = %v, want context.DeadlineExceeded", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("Execute took %v after the deadline, want it interrupted", elapsed)
+	}
+}
+
+func TestScriptedExecutor_InvalidPayload(t *testing.T) {
+	if err := NewScriptedExecutor().Execute(context.Background(), []byte(`not json`)); err == nil {
+		t.Error("Expected error for invalid payload")
+	}
+}
@@ -0,0 +1,133 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             (unknown)
+// source: proto/worker/v1/worker.proto
+
+package workerpb
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	JobWorker_Execute_FullMethodName = "/orchestrix.worker.v1.JobWorker/Execute"
+)
+
+// JobWorkerClient is the client API for JobWorker service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// JobWorker is implemented by external services that run jobs on
+// Orchestrix's behalf. Orchestrix is the client; see executor.GRPCExecutor.
+type JobWorkerClient interface {
+	// Execute runs one attempt of a job. The job's outcome is reported in
+	// JobResult; an RPC error means the attempt couldn't be run, and
+	// Orchestrix retries it if the job has attempts left.
+	Execute(ctx context.Context, in *JobRequest, opts ...grpc.CallOption) (*JobResult, error)
+}
+
+type jobWorkerClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewJobWorkerClient(cc grpc.ClientConnInterface) JobWorkerClient {
+	return &jobWorkerClient{cc}
+}
+
+func (c *jobWorkerClient) Execute(ctx context.Context, in *JobRequest, opts ...grpc.CallOption) (*JobResult, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(JobResult)
+	err := c.cc.Invoke(ctx, JobWorker_Execute_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// JobWorkerServer is the server API for JobWorker service.
+// All implementations must embed UnimplementedJobWorkerServer
+// for forward compatibility.
+//
+// JobWorker is implemented by external services that run jobs on
+// Orchestrix's behalf. Orchestrix is the client; see executor.GRPCExecutor.
+type JobWorkerServer interface {
+	// Execute runs one attempt of a job. The job's outcome is reported in
+	// JobResult; an RPC error means the attempt couldn't be run, and
+	// Orchestrix retries it if the job has attempts left.
+	Execute(context.Context, *JobRequest) (*JobResult, error)
+	mustEmbedUnimplementedJobWorkerServer()
+}
+
+// UnimplementedJobWorkerServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedJobWorkerServer struct{}
+
+func (UnimplementedJobWorkerServer) Execute(context.Context, *JobRequest) (*JobResult, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Execute not implemented")
+}
+func (UnimplementedJobWorkerServer) mustEmbedUnimplementedJobWorkerServer() {}
+func (UnimplementedJobWorkerServer) testEmbeddedByValue()                   {}
+
+// UnsafeJobWorkerServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to JobWorkerServer will
+// result in compilation errors.
+type UnsafeJobWorkerServer interface {
+	mustEmbedUnimplementedJobWorkerServer()
+}
+
+func RegisterJobWorkerServer(s grpc.ServiceRegistrar, srv JobWorkerServer) {
+	// If the following call pancis, it indicates UnimplementedJobWorkerServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&JobWorker_ServiceDesc, srv)
+}
+
+func _JobWorker_Execute_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(JobRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(JobWorkerServer).Execute(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: JobWorker_Execute_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(JobWorkerServer).Execute(ctx, req.(*JobRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// JobWorker_ServiceDesc is the grpc.ServiceDesc for JobWorker service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var JobWorker_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "orchestrix.worker.v1.JobWorker",
+	HandlerType: (*JobWorkerServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Execute",
+			Handler:    _JobWorker_Execute_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "proto/worker/v1/worker.proto",
+}
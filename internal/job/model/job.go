@@ -2,6 +2,7 @@ package model
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"time"
 
@@ -24,6 +25,10 @@ type Job struct {
 	// Example for "send_email": {"to": "user@example.com", "subject": "Hi"}
 	Payload []byte
 
+	// PayloadRef is the blob store key of a payload too large to keep in
+	// the database. When set, Payload is empty until it's loaded from the store.
+	PayloadRef *string
+
 	// State tracks the current lifecycle state of the job.
 	State state.State
 
@@ -54,6 +59,66 @@ type Job struct {
 	// CompletedAt is when the job finished (success or permanent failure).
 	// Nil until the job reaches a terminal state.
 	CompletedAt *time.Time
+
+	// LastTransitionReason explains why the job last changed state,
+	// e.g. "user cancelled via API". Nil if no reason was given.
+	LastTransitionReason *string
+
+	// Priority orders claimable jobs; higher values are claimed first.
+	// Jobs with equal priority are claimed oldest first. Defaults to 0.
+	Priority int
+
+	// NextRetryAt is when a RETRYING job's backoff expires.
+	// The scheduler won't claim the job before then. Nil means no delay.
+	NextRetryAt *time.Time
+
+	// RunAt is the earliest time a PENDING job may be claimed.
+	// Nil means the job can run as soon as a worker is free.
+	RunAt *time.Time
+
+	// Schedule is the cron expression of a recurring job, e.g. "0 3 * * *".
+	// When a recurring job succeeds or fails, its next occurrence is
+	// created with RunAt set from this schedule. Nil for one-off jobs.
+	Schedule *string
+
+	// ParentID is the ID of the first job in this job's recurring series.
+	// Nil for one-off jobs and for the first job of a series.
+	ParentID *string
+
+	// Result is the JSON output of a SUCCEEDED job.
+	// Nil until a result has been recorded.
+	Result []byte
+
+	// RerunCount is how many times the job was run again after finishing,
+	// via JobService.RerunJob. Zero for jobs that have only run once.
+	RerunCount int
+
+	// IdempotencyKey is a client-chosen key that makes creating this job
+	// safe to retry: a second create with the same key and type returns
+	// this job instead of making another. Nil if none was given.
+	IdempotencyKey *string
+}
+
+// AttemptError records the error from a single failed execution attempt.
+// A job keeps one entry per failed attempt, even if it later succeeds.
+type AttemptError struct {
+	// JobID is the job this error belongs to.
+	JobID string
+
+	// Attempt is the attempt number that failed (1-indexed).
+	Attempt int
+
+	// Error is the error message returned by the executor.
+	Error string
+
+	// OccurredAt is when the failure was recorded.
+	OccurredAt time.Time
+}
+
+// ErrorCount is how many jobs share an error message.
+type ErrorCount struct {
+	Error string
+	Count int
 }
 
 // IsTerminal returns true if the job is in a terminal state.
@@ -62,6 +127,11 @@ func (j *Job) IsTerminal() bool {
 	return j.State.IsTerminal()
 }
 
+// IsRecurring returns true if the job re-enqueues itself on a schedule.
+func (j *Job) IsRecurring() bool {
+	return j.Schedule != nil
+}
+
 // CanRetry returns true if the job can be retried after a failure.
 // This is based on whether we've exhausted the maximum attempts.
 func (j *Job) CanRetry() bool {
@@ -88,6 +158,32 @@ func (j *Job) ClearError() {
 	j.LastError = nil
 }
 
+// ErrPayloadTooLarge is returned by ValidateWithLimits for a payload over
+// the configured size.
+var ErrPayloadTooLarge = errors.New("job payload too large")
+
+// ValidationLimits are size limits checked by ValidateWithLimits.
+// Zero values mean no limit.
+type ValidationLimits struct {
+	// MaxPayloadBytes caps the payload size, e.g. to what the database
+	// column or a downstream consumer can hold.
+	MaxPayloadBytes int
+}
+
+// ValidateWithLimits runs Validate and then checks the job against limits.
+// An oversized payload fails with ErrPayloadTooLarge.
+func (j *Job) ValidateWithLimits(limits ValidationLimits) error {
+	if err := j.Validate(); err != nil {
+		return err
+	}
+
+	if limits.MaxPayloadBytes > 0 && len(j.Payload) > limits.MaxPayloadBytes {
+		return fmt.Errorf("%w: %d bytes, limit is %d", ErrPayloadTooLarge, len(j.Payload), limits.MaxPayloadBytes)
+	}
+
+	return nil
+}
+
 // Validate checks if the job has valid data.
 // Returns an error if any validation rule is violated.
 func (j *Job) Validate() error {
@@ -123,9 +219,9 @@ func (j *Job) Validate() error {
 		return fmt.Errorf("attempt %d exceeds max attempts %d", j.Attempt, j.MaxAttempts)
 	}
 
-	// Attempt must be positive if job has started
-	if j.Attempt < 0 {
-		return fmt.Errorf("attempt must be non-negative, got %d", j.Attempt)
+	// Attempts are 1-indexed; Attempt 0 would let CanRetry allow an extra try
+	if j.Attempt < 1 {
+		return fmt.Errorf("attempt must be at least 1, got %d", j.Attempt)
 	}
 
 	return nil
@@ -2,6 +2,7 @@ package model
 
 import (
 	"errors"
+	"strings"
 	"testing"
 	"time"
 
@@ -103,6 +104,10 @@ func TestJob_CanRetry(t *testing.T) {
 		// This shouldn't happen, but we test it anyway (defensive programming)
 		// 4 < 3 → false → Cannot retry
 		{"exceeded attempts cannot retry", 4, 3, false},
+
+		// Scenario 5: MaxAttempts=1 means run once, never retry
+		// 1 < 1 → false → Straight to FAILED
+		{"single attempt cannot retry", 1, 1, false},
 	}
 
 	for _, tt := range tests {
@@ -253,7 +258,7 @@ func TestJob_Validate(t *testing.T) {
 		Type:        "send_email",                        // Required: What kind of work
 		Payload:     []byte(`{"to":"user@example.com"}`), // Valid JSON
 		State:       state.PENDING,                       // Valid state
-		Attempt:     1,                                   // Current attempt (>= 0)
+		Attempt:     1,                                   // Current attempt (>= 1)
 		MaxAttempts: 3,                                   // Max attempts (>= 1)
 		CreatedAt:   time.Now(),                          // Timestamp
 	}
@@ -340,12 +345,75 @@ func TestJob_Validate(t *testing.T) {
 		}
 	})
 
+	// Test 8: Attempt below 1 should fail
+	// WHY? Attempts are 1-indexed: CreateJob starts at 1
+	// With Attempt = 0, CanRetry (Attempt < MaxAttempts) would allow MaxAttempts+1 tries
+	t.Run("attempt negative", func(t *testing.T) {
+		job := *validJob
+		job.Attempt = -1
+		if err := job.Validate(); err == nil {
+			t.Error("Expected error for Attempt < 0")
+		}
+	})
+
+	t.Run("attempt zero", func(t *testing.T) {
+		job := *validJob
+		job.Attempt = 0 // Never valid: the first attempt is 1
+		if err := job.Validate(); err == nil {
+			t.Error("Expected error for Attempt = 0")
+		}
+	})
+
 	// SUMMARY OF WHAT WE'RE TESTING:
 	// ✓ Required fields must not be empty (ID, Type)
 	// ✓ JSON must be well-formed (Payload)
 	// ✓ State must be recognized (State)
-	// ✓ Counters must be logical (MaxAttempts >= 1, Attempt <= MaxAttempts)
+	// ✓ Counters must be logical (MaxAttempts >= 1, 1 <= Attempt <= MaxAttempts)
 	//
 	// These rules protect the system from garbage data that would cause
 	// crashes, infinite loops, or undefined behavior.
 }
+
+func TestJob_ValidateWithLimits(t *testing.T) {
+	newJob := func(payloadBytes int) *Job {
+		// A JSON string of payloadBytes bytes in total, quotes included
+		payload := []byte(`"` + strings.Repeat("x", payloadBytes-2) + `"`)
+		return &Job{
+			ID:          "job_123",
+			Type:        "send_email",
+			Payload:     payload,
+			State:       state.PENDING,
+			Attempt:     1,
+			MaxAttempts: 3,
+			CreatedAt:   time.Now(),
+		}
+	}
+	limits := ValidationLimits{MaxPayloadBytes: 1024}
+
+	t.Run("at the limit", func(t *testing.T) {
+		if err := newJob(1024).ValidateWithLimits(limits); err != nil {
+			t.Errorf("Payload of exactly the limit failed validation: %v", err)
+		}
+	})
+
+	t.Run("one byte over", func(t *testing.T) {
+		err := newJob(1025).ValidateWithLimits(limits)
+		if !errors.Is(err, ErrPayloadTooLarge) {
+			t.Errorf("Error = %v, want ErrPayloadTooLarge", err)
+		}
+	})
+
+	t.Run("no limit", func(t *testing.T) {
+		if err := newJob(1 << 20).ValidateWithLimits(ValidationLimits{}); err != nil {
+			t.Errorf("Zero limits rejected a large payload: %v", err)
+		}
+	})
+
+	t.Run("other rules still apply", func(t *testing.T) {
+		job := newJob(10)
+		job.ID = ""
+		if err := job.ValidateWithLimits(limits); err == nil {
+			t.Error("Expected error for missing ID")
+		}
+	})
+}
@@ -0,0 +1,46 @@
+package repository
+
+import (
+	"fmt"
+	"math"
+	"time"
+)
+
+// ClaimPolicy decides the order in which claimable jobs are handed out.
+// Higher-priority jobs always come first (after priority aging, if enabled);
+// the policy orders jobs of equal priority.
+type ClaimPolicy int
+
+const (
+	// ClaimFIFO claims the oldest jobs first, whether new or retrying.
+	// This is the default.
+	ClaimFIFO ClaimPolicy = iota
+
+	// ClaimRetriesFirst claims due RETRYING jobs ahead of PENDING ones,
+	// oldest first within each, so new work can't crowd out retries.
+	ClaimRetriesFirst
+)
+
+// ParseClaimPolicy parses "fifo" or "retries_first".
+// An empty string is ClaimFIFO.
+func ParseClaimPolicy(s string) (ClaimPolicy, error) {
+	switch s {
+	case "", "fifo":
+		return ClaimFIFO, nil
+	case "retries_first":
+		return ClaimRetriesFirst, nil
+	default:
+		return ClaimFIFO, fmt.Errorf("unknown claim policy %q (want fifo or retries_first)", s)
+	}
+}
+
+// agedPriority is a job's priority raised by one for every full aging
+// step it has waited since creation, so old low-priority jobs eventually
+// outrank a steady stream of newer higher-priority ones.
+// A step of zero or less disables aging.
+func agedPriority(priority int, createdAt, now time.Time, step time.Duration) float64 {
+	if step <= 0 {
+		return float64(priority)
+	}
+	return float64(priority) + math.Floor(now.Sub(createdAt).Seconds()/step.Seconds())
+}
@@ -3,6 +3,9 @@ package repository
 import (
 	"context"
 	"fmt"
+	"math/rand"
+	"net/url"
+	"strconv"
 	"time"
 
 	"github.com/jackc/pgx/v5/pgxpool"
@@ -20,23 +23,40 @@ type DBConfig struct {
 	MinConnections  int
 	MaxConnLifetime time.Duration
 	MaxConnIdleTime time.Duration
+
+	// PingAttempts is how many times to ping the database before giving up.
+	// Useful on container startup when the app can start before the DB.
+	// Values <= 0 mean a single attempt.
+	PingAttempts int
+
+	// PingBackoff is the initial delay between ping attempts, doubled after
+	// each failure and jittered. PingMaxBackoff caps the delay
+	// (defaults to defaultPingMaxBackoff when zero).
+	PingBackoff    time.Duration
+	PingMaxBackoff time.Duration
+
+	// AcquireTimeout bounds how long a repository operation waits for a
+	// free connection when all MaxConnections are busy. Zero means no
+	// limit beyond the caller's context. See PostgresJobRepository.SetAcquireTimeout.
+	AcquireTimeout time.Duration
+
+	// Params are extra connection string parameters, such as
+	// connect_timeout or statement_timeout. Unknown keys are sent to the
+	// server as runtime parameters. application_name defaults to
+	// defaultApplicationName; SSLMode always sets sslmode.
+	Params map[string]string
 }
 
+// defaultApplicationName identifies our connections in pg_stat_activity.
+const defaultApplicationName = "orchestrix"
+
+// defaultPingMaxBackoff caps the ping retry delay when PingMaxBackoff is unset.
+const defaultPingMaxBackoff = 5 * time.Second
+
 // NewConnectionPool creates a new PostgreSQL connection pool.
 func NewConnectionPool(ctx context.Context, cfg DBConfig) (*pgxpool.Pool, error) {
-	// Build connection string
-	dsn := fmt.Sprintf(
-		"postgres://%s:%s@%s:%d/%s?sslmode=%s",
-		cfg.User,
-		cfg.Password,
-		cfg.Host,
-		cfg.Port,
-		cfg.Database,
-		cfg.SSLMode,
-	)
-
 	// Parse connection string and configure pool
-	config, err := pgxpool.ParseConfig(dsn)
+	config, err := pgxpool.ParseConfig(buildDSN(cfg))
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse database config: %w", err)
 	}
@@ -50,18 +70,86 @@ func NewConnectionPool(ctx context.Context, cfg DBConfig) (*pgxpool.Pool, error)
 	// Create the pool
 	pool, err := pgxpool.NewWithConfig(ctx, config)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create connection pool: %w", err)
+		return nil, fmt.Errorf("failed to create connection pool: %w", classify(err))
 	}
 
-	// Test the connection
-	if err := pool.Ping(ctx); err != nil {
+	// Test the connection, waiting for the database to come up if needed
+	if err := pingWithRetry(ctx, pool.Ping, cfg.PingAttempts, cfg.PingBackoff, cfg.PingMaxBackoff); err != nil {
 		pool.Close()
-		return nil, fmt.Errorf("failed to ping database: %w", err)
+		return nil, fmt.Errorf("failed to ping database: %w", classify(err))
 	}
 
 	return pool, nil
 }
 
+// buildDSN builds the postgres:// connection string for cfg.
+// Credentials are escaped, so they may contain URL special characters.
+func buildDSN(cfg DBConfig) string {
+	query := url.Values{}
+	query.Set("application_name", defaultApplicationName)
+	for key, value := range cfg.Params {
+		query.Set(key, value)
+	}
+	query.Set("sslmode", cfg.SSLMode)
+
+	dsn := url.URL{
+		Scheme:   "postgres",
+		User:     url.UserPassword(cfg.User, cfg.Password),
+		Host:     cfg.Host + ":" + strconv.Itoa(cfg.Port),
+		Path:     "/" + cfg.Database,
+		RawQuery: query.Encode(),
+	}
+	return dsn.String()
+}
+
+// pingWithRetry calls ping until it succeeds, retrying with jittered
+// exponential backoff. Gives up after attempts tries or when ctx is done.
+func pingWithRetry(
+	ctx context.Context,
+	ping func(context.Context) error,
+	attempts int,
+	backoff time.Duration,
+	maxBackoff time.Duration,
+) error {
+	if attempts <= 0 {
+		attempts = 1
+	}
+	if maxBackoff <= 0 {
+		maxBackoff = defaultPingMaxBackoff
+	}
+
+	var err error
+	delay := backoff
+	for attempt := 1; attempt <= attempts; attempt++ {
+		if err = ping(ctx); err == nil {
+			return nil
+		}
+
+		if attempt == attempts {
+			break
+		}
+
+		// Cap, then jitter into [delay/2, delay) to avoid synchronized retries
+		if delay > maxBackoff {
+			delay = maxBackoff
+		}
+		wait := delay
+		if half := int64(delay / 2); half > 0 {
+			wait = time.Duration(half + rand.Int63n(half))
+		}
+
+		select {
+		case <-time.After(wait):
+		case <-ctx.Done():
+			return fmt.Errorf("gave up after %d attempts: %w", attempt, ctx.Err())
+		}
+
+		delay *= 2
+	}
+
+	return fmt.Errorf("gave up after %d attempts: %w", attempts, err)
+}
+
 // ClosePool gracefully closes the connection pool.
 func ClosePool(pool *pgxpool.Pool) {
 	if pool != nil {
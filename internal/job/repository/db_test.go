@@ -0,0 +1,174 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+func TestPingWithRetry_EventuallySucceeds(t *testing.T) {
+	calls := 0
+	ping := func(ctx context.Context) error {
+		calls++
+		if calls < 3 {
+			return errors.New("connection refused")
+		}
+		return nil
+	}
+
+	err := pingWithRetry(context.Background(), ping, 5, time.Millisecond, 10*time.Millisecond)
+	if err != nil {
+		t.Fatalf("pingWithRetry failed: %v", err)
+	}
+	if calls != 3 {
+		t.Errorf("ping called %d times, want 3", calls)
+	}
+}
+
+func TestPingWithRetry_RespectsContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	calls := 0
+	ping := func(ctx context.Context) error {
+		calls++
+		return errors.New("connection refused")
+	}
+
+	err := pingWithRetry(ctx, ping, 5, time.Second, time.Second)
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("Expected context.Canceled, got %v", err)
+	}
+	if calls != 1 {
+		t.Errorf("ping called %d times after cancellation, want 1", calls)
+	}
+}
+
+func TestBuildDSN_Params(t *testing.T) {
+	cfg := DBConfig{
+		Host:     "db.internal",
+		Port:     5432,
+		User:     "orchestrix",
+		Password: "p@ss/word",
+		Database: "orchestrix_dev",
+		SSLMode:  "require",
+		Params: map[string]string{
+			"connect_timeout":   "5",
+			"statement_timeout": "30000",
+		},
+	}
+
+	dsn := buildDSN(cfg)
+	for _, want := range []string{"connect_timeout=5", "statement_timeout=30000", "application_name=orchestrix", "sslmode=require"} {
+		if !strings.Contains(dsn, want) {
+			t.Errorf("DSN %q does not include %s", dsn, want)
+		}
+	}
+
+	// pgx must read it back the way we meant it
+	config, err := pgxpool.ParseConfig(dsn)
+	if err != nil {
+		t.Fatalf("ParseConfig failed: %v", err)
+	}
+	if config.ConnConfig.Password != "p@ss/word" || config.ConnConfig.Host != "db.internal" {
+		t.Errorf("Password %q, host %q; want the configured ones", config.ConnConfig.Password, config.ConnConfig.Host)
+	}
+	if config.ConnConfig.ConnectTimeout != 5*time.Second {
+		t.Errorf("ConnectTimeout = %v, want 5s", config.ConnConfig.ConnectTimeout)
+	}
+	if got := config.ConnConfig.RuntimeParams["statement_timeout"]; got != "30000" {
+		t.Errorf("statement_timeout = %q, want 30000", got)
+	}
+
+	// Params can override the default application name
+	cfg.Params = map[string]string{"application_name": "orchestrix-worker"}
+	if dsn := buildDSN(cfg); !strings.Contains(dsn, "application_name=orchestrix-worker") {
+		t.Errorf("DSN %q does not use the configured application_name", dsn)
+	}
+}
+
+func TestNewConnectionPool_DeadPort(t *testing.T) {
+	cfg := DBConfig{
+		Host:           "127.0.0.1",
+		Port:           1, // nothing listens here
+		User:           "orchestrix",
+		Password:       "unused",
+		Database:       "orchestrix_dev",
+		SSLMode:        "disable",
+		MaxConnections: 1,
+		PingAttempts:   3,
+		PingBackoff:    time.Millisecond,
+		PingMaxBackoff: 5 * time.Millisecond,
+	}
+
+	_, err := NewConnectionPool(context.Background(), cfg)
+	if err == nil {
+		t.Fatal("Expected error connecting to dead port")
+	}
+	if !strings.Contains(err.Error(), "gave up after 3 attempts") {
+		t.Errorf("Error = %q, want it to report 3 attempts", err)
+	}
+}
+
+func TestNewConnectionPool_PingFailureIsConnectionError(t *testing.T) {
+	// Nothing listens on port 1, so the ping is refused
+	cfg := DBConfig{
+		Host:           "127.0.0.1",
+		Port:           1,
+		User:           "orchestrix",
+		Password:       "unused",
+		Database:       "orchestrix",
+		SSLMode:        "disable",
+		MaxConnections: 1,
+		PingAttempts:   1,
+	}
+
+	_, err := NewConnectionPool(context.Background(), cfg)
+	if err == nil {
+		t.Fatal("Expected ping failure")
+	}
+	if !errors.Is(err, ErrConnection) {
+		t.Errorf("Error %v is not ErrConnection", err)
+	}
+	if errors.Is(err, ErrQuery) {
+		t.Errorf("Error %v is also ErrQuery, want only ErrConnection", err)
+	}
+}
+
+func TestClassify(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want error
+	}{
+		{"constraint violation", &pgconn.PgError{Code: "23502"}, ErrQuery},
+		{"server shutting down", &pgconn.PgError{Code: "57P01"}, ErrConnection},
+		{"connection exception", &pgconn.PgError{Code: "08006"}, ErrConnection},
+		{"pool exhausted", ErrPoolExhausted, ErrConnection},
+		{"connection dropped", io.ErrUnexpectedEOF, ErrConnection},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := fmt.Errorf("failed to update job: %w", classify(tt.err))
+			if !errors.Is(err, tt.want) {
+				t.Errorf("classify(%v) is not %v", tt.err, tt.want)
+			}
+			if !errors.Is(err, tt.err) {
+				t.Errorf("classify(%v) lost the original error", tt.err)
+			}
+		})
+	}
+
+	// Cancellation is the caller's, not the database's
+	if err := classify(context.Canceled); errors.Is(err, ErrConnection) || errors.Is(err, ErrQuery) {
+		t.Errorf("classify(context.Canceled) = %v, want it unclassified", err)
+	}
+}
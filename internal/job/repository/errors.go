@@ -0,0 +1,71 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"strings"
+
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// Database errors are classified so callers can decide how to react
+// with errors.Is, while the original error stays in the chain.
+var (
+	// ErrConnection means the database couldn't be reached or the
+	// connection broke. Usually transient: back off and retry.
+	ErrConnection = errors.New("database connection error")
+
+	// ErrQuery means the database rejected the statement, e.g. a
+	// constraint or syntax error. Retrying won't help.
+	ErrQuery = errors.New("database query error")
+)
+
+// classifiedError tags a database error with ErrConnection or ErrQuery.
+// Its message is the original error's, so wrapping it reads as before.
+type classifiedError struct {
+	class error
+	err   error
+}
+
+func (e *classifiedError) Error() string { return e.err.Error() }
+
+func (e *classifiedError) Unwrap() []error { return []error{e.class, e.err} }
+
+// classify tags err as a connection or query error. Context errors are
+// the caller's doing and are returned unchanged, as are nil and errors
+// that are already classified.
+func classify(err error) error {
+	if err == nil ||
+		errors.Is(err, ErrConnection) || errors.Is(err, ErrQuery) ||
+		errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return err
+	}
+
+	if isConnectionError(err) {
+		return &classifiedError{class: ErrConnection, err: err}
+	}
+	return &classifiedError{class: ErrQuery, err: err}
+}
+
+// isConnectionError reports whether err came from reaching or talking to
+// the server rather than from the statement itself.
+func isConnectionError(err error) bool {
+	var connectErr *pgconn.ConnectError
+	if errors.As(err, &connectErr) {
+		return true
+	}
+
+	// SQLSTATE class 08 is "connection exception"; 57P0x is the server
+	// shutting down or restarting
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		return strings.HasPrefix(pgErr.Code, "08") || strings.HasPrefix(pgErr.Code, "57P0")
+	}
+
+	var netErr net.Error
+	return errors.Is(err, ErrPoolExhausted) ||
+		errors.As(err, &netErr) ||
+		errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF)
+}
@@ -0,0 +1,651 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/dipak0000812/orchestrix/internal/job/model"
+	"github.com/dipak0000812/orchestrix/internal/job/state"
+)
+
+// MemoryJobRepository implements JobRepository in memory.
+// Intended for tests and local development — nothing is persisted.
+//
+// Jobs are copied on the way in and out so callers can't mutate stored
+// state without going through the repository, mirroring a real database.
+type MemoryJobRepository struct {
+	mu     sync.Mutex
+	jobs   map[string]*model.Job
+	errors map[string][]model.AttemptError
+
+	// txMu serializes WithTx calls. Calls made outside a transaction
+	// are not blocked by it, unlike row locks in a real database.
+	txMu sync.Mutex
+
+	claimPolicy ClaimPolicy
+
+	// priorityAging raises a job's claim priority by one per step waited;
+	// zero disables it.
+	priorityAging time.Duration
+
+	// allowDestructive enables TruncateAll, as on the Postgres repository.
+	allowDestructive bool
+}
+
+// NewMemoryJobRepository creates an empty in-memory job repository.
+func NewMemoryJobRepository() *MemoryJobRepository {
+	return &MemoryJobRepository{
+		jobs:   make(map[string]*model.Job),
+		errors: make(map[string][]model.AttemptError),
+	}
+}
+
+// Create inserts a new job.
+func (r *MemoryJobRepository) Create(ctx context.Context, job *model.Job) error {
+	if err := ctx.Err(); err != nil {
+		return fmt.Errorf("failed to create job: %w", err)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.jobs[job.ID]; exists {
+		return fmt.Errorf("%w: %s", ErrDuplicateJob, job.ID)
+	}
+	if job.IdempotencyKey != nil && r.findByIdempotencyKey(*job.IdempotencyKey, job.Type) != nil {
+		return fmt.Errorf("%w: idempotency key %s for type %s", ErrDuplicateJob, *job.IdempotencyKey, job.Type)
+	}
+
+	r.jobs[job.ID] = copyJob(job)
+	return nil
+}
+
+// GetByIdempotencyKey retrieves the job of a type created with key.
+// Returns nil if there is none.
+func (r *MemoryJobRepository) GetByIdempotencyKey(ctx context.Context, key, jobType string) (*model.Job, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if job := r.findByIdempotencyKey(key, jobType); job != nil {
+		return copyJob(job), nil
+	}
+	return nil, nil
+}
+
+// findByIdempotencyKey returns the stored job with key and type, or nil.
+// Caller must hold mu.
+func (r *MemoryJobRepository) findByIdempotencyKey(key, jobType string) *model.Job {
+	for _, job := range r.jobs {
+		if job.IdempotencyKey != nil && *job.IdempotencyKey == key && job.Type == jobType {
+			return job
+		}
+	}
+	return nil
+}
+
+// GetByID retrieves a job by its ID.
+// Returns nil if the job doesn't exist.
+func (r *MemoryJobRepository) GetByID(ctx context.Context, id string) (*model.Job, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	job, exists := r.jobs[id]
+	if !exists {
+		return nil, nil
+	}
+	return copyJob(job), nil
+}
+
+// GetByIDForUpdate is GetByID. WithTx calls are already serialized,
+// so there is no row to lock.
+func (r *MemoryJobRepository) GetByIDForUpdate(ctx context.Context, id string) (*model.Job, error) {
+	return r.GetByID(ctx, id)
+}
+
+// GetStates returns the state of each listed job that exists, keyed by ID.
+func (r *MemoryJobRepository) GetStates(ctx context.Context, ids []string) (map[string]state.State, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	states := make(map[string]state.State, len(ids))
+	for _, id := range ids {
+		if job, exists := r.jobs[id]; exists {
+			states[id] = job.State
+		}
+	}
+	return states, nil
+}
+
+// Exists reports whether a job with the given ID exists.
+func (r *MemoryJobRepository) Exists(ctx context.Context, id string) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	_, exists := r.jobs[id]
+	return exists, nil
+}
+
+// UpdateState updates only the state field of a job.
+func (r *MemoryJobRepository) UpdateState(ctx context.Context, id string, newState state.State) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	job, exists := r.jobs[id]
+	if !exists {
+		return fmt.Errorf("job not found: %s", id)
+	}
+	job.State = newState
+	return nil
+}
+
+// UpdateStateBatch updates the state of every listed job still in from.
+func (r *MemoryJobRepository) UpdateStateBatch(ctx context.Context, ids []string, from, to state.State, reason string) ([]string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	updated := []string{}
+	for _, id := range ids {
+		job, exists := r.jobs[id]
+		if !exists || job.State != from {
+			continue
+		}
+		job.State = to
+		job.LastTransitionReason = nil
+		if reason != "" {
+			job.LastTransitionReason = &reason
+		}
+		if isUnclaimed(to) {
+			job.ScheduledAt = nil
+			job.StartedAt = nil
+		}
+		updated = append(updated, id)
+	}
+	return updated, nil
+}
+
+// isUnclaimed reports whether jobs in s are waiting to be claimed.
+func isUnclaimed(s state.State) bool {
+	return s == state.PENDING || s == state.RETRYING
+}
+
+// ListByState returns jobs with a specific state, ordered by creation time.
+func (r *MemoryJobRepository) ListByState(ctx context.Context, jobState state.State, limit int) ([]*model.Job, error) {
+	return r.ListByStateOrdered(ctx, jobState, limit, SortAsc)
+}
+
+// ListByStateOrdered returns jobs with a specific state in the given creation order.
+func (r *MemoryJobRepository) ListByStateOrdered(ctx context.Context, jobState state.State, limit int, order SortOrder) ([]*model.Job, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	sorted := r.sortedLocked()
+	if order == SortDesc {
+		slices.Reverse(sorted)
+	}
+
+	var jobs []*model.Job
+	for _, job := range sorted {
+		if job.State != jobState {
+			continue
+		}
+		jobs = append(jobs, copyJob(job))
+		if len(jobs) >= limit {
+			break
+		}
+	}
+	return jobs, nil
+}
+
+// ListByResultKey returns SUCCEEDED jobs whose result has key set to value,
+// ordered by creation time.
+func (r *MemoryJobRepository) ListByResultKey(ctx context.Context, key, value string, limit int) ([]*model.Job, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	jobs := []*model.Job{}
+	for _, job := range r.sortedLocked() {
+		if job.State != state.SUCCEEDED || job.Result == nil {
+			continue
+		}
+		var result map[string]any
+		if err := json.Unmarshal(job.Result, &result); err != nil {
+			continue // not an object, so it can't contain the key
+		}
+		if got, ok := result[key].(string); !ok || got != value {
+			continue
+		}
+		jobs = append(jobs, copyJob(job))
+		if len(jobs) >= limit {
+			break
+		}
+	}
+	return jobs, nil
+}
+
+// CountByState returns how many jobs are in any of the given states.
+func (r *MemoryJobRepository) CountByState(ctx context.Context, states ...state.State) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	count := 0
+	for _, job := range r.jobs {
+		for _, s := range states {
+			if job.State == s {
+				count++
+				break
+			}
+		}
+	}
+	return count, nil
+}
+
+// CountByTypeAndState returns job counts grouped by type and state.
+func (r *MemoryJobRepository) CountByTypeAndState(ctx context.Context) (map[string]map[state.State]int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	counts := make(map[string]map[state.State]int)
+	for _, job := range r.jobs {
+		if counts[job.Type] == nil {
+			counts[job.Type] = make(map[state.State]int)
+		}
+		counts[job.Type][job.State]++
+	}
+	return counts, nil
+}
+
+// OldestPendingAge returns how long the longest-waiting due PENDING job has been claimable.
+func (r *MemoryJobRepository) OldestPendingAge(ctx context.Context, now time.Time) (time.Duration, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var oldest time.Duration
+	for _, job := range r.jobs {
+		if job.State != state.PENDING {
+			continue
+		}
+		dueAt := job.CreatedAt
+		if job.RunAt != nil {
+			if job.RunAt.After(now) {
+				continue
+			}
+			if job.RunAt.After(dueAt) {
+				dueAt = *job.RunAt
+			}
+		}
+		oldest = max(oldest, now.Sub(dueAt))
+	}
+	return oldest, nil
+}
+
+// Update modifies all fields of an existing job.
+func (r *MemoryJobRepository) Update(ctx context.Context, job *model.Job) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.jobs[job.ID]; !exists {
+		return fmt.Errorf("job not found: %s", job.ID)
+	}
+	r.jobs[job.ID] = copyJob(job)
+	return nil
+}
+
+// UpdateProgress writes only the mutable lifecycle fields of a job.
+func (r *MemoryJobRepository) UpdateProgress(ctx context.Context, job *model.Job) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored, exists := r.jobs[job.ID]
+	if !exists {
+		return fmt.Errorf("job not found: %s", job.ID)
+	}
+
+	update := copyJob(job)
+	stored.State = update.State
+	stored.Attempt = update.Attempt
+	stored.LastError = update.LastError
+	stored.ScheduledAt = update.ScheduledAt
+	stored.StartedAt = update.StartedAt
+	stored.CompletedAt = update.CompletedAt
+	stored.LastTransitionReason = update.LastTransitionReason
+	stored.Priority = update.Priority
+	stored.NextRetryAt = update.NextRetryAt
+	return nil
+}
+
+// UpdateMaxAttempts sets a live job's max attempts.
+func (r *MemoryJobRepository) UpdateMaxAttempts(ctx context.Context, id string, maxAttempts int) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored, exists := r.jobs[id]
+	if !exists || stored.IsTerminal() || stored.Attempt > maxAttempts {
+		return false, nil
+	}
+	stored.MaxAttempts = maxAttempts
+	return true, nil
+}
+
+// Delete removes a job.
+func (r *MemoryJobRepository) Delete(ctx context.Context, id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.jobs[id]; !exists {
+		return fmt.Errorf("job not found: %s", id)
+	}
+	delete(r.jobs, id)
+	delete(r.errors, id)
+	return nil
+}
+
+// SetAllowDestructive enables TruncateAll.
+func (r *MemoryJobRepository) SetAllowDestructive(allow bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.allowDestructive = allow
+}
+
+// TruncateAll deletes every job and its error history.
+func (r *MemoryJobRepository) TruncateAll(ctx context.Context) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if !r.allowDestructive {
+		return ErrDestructiveDisabled
+	}
+	r.jobs = make(map[string]*model.Job)
+	r.errors = make(map[string][]model.AttemptError)
+	return nil
+}
+
+// RecordAttemptError appends an entry to a job's error history.
+func (r *MemoryJobRepository) RecordAttemptError(ctx context.Context, attemptErr *model.AttemptError) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.jobs[attemptErr.JobID]; !exists {
+		return fmt.Errorf("failed to record attempt error: job not found: %s", attemptErr.JobID)
+	}
+	r.errors[attemptErr.JobID] = append(r.errors[attemptErr.JobID], *attemptErr)
+	return nil
+}
+
+// ListAttemptErrors returns a job's error history, oldest first.
+func (r *MemoryJobRepository) ListAttemptErrors(ctx context.Context, jobID string) ([]*model.AttemptError, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	attemptErrs := []*model.AttemptError{}
+	for _, attemptErr := range r.errors[jobID] {
+		e := attemptErr
+		attemptErrs = append(attemptErrs, &e)
+	}
+	return attemptErrs, nil
+}
+
+// FindStaleScheduled returns SCHEDULED jobs whose scheduled_at is before cutoff.
+func (r *MemoryJobRepository) FindStaleScheduled(ctx context.Context, cutoff time.Time) ([]*model.Job, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	jobs := []*model.Job{}
+	for _, job := range r.sortedLocked() {
+		if job.State != state.SCHEDULED || job.ScheduledAt == nil {
+			continue
+		}
+		if job.ScheduledAt.Before(cutoff) {
+			jobs = append(jobs, copyJob(job))
+		}
+	}
+	sort.SliceStable(jobs, func(i, j int) bool {
+		if !jobs[i].ScheduledAt.Equal(*jobs[j].ScheduledAt) {
+			return jobs[i].ScheduledAt.Before(*jobs[j].ScheduledAt)
+		}
+		return jobs[i].ID < jobs[j].ID
+	})
+	return jobs, nil
+}
+
+// DistinctTypes returns the sorted set of job types that currently exist.
+func (r *MemoryJobRepository) DistinctTypes(ctx context.Context) ([]string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	seen := make(map[string]bool)
+	types := []string{}
+	for _, job := range r.jobs {
+		if !seen[job.Type] {
+			seen[job.Type] = true
+			types = append(types, job.Type)
+		}
+	}
+	sort.Strings(types)
+	return types, nil
+}
+
+// TopErrors returns the most common last errors among FAILED jobs.
+func (r *MemoryJobRepository) TopErrors(ctx context.Context, limit int) ([]model.ErrorCount, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return topErrors(r.jobs, limit), nil
+}
+
+// topErrors groups FAILED jobs by last error, ordered like the
+// postgres query: most common first, ties by message.
+func topErrors(jobs map[string]*model.Job, limit int) []model.ErrorCount {
+	byError := make(map[string]int)
+	for _, job := range jobs {
+		if job.State == state.FAILED && job.LastError != nil {
+			byError[*job.LastError]++
+		}
+	}
+
+	counts := []model.ErrorCount{}
+	for msg, n := range byError {
+		counts = append(counts, model.ErrorCount{Error: msg, Count: n})
+	}
+	sort.Slice(counts, func(i, j int) bool {
+		if counts[i].Count != counts[j].Count {
+			return counts[i].Count > counts[j].Count
+		}
+		return counts[i].Error < counts[j].Error
+	})
+
+	if len(counts) > limit {
+		counts = counts[:limit]
+	}
+	return counts
+}
+
+// SetPriorityAging makes claim order use a job's priority plus one for
+// every full step it has waited since creation, so a low-priority job
+// can't be starved by higher-priority work forever. Zero (the default)
+// claims by priority alone.
+func (r *MemoryJobRepository) SetPriorityAging(step time.Duration) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.priorityAging = step
+}
+
+// SetClaimPolicy sets the order ClaimPendingJobs and PeekClaimable
+// hand out jobs in. The default is ClaimFIFO.
+func (r *MemoryJobRepository) SetClaimPolicy(policy ClaimPolicy) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.claimPolicy = policy
+}
+
+// ClaimPendingJobs claims pending and retrying jobs by transitioning them to SCHEDULED.
+// Matches the ordering of PostgresJobRepository.ClaimPendingJobs.
+// Jobs of the skipTypes are left unclaimed.
+func (r *MemoryJobRepository) ClaimPendingJobs(ctx context.Context, limit int, now time.Time, skipTypes ...string) ([]*model.Job, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	jobs := []*model.Job{}
+	for _, job := range r.claimableLocked(limit, now, skipTypes) {
+		job.State = state.SCHEDULED
+		scheduledAt := now
+		job.ScheduledAt = &scheduledAt
+		jobs = append(jobs, copyJob(job))
+	}
+	return jobs, nil
+}
+
+// PeekClaimable returns the jobs ClaimPendingJobs would claim next, without claiming them.
+func (r *MemoryJobRepository) PeekClaimable(ctx context.Context, limit int, now time.Time, skipTypes ...string) ([]*model.Job, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	jobs := []*model.Job{}
+	for _, job := range r.claimableLocked(limit, now, skipTypes) {
+		jobs = append(jobs, copyJob(job))
+	}
+	return jobs, nil
+}
+
+// claimableLocked returns up to limit PENDING/RETRYING jobs in claim order:
+// highest priority (after aging) first, then due retries under ClaimRetriesFirst,
+// oldest first within a priority, ID last. Jobs whose
+// run_at (pending) or backoff (retrying) hasn't passed by now, or whose
+// type is in skipTypes, are skipped.
+// Caller must hold r.mu.
+func (r *MemoryJobRepository) claimableLocked(limit int, now time.Time, skipTypes []string) []*model.Job {
+	candidates := r.sortedLocked()
+	sort.SliceStable(candidates, func(i, j int) bool {
+		pi := agedPriority(candidates[i].Priority, candidates[i].CreatedAt, now, r.priorityAging)
+		pj := agedPriority(candidates[j].Priority, candidates[j].CreatedAt, now, r.priorityAging)
+		if pi != pj {
+			return pi > pj
+		}
+		if r.claimPolicy == ClaimRetriesFirst {
+			return candidates[i].State == state.RETRYING && candidates[j].State != state.RETRYING
+		}
+		return false
+	})
+
+	var jobs []*model.Job
+	for _, job := range candidates {
+		if job.State != state.PENDING && job.State != state.RETRYING {
+			continue
+		}
+		if job.State == state.PENDING && job.RunAt != nil && job.RunAt.After(now) {
+			continue
+		}
+		if job.State == state.RETRYING && job.NextRetryAt != nil && job.NextRetryAt.After(now) {
+			continue
+		}
+		if slices.Contains(skipTypes, job.Type) {
+			continue
+		}
+		jobs = append(jobs, job)
+		if len(jobs) >= limit {
+			break
+		}
+	}
+	return jobs
+}
+
+// WithTx runs fn with rollback on error: if fn fails, all jobs and
+// attempt errors are restored to their state before fn ran.
+func (r *MemoryJobRepository) WithTx(ctx context.Context, fn func(tx JobRepository) error) error {
+	r.txMu.Lock()
+	defer r.txMu.Unlock()
+
+	return r.runTx(fn)
+}
+
+// memoryTx is the repository handed to WithTx callbacks.
+// Nested WithTx calls snapshot again instead of re-taking txMu.
+type memoryTx struct {
+	*MemoryJobRepository
+}
+
+func (t memoryTx) WithTx(ctx context.Context, fn func(tx JobRepository) error) error {
+	return t.runTx(fn)
+}
+
+// runTx snapshots all state, runs fn, and restores the snapshot if fn fails.
+func (r *MemoryJobRepository) runTx(fn func(tx JobRepository) error) error {
+	r.mu.Lock()
+	jobs := make(map[string]*model.Job, len(r.jobs))
+	for id, job := range r.jobs {
+		jobs[id] = copyJob(job)
+	}
+	errs := make(map[string][]model.AttemptError, len(r.errors))
+	for id, attemptErrs := range r.errors {
+		errs[id] = append([]model.AttemptError(nil), attemptErrs...)
+	}
+	r.mu.Unlock()
+
+	if err := fn(memoryTx{r}); err != nil {
+		r.mu.Lock()
+		r.jobs, r.errors = jobs, errs
+		r.mu.Unlock()
+		return err
+	}
+	return nil
+}
+
+// sortedLocked returns stored jobs ordered by creation time, then ID,
+// so jobs created in the same instant still come back in a fixed order.
+// Caller must hold r.mu.
+func (r *MemoryJobRepository) sortedLocked() []*model.Job {
+	jobs := make([]*model.Job, 0, len(r.jobs))
+	for _, job := range r.jobs {
+		jobs = append(jobs, job)
+	}
+	sort.Slice(jobs, func(i, j int) bool {
+		if !jobs[i].CreatedAt.Equal(jobs[j].CreatedAt) {
+			return jobs[i].CreatedAt.Before(jobs[j].CreatedAt)
+		}
+		return jobs[i].ID < jobs[j].ID
+	})
+	return jobs
+}
+
+// copyJob returns a copy of job that shares no pointers with the original.
+func copyJob(job *model.Job) *model.Job {
+	c := *job
+	if job.Payload != nil {
+		c.Payload = append([]byte(nil), job.Payload...)
+	}
+	c.LastError = copyString(job.LastError)
+	c.ScheduledAt = copyTime(job.ScheduledAt)
+	c.StartedAt = copyTime(job.StartedAt)
+	c.CompletedAt = copyTime(job.CompletedAt)
+	c.LastTransitionReason = copyString(job.LastTransitionReason)
+	c.NextRetryAt = copyTime(job.NextRetryAt)
+	c.RunAt = copyTime(job.RunAt)
+	c.Schedule = copyString(job.Schedule)
+	c.ParentID = copyString(job.ParentID)
+	c.PayloadRef = copyString(job.PayloadRef)
+	c.IdempotencyKey = copyString(job.IdempotencyKey)
+	if job.Result != nil {
+		c.Result = append([]byte(nil), job.Result...)
+	}
+	return &c
+}
+
+func copyString(s *string) *string {
+	if s == nil {
+		return nil
+	}
+	v := *s
+	return &v
+}
+
+func copyTime(t *time.Time) *time.Time {
+	if t == nil {
+		return nil
+	}
+	v := *t
+	return &v
+}
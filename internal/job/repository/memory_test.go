@@ -0,0 +1,692 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/dipak0000812/orchestrix/internal/job/model"
+	"github.com/dipak0000812/orchestrix/internal/job/state"
+)
+
+func TestMemoryWithTx_RollsBackOnError(t *testing.T) {
+	repo := NewMemoryJobRepository()
+	ctx := context.Background()
+
+	job := &model.Job{
+		ID:          "test_job_tx",
+		Type:        "test",
+		Payload:     []byte(`{}`),
+		State:       state.RUNNING,
+		Attempt:     1,
+		MaxAttempts: 3,
+		CreatedAt:   time.Now(),
+	}
+	repo.Create(ctx, job)
+
+	errBoom := errors.New("boom")
+	err := repo.WithTx(ctx, func(tx JobRepository) error {
+		tx.RecordAttemptError(ctx, &model.AttemptError{
+			JobID:      job.ID,
+			Attempt:    1,
+			Error:      "timeout",
+			OccurredAt: time.Now(),
+		})
+
+		updated := *job
+		updated.State = state.RETRYING
+		updated.Attempt = 2
+		if err := tx.Update(ctx, &updated); err != nil {
+			t.Fatalf("Update failed: %v", err)
+		}
+
+		// Fail after both writes, before the transaction completes
+		return errBoom
+	})
+	if !errors.Is(err, errBoom) {
+		t.Fatalf("WithTx error = %v, want %v", err, errBoom)
+	}
+
+	retrieved, _ := repo.GetByID(ctx, job.ID)
+	if retrieved.State != state.RUNNING || retrieved.Attempt != 1 {
+		t.Errorf("Job = %s attempt %d, want RUNNING attempt 1", retrieved.State, retrieved.Attempt)
+	}
+
+	errs, _ := repo.ListAttemptErrors(ctx, job.ID)
+	if len(errs) != 0 {
+		t.Errorf("Expected attempt error to be rolled back, got %d", len(errs))
+	}
+}
+
+func TestMemoryWithTx_CommitsOnSuccess(t *testing.T) {
+	repo := NewMemoryJobRepository()
+	ctx := context.Background()
+
+	err := repo.WithTx(ctx, func(tx JobRepository) error {
+		return tx.Create(ctx, &model.Job{
+			ID:          "test_job_tx_commit",
+			Type:        "test",
+			State:       state.PENDING,
+			Attempt:     1,
+			MaxAttempts: 3,
+			CreatedAt:   time.Now(),
+		})
+	})
+	if err != nil {
+		t.Fatalf("WithTx failed: %v", err)
+	}
+
+	if retrieved, _ := repo.GetByID(ctx, "test_job_tx_commit"); retrieved == nil {
+		t.Error("Expected job created in transaction to exist")
+	}
+}
+
+func TestMemoryCreate_Duplicate(t *testing.T) {
+	repo := NewMemoryJobRepository()
+	ctx := context.Background()
+
+	job := &model.Job{
+		ID:          "test_job_dup",
+		Type:        "test",
+		State:       state.PENDING,
+		Attempt:     1,
+		MaxAttempts: 3,
+		CreatedAt:   time.Now(),
+	}
+	if err := repo.Create(ctx, job); err != nil {
+		t.Fatalf("First Create failed: %v", err)
+	}
+
+	if err := repo.Create(ctx, job); !errors.Is(err, ErrDuplicateJob) {
+		t.Errorf("Second Create error = %v, want ErrDuplicateJob", err)
+	}
+}
+
+func TestMemoryExists(t *testing.T) {
+	repo := NewMemoryJobRepository()
+	ctx := context.Background()
+
+	repo.Create(ctx, &model.Job{
+		ID:          "test_job_exists",
+		Type:        "test",
+		Payload:     []byte(`{}`),
+		State:       state.PENDING,
+		Attempt:     1,
+		MaxAttempts: 3,
+		CreatedAt:   time.Now(),
+	})
+
+	exists, err := repo.Exists(ctx, "test_job_exists")
+	if err != nil {
+		t.Fatalf("Exists failed: %v", err)
+	}
+	if !exists {
+		t.Error("Exists = false for a stored job, want true")
+	}
+
+	exists, err = repo.Exists(ctx, "nonexistent")
+	if err != nil {
+		t.Fatalf("Exists failed: %v", err)
+	}
+	if exists {
+		t.Error("Exists = true for a missing job, want false")
+	}
+}
+
+func TestMemoryUpdateProgress_PreservesImmutableFields(t *testing.T) {
+	repo := NewMemoryJobRepository()
+	ctx := context.Background()
+
+	createdAt := time.Now().Add(-time.Hour).Truncate(time.Millisecond)
+	job := &model.Job{
+		ID:          "test_job_progress",
+		Type:        "test",
+		Payload:     []byte(`{"a":1}`),
+		State:       state.SCHEDULED,
+		Attempt:     1,
+		MaxAttempts: 3,
+		CreatedAt:   createdAt,
+	}
+	repo.Create(ctx, job)
+
+	// Stale or tampered immutable fields on the caller's copy are ignored
+	update := *job
+	update.State = state.RUNNING
+	startedAt := time.Now()
+	update.StartedAt = &startedAt
+	update.CreatedAt = time.Now()
+	update.Type = "other"
+	if err := repo.UpdateProgress(ctx, &update); err != nil {
+		t.Fatalf("UpdateProgress failed: %v", err)
+	}
+
+	retrieved, _ := repo.GetByID(ctx, job.ID)
+	if retrieved.State != state.RUNNING || retrieved.StartedAt == nil {
+		t.Errorf("Progress not written: state %s, started_at %v", retrieved.State, retrieved.StartedAt)
+	}
+	if !retrieved.CreatedAt.Equal(createdAt) {
+		t.Errorf("CreatedAt = %v, want unchanged %v", retrieved.CreatedAt, createdAt)
+	}
+	if retrieved.Type != "test" {
+		t.Errorf("Type = %q, want unchanged \"test\"", retrieved.Type)
+	}
+}
+
+func TestMemoryPeekClaimable_DoesNotClaim(t *testing.T) {
+	repo := NewMemoryJobRepository()
+	ctx := context.Background()
+
+	now := time.Now()
+	for i, priority := range []int{0, 5, 0} {
+		repo.Create(ctx, &model.Job{
+			ID:          fmt.Sprintf("test_job_peek_%d", i),
+			Type:        "test",
+			State:       state.PENDING,
+			Attempt:     1,
+			MaxAttempts: 3,
+			CreatedAt:   now.Add(time.Duration(i) * time.Second),
+			Priority:    priority,
+		})
+	}
+
+	peeked, err := repo.PeekClaimable(ctx, 10, now)
+	if err != nil {
+		t.Fatalf("PeekClaimable failed: %v", err)
+	}
+
+	// Claim order: priority first, then age
+	want := []string{"test_job_peek_1", "test_job_peek_0", "test_job_peek_2"}
+	if len(peeked) != len(want) {
+		t.Fatalf("Peeked %d jobs, want %d", len(peeked), len(want))
+	}
+	for i, id := range want {
+		if peeked[i].ID != id {
+			t.Errorf("peeked[%d] = %s, want %s", i, peeked[i].ID, id)
+		}
+	}
+
+	// Nothing was claimed
+	pending, _ := repo.CountByState(ctx, state.PENDING)
+	if pending != 3 {
+		t.Errorf("Pending jobs after peek = %d, want 3", pending)
+	}
+
+	// Peek matches what a claim actually takes
+	claimed, _ := repo.ClaimPendingJobs(ctx, 1, now)
+	if len(claimed) != 1 || claimed[0].ID != want[0] {
+		t.Errorf("Claimed %v, want %s first", claimed, want[0])
+	}
+}
+
+func TestMemoryClaimPendingJobs_WaitsForRunAt(t *testing.T) {
+	repo := NewMemoryJobRepository()
+	ctx := context.Background()
+
+	now := time.Now()
+	runAt := now.Add(time.Hour)
+	repo.Create(ctx, &model.Job{
+		ID:          "test_job_run_at",
+		Type:        "test",
+		State:       state.PENDING,
+		Attempt:     1,
+		MaxAttempts: 3,
+		CreatedAt:   now,
+		RunAt:       &runAt,
+	})
+
+	if claimed, _ := repo.ClaimPendingJobs(ctx, 10, now); len(claimed) != 0 {
+		t.Fatalf("Claimed %d jobs before run_at, want 0", len(claimed))
+	}
+
+	if claimed, _ := repo.ClaimPendingJobs(ctx, 10, runAt); len(claimed) != 1 {
+		t.Errorf("Claimed %d jobs at run_at, want 1", len(claimed))
+	}
+}
+
+func TestMemoryClaimPendingJobs_SkipsTypes(t *testing.T) {
+	repo := NewMemoryJobRepository()
+	ctx := context.Background()
+
+	now := time.Now()
+	for _, jobType := range []string{"process_video", "send_email"} {
+		repo.Create(ctx, &model.Job{
+			ID:          "test_job_" + jobType,
+			Type:        jobType,
+			Payload:     []byte(`{}`),
+			State:       state.PENDING,
+			Attempt:     1,
+			MaxAttempts: 3,
+			CreatedAt:   now,
+		})
+	}
+
+	peeked, _ := repo.PeekClaimable(ctx, 10, now, "process_video")
+	if len(peeked) != 1 || peeked[0].Type != "send_email" {
+		t.Errorf("Peeked %v, want only the send_email job", peeked)
+	}
+
+	claimed, err := repo.ClaimPendingJobs(ctx, 10, now, "process_video")
+	if err != nil {
+		t.Fatalf("ClaimPendingJobs failed: %v", err)
+	}
+	if len(claimed) != 1 || claimed[0].Type != "send_email" {
+		t.Fatalf("Claimed %v, want only the send_email job", claimed)
+	}
+
+	// Without skipped types the other job is claimable again
+	if claimed, _ := repo.ClaimPendingJobs(ctx, 10, now); len(claimed) != 1 || claimed[0].Type != "process_video" {
+		t.Errorf("Claimed %v, want the process_video job", claimed)
+	}
+}
+
+func TestMemoryUpdateStateBatch(t *testing.T) {
+	repo := NewMemoryJobRepository()
+	ctx := context.Background()
+
+	// Three RUNNING jobs to complete, plus one that already moved on
+	for i, jobState := range []state.State{state.RUNNING, state.RUNNING, state.RUNNING, state.CANCELLED} {
+		repo.Create(ctx, &model.Job{
+			ID:          fmt.Sprintf("test_job_batch_%d", i),
+			Type:        "test",
+			Payload:     []byte(`{}`),
+			State:       jobState,
+			Attempt:     1,
+			MaxAttempts: 3,
+			CreatedAt:   time.Now(),
+		})
+	}
+
+	ids := []string{"test_job_batch_0", "test_job_batch_1", "test_job_batch_2", "test_job_batch_3", "missing"}
+	updated, err := repo.UpdateStateBatch(ctx, ids, state.RUNNING, state.SUCCEEDED, "")
+	if err != nil {
+		t.Fatalf("UpdateStateBatch failed: %v", err)
+	}
+	if len(updated) != 3 {
+		t.Fatalf("Updated %v, want the 3 RUNNING jobs", updated)
+	}
+
+	for i := 0; i < 3; i++ {
+		job, _ := repo.GetByID(ctx, fmt.Sprintf("test_job_batch_%d", i))
+		if job.State != state.SUCCEEDED {
+			t.Errorf("Job %s state = %s, want SUCCEEDED", job.ID, job.State)
+		}
+	}
+
+	// Jobs no longer in the expected prior state are left alone
+	skipped, _ := repo.GetByID(ctx, "test_job_batch_3")
+	if skipped.State != state.CANCELLED {
+		t.Errorf("Job %s state = %s, want CANCELLED", skipped.ID, skipped.State)
+	}
+}
+
+func TestMemoryClaimPendingJobs_StableOrderOnTies(t *testing.T) {
+	// Map iteration is random, so repeat to catch order leaking through
+	for run := 0; run < 20; run++ {
+		repo := NewMemoryJobRepository()
+		ctx := context.Background()
+
+		// Created in a tight loop sharing one timestamp, inserted in reverse ID order
+		now := time.Now()
+		for i := 9; i >= 0; i-- {
+			repo.Create(ctx, &model.Job{
+				ID:          fmt.Sprintf("test_job_tie_%02d", i),
+				Type:        "test",
+				State:       state.PENDING,
+				Attempt:     1,
+				MaxAttempts: 3,
+				CreatedAt:   now,
+			})
+		}
+
+		claimed, err := repo.ClaimPendingJobs(ctx, 10, now)
+		if err != nil {
+			t.Fatalf("ClaimPendingJobs failed: %v", err)
+		}
+		if len(claimed) != 10 {
+			t.Fatalf("Claimed %d jobs, want 10", len(claimed))
+		}
+		for i, job := range claimed {
+			if want := fmt.Sprintf("test_job_tie_%02d", i); job.ID != want {
+				t.Fatalf("Run %d: claimed[%d] = %s, want %s", run, i, job.ID, want)
+			}
+		}
+	}
+}
+
+func TestMemoryClaimPendingJobs_RetriesFirst(t *testing.T) {
+	repo := NewMemoryJobRepository()
+	ctx := context.Background()
+
+	now := time.Now()
+	backdated := now.Add(-time.Minute)
+
+	// An older new job, then a due retry created after it
+	repo.Create(ctx, &model.Job{
+		ID:          "test_job_policy_new",
+		Type:        "test",
+		Payload:     []byte(`{}`),
+		State:       state.PENDING,
+		Attempt:     1,
+		MaxAttempts: 3,
+		CreatedAt:   now.Add(-time.Hour),
+	})
+	repo.Create(ctx, &model.Job{
+		ID:          "test_job_policy_retry",
+		Type:        "test",
+		Payload:     []byte(`{}`),
+		State:       state.RETRYING,
+		Attempt:     2,
+		MaxAttempts: 3,
+		CreatedAt:   now.Add(-time.Minute),
+		NextRetryAt: &backdated,
+	})
+
+	// FIFO by default: the older new job goes first
+	peeked, _ := repo.PeekClaimable(ctx, 1, now)
+	if len(peeked) != 1 || peeked[0].ID != "test_job_policy_new" {
+		t.Fatalf("FIFO peeked %v, want test_job_policy_new", peeked)
+	}
+
+	repo.SetClaimPolicy(ClaimRetriesFirst)
+	claimed, err := repo.ClaimPendingJobs(ctx, 1, now)
+	if err != nil {
+		t.Fatalf("ClaimPendingJobs failed: %v", err)
+	}
+	if len(claimed) != 1 || claimed[0].ID != "test_job_policy_retry" {
+		t.Errorf("Claimed %v, want the due retry first", claimed)
+	}
+}
+
+func TestMemoryClaimPendingJobs_PriorityAging(t *testing.T) {
+	repo := NewMemoryJobRepository()
+	repo.SetPriorityAging(10 * time.Minute)
+	ctx := context.Background()
+
+	created := time.Now().Add(-2 * time.Hour)
+	newJob := func(id string, priority int, createdAt time.Time) {
+		repo.Create(ctx, &model.Job{
+			ID:          id,
+			Type:        "test",
+			Payload:     []byte(`{}`),
+			State:       state.PENDING,
+			Attempt:     1,
+			MaxAttempts: 3,
+			Priority:    priority,
+			CreatedAt:   createdAt,
+		})
+	}
+	claimNext := func(now time.Time) string {
+		claimed, err := repo.ClaimPendingJobs(ctx, 1, now)
+		if err != nil {
+			t.Fatalf("ClaimPendingJobs failed: %v", err)
+		}
+		if len(claimed) != 1 {
+			t.Fatalf("Claimed %d jobs, want 1", len(claimed))
+		}
+		return claimed[0].ID
+	}
+
+	newJob("test_job_aging_old", 1, created)
+
+	// After 20 minutes the old job ranks 1+2, below a fresh priority 5 job
+	newJob("test_job_aging_mid_1", 5, created.Add(19*time.Minute))
+	if got := claimNext(created.Add(20 * time.Minute)); got != "test_job_aging_mid_1" {
+		t.Errorf("Claimed %s after 20m, want test_job_aging_mid_1", got)
+	}
+
+	// After an hour it ranks 1+6 and overtakes the next fresh priority 5 job
+	newJob("test_job_aging_mid_2", 5, created.Add(59*time.Minute))
+	if got := claimNext(created.Add(time.Hour)); got != "test_job_aging_old" {
+		t.Errorf("Claimed %s after 1h, want test_job_aging_old", got)
+	}
+}
+
+func TestMemoryOldestPendingAge(t *testing.T) {
+	repo := NewMemoryJobRepository()
+	ctx := context.Background()
+	now := time.Now()
+
+	age, err := repo.OldestPendingAge(ctx, now)
+	if err != nil {
+		t.Fatalf("OldestPendingAge failed: %v", err)
+	}
+	if age != 0 {
+		t.Errorf("Age with no jobs = %v, want 0", age)
+	}
+
+	future := now.Add(time.Hour)
+	for id, job := range map[string]struct {
+		state     state.State
+		createdAt time.Time
+		runAt     *time.Time
+	}{
+		"test_job_oldest_pending": {state.PENDING, now.Add(-time.Hour), nil},
+		"test_job_newer_pending":  {state.PENDING, now.Add(-time.Minute), nil},
+		"test_job_older_running":  {state.RUNNING, now.Add(-2 * time.Hour), nil},
+		"test_job_not_yet_due":    {state.PENDING, now.Add(-3 * time.Hour), &future},
+	} {
+		repo.Create(ctx, &model.Job{
+			ID:          id,
+			Type:        "test",
+			Payload:     []byte(`{}`),
+			State:       job.state,
+			Attempt:     1,
+			MaxAttempts: 3,
+			CreatedAt:   job.createdAt,
+			RunAt:       job.runAt,
+		})
+	}
+
+	age, err = repo.OldestPendingAge(ctx, now)
+	if err != nil {
+		t.Fatalf("OldestPendingAge failed: %v", err)
+	}
+	if age < time.Hour-time.Second || age > time.Hour+time.Second {
+		t.Errorf("Age = %v, want about 1h (running and not-yet-due jobs ignored)", age)
+	}
+}
+
+func TestMemoryListByStateOrdered(t *testing.T) {
+	repo := NewMemoryJobRepository()
+	ctx := context.Background()
+	now := time.Now()
+
+	for i := 1; i <= 3; i++ {
+		repo.Create(ctx, &model.Job{
+			ID:          fmt.Sprintf("test_job_%d", i),
+			Type:        "test",
+			Payload:     []byte(`{}`),
+			State:       state.PENDING,
+			Attempt:     1,
+			MaxAttempts: 3,
+			CreatedAt:   now.Add(time.Duration(i) * time.Second),
+		})
+	}
+
+	tests := []struct {
+		order SortOrder
+		want  []string
+	}{
+		{SortAsc, []string{"test_job_1", "test_job_2"}},
+		{SortDesc, []string{"test_job_3", "test_job_2"}},
+	}
+	for _, tt := range tests {
+		jobs, err := repo.ListByStateOrdered(ctx, state.PENDING, 2, tt.order)
+		if err != nil {
+			t.Fatalf("ListByStateOrdered(%s) failed: %v", tt.order, err)
+		}
+		var got []string
+		for _, job := range jobs {
+			got = append(got, job.ID)
+		}
+		if !slices.Equal(got, tt.want) {
+			t.Errorf("ListByStateOrdered(%s) = %v, want %v", tt.order, got, tt.want)
+		}
+	}
+}
+
+func TestMemoryCountByTypeAndState(t *testing.T) {
+	repo := NewMemoryJobRepository()
+	ctx := context.Background()
+
+	jobs := []struct {
+		jobType  string
+		jobState state.State
+	}{
+		{"email", state.PENDING},
+		{"email", state.PENDING},
+		{"email", state.RUNNING},
+		{"email", state.FAILED},
+		{"resize", state.PENDING},
+		{"resize", state.FAILED},
+		{"resize", state.FAILED},
+	}
+	for i, job := range jobs {
+		repo.Create(ctx, &model.Job{
+			ID:          fmt.Sprintf("test_job_matrix_%d", i),
+			Type:        job.jobType,
+			Payload:     []byte(`{}`),
+			State:       job.jobState,
+			Attempt:     1,
+			MaxAttempts: 3,
+			CreatedAt:   time.Now(),
+		})
+	}
+
+	counts, err := repo.CountByTypeAndState(ctx)
+	if err != nil {
+		t.Fatalf("CountByTypeAndState failed: %v", err)
+	}
+	want := map[string]map[state.State]int{
+		"email":  {state.PENDING: 2, state.RUNNING: 1, state.FAILED: 1},
+		"resize": {state.PENDING: 1, state.FAILED: 2},
+	}
+	if !reflect.DeepEqual(counts, want) {
+		t.Errorf("CountByTypeAndState = %v, want %v", counts, want)
+	}
+}
+
+func TestMemoryTopErrors(t *testing.T) {
+	repo := NewMemoryJobRepository()
+	ctx := context.Background()
+
+	errs := []string{"timeout", "refused", "timeout", "timeout", "refused", "disk full"}
+	for i, msg := range errs {
+		repo.Create(ctx, &model.Job{
+			ID:          fmt.Sprintf("test_job_top_error_%d", i),
+			Type:        "test",
+			Payload:     []byte(`{}`),
+			State:       state.FAILED,
+			Attempt:     3,
+			MaxAttempts: 3,
+			LastError:   &msg,
+			CreatedAt:   time.Now(),
+		})
+	}
+	// Only FAILED jobs count, even if another state carries an error
+	retrying := "timeout"
+	repo.Create(ctx, &model.Job{
+		ID:          "test_job_top_error_retrying",
+		Type:        "test",
+		Payload:     []byte(`{}`),
+		State:       state.RETRYING,
+		Attempt:     2,
+		MaxAttempts: 3,
+		LastError:   &retrying,
+		CreatedAt:   time.Now(),
+	})
+
+	counts, err := repo.TopErrors(ctx, 2)
+	if err != nil {
+		t.Fatalf("TopErrors failed: %v", err)
+	}
+	want := []model.ErrorCount{{Error: "timeout", Count: 3}, {Error: "refused", Count: 2}}
+	if len(counts) != len(want) || counts[0] != want[0] || counts[1] != want[1] {
+		t.Errorf("TopErrors = %+v, want %+v", counts, want)
+	}
+}
+
+func TestMemoryTruncateAll(t *testing.T) {
+	repo := NewMemoryJobRepository()
+	ctx := context.Background()
+
+	for i := 0; i < 3; i++ {
+		repo.Create(ctx, &model.Job{
+			ID:          fmt.Sprintf("test_job_truncate_%d", i),
+			Type:        "test",
+			Payload:     []byte(`{}`),
+			State:       state.PENDING,
+			Attempt:     1,
+			MaxAttempts: 3,
+			CreatedAt:   time.Now(),
+		})
+	}
+	repo.RecordAttemptError(ctx, &model.AttemptError{JobID: "test_job_truncate_0", Attempt: 1, Error: "boom"})
+
+	// Refused until explicitly allowed
+	if err := repo.TruncateAll(ctx); !errors.Is(err, ErrDestructiveDisabled) {
+		t.Fatalf("TruncateAll without permission = %v, want ErrDestructiveDisabled", err)
+	}
+	if n, _ := repo.CountByState(ctx, state.PENDING); n != 3 {
+		t.Fatalf("Refused TruncateAll left %d jobs, want 3", n)
+	}
+
+	repo.SetAllowDestructive(true)
+	if err := repo.TruncateAll(ctx); err != nil {
+		t.Fatalf("TruncateAll failed: %v", err)
+	}
+	if n, _ := repo.CountByState(ctx, state.PENDING); n != 0 {
+		t.Errorf("%d jobs left after TruncateAll, want 0", n)
+	}
+	if errs, _ := repo.ListAttemptErrors(ctx, "test_job_truncate_0"); len(errs) != 0 {
+		t.Errorf("%d attempt errors left after TruncateAll, want 0", len(errs))
+	}
+}
+
+func TestMemoryGetByIdempotencyKey(t *testing.T) {
+	repo := NewMemoryJobRepository()
+	ctx := context.Background()
+
+	if job, err := repo.GetByIdempotencyKey(ctx, "order-42", "email"); err != nil || job != nil {
+		t.Fatalf("Unused key = %v, %v; want nil, nil", job, err)
+	}
+
+	key := "order-42"
+	repo.Create(ctx, &model.Job{
+		ID:             "test_job_idempotent",
+		Type:           "email",
+		Payload:        []byte(`{}`),
+		State:          state.PENDING,
+		Attempt:        1,
+		MaxAttempts:    3,
+		CreatedAt:      time.Now(),
+		IdempotencyKey: &key,
+	})
+
+	job, err := repo.GetByIdempotencyKey(ctx, "order-42", "email")
+	if err != nil || job == nil || job.ID != "test_job_idempotent" {
+		t.Fatalf("Used key = %v, %v; want test_job_idempotent", job, err)
+	}
+	if job, _ := repo.GetByIdempotencyKey(ctx, "order-42", "sms"); job != nil {
+		t.Errorf("Key matched job %s of another type", job.ID)
+	}
+
+	// A second job can't take the same key and type
+	err = repo.Create(ctx, &model.Job{
+		ID:             "test_job_idempotent_2",
+		Type:           "email",
+		Payload:        []byte(`{}`),
+		State:          state.PENDING,
+		Attempt:        1,
+		MaxAttempts:    3,
+		CreatedAt:      time.Now(),
+		IdempotencyKey: &key,
+	})
+	if !errors.Is(err, ErrDuplicateJob) {
+		t.Errorf("Create with a used key = %v, want ErrDuplicateJob", err)
+	}
+}
@@ -2,37 +2,264 @@ package repository
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"log/slog"
 	"time"
 
 	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/jackc/pgx/v5/pgxpool"
+	"golang.org/x/sync/semaphore"
 
 	"github.com/dipak0000812/orchestrix/internal/job/model"
 	"github.com/dipak0000812/orchestrix/internal/job/state"
 )
 
+// dbtx is the query interface shared by *pgxpool.Pool and pgx.Tx,
+// so the same repository code runs inside or outside a transaction.
+type dbtx interface {
+	Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error)
+	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+	Begin(ctx context.Context) (pgx.Tx, error)
+}
+
 // PostgresJobRepository implements JobRepository using PostgreSQL.
 type PostgresJobRepository struct {
-	pool *pgxpool.Pool
+	pool dbtx
+
+	// base is the pool behind pool; nil inside a transaction.
+	base *pgxpool.Pool
+
+	claimPolicy ClaimPolicy
+
+	// priorityAging raises a job's claim priority by one per step waited;
+	// zero disables it.
+	priorityAging time.Duration
+
+	// allowDestructive enables TruncateAll; off unless a test turns it on.
+	allowDestructive bool
+
+	// claimSlots caps concurrent ClaimPendingJobs transactions; nil means
+	// no cap. Transaction repositories leave it nil: they already hold
+	// their connection.
+	claimSlots *semaphore.Weighted
 }
 
 // NewPostgresJobRepository creates a new PostgreSQL-backed job repository.
 func NewPostgresJobRepository(pool *pgxpool.Pool) *PostgresJobRepository {
 	return &PostgresJobRepository{
 		pool: pool,
+		base: pool,
+	}
+}
+
+// SetPriorityAging makes claim order use a job's priority plus one for
+// every full step it has waited since creation, so a low-priority job
+// can't be starved by higher-priority work forever. Zero (the default)
+// claims by priority alone.
+func (r *PostgresJobRepository) SetPriorityAging(step time.Duration) {
+	r.priorityAging = step
+}
+
+// SetClaimPolicy sets the order ClaimPendingJobs and PeekClaimable
+// hand out jobs in. The default is ClaimFIFO.
+func (r *PostgresJobRepository) SetClaimPolicy(policy ClaimPolicy) {
+	r.claimPolicy = policy
+}
+
+// SetMaxConcurrentClaims caps how many ClaimPendingJobs transactions this
+// repository runs at once. Each claim holds a pool connection across its
+// select and update, so several schedulers polling a small pool could
+// otherwise leave no connections for API queries. Extra claims wait for a
+// slot, or fail once their context is done. Zero or less means no cap.
+// Call it before the repository is used.
+func (r *PostgresJobRepository) SetMaxConcurrentClaims(n int) {
+	if n <= 0 {
+		r.claimSlots = nil
+		return
+	}
+	r.claimSlots = semaphore.NewWeighted(int64(n))
+}
+
+// SetAllowDestructive enables TruncateAll. Only tests should call this:
+// it lets one call wipe the jobs table.
+func (r *PostgresJobRepository) SetAllowDestructive(allow bool) {
+	r.allowDestructive = allow
+}
+
+// SetAcquireTimeout bounds how long each operation waits for a free pool
+// connection before failing with ErrPoolExhausted. Zero waits for as long
+// as the caller's context allows. Call it before the repository is used.
+func (r *PostgresJobRepository) SetAcquireTimeout(timeout time.Duration) {
+	if r.base == nil {
+		return // transactions already hold their connection
+	}
+	if timeout <= 0 {
+		r.pool = r.base
+		return
+	}
+	r.pool = &acquirePool{pool: r.base, timeout: timeout}
+}
+
+// WithTx runs fn in a database transaction.
+// The transaction commits if fn returns nil and rolls back otherwise.
+// Nested calls use savepoints, so they roll back independently.
+func (r *PostgresJobRepository) WithTx(ctx context.Context, fn func(tx JobRepository) error) error {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", classify(err))
+	}
+	defer tx.Rollback(ctx) // No-op once committed
+
+	if err := fn(&PostgresJobRepository{pool: tx, claimPolicy: r.claimPolicy, priorityAging: r.priorityAging, allowDestructive: r.allowDestructive}); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", classify(err))
 	}
+
+	return nil
+}
+
+// uniqueViolation is the Postgres error code for a unique constraint violation.
+const uniqueViolation = "23505"
+
+// idempotencyKeyIndex is the unique index on (idempotency_key, type).
+const idempotencyKeyIndex = "idx_jobs_idempotency_key"
+
+// skipTypesArg returns skipTypes as the $5 argument of claimableJobs.
+// A nil slice would be sent as NULL, which matches no rows.
+func skipTypesArg(skipTypes []string) []string {
+	if skipTypes == nil {
+		return []string{}
+	}
+	return skipTypes
+}
+
+// claimableJobs filters and orders jobs the way the scheduler claims them
+// under the repository's claim policy.
+// Takes $1 = PENDING, $2 = RETRYING, $3 = limit, $4 = now, $5 = types to skip.
+// Jobs are skipped until their run_at (pending) or backoff (retrying) has passed.
+// Priority aging ranks by an expression, so it can't use an index for ordering.
+func (r *PostgresJobRepository) claimableJobs() string {
+	priority := "priority"
+	if r.priorityAging > 0 {
+		priority = fmt.Sprintf("(priority + FLOOR(EXTRACT(EPOCH FROM ($4 - created_at)) / %g))",
+			r.priorityAging.Seconds())
+	}
+
+	order := priority + " DESC, created_at ASC, id ASC"
+	if r.claimPolicy == ClaimRetriesFirst {
+		order = priority + " DESC, (state = $2) DESC, created_at ASC, id ASC"
+	}
+
+	return `
+		WHERE ((state = $1 AND (run_at IS NULL OR run_at <= $4))
+			OR (state = $2 AND (next_retry_at IS NULL OR next_retry_at <= $4)))
+			AND type <> ALL($5)
+		ORDER BY ` + order + `
+		LIMIT $3`
+}
+
+// jobColumns lists the jobs table columns in the order scanJob reads them.
+const jobColumns = `
+			id, type, payload, state, attempt, max_attempts, last_error,
+			created_at, scheduled_at, started_at, completed_at, last_transition_reason,
+			priority, next_retry_at, run_at, schedule, parent_id, payload_ref, result, rerun_count,
+			idempotency_key`
+
+// scanJob reads a row selected with jobColumns into a Job.
+//
+// Required columns are scanned as nullable so a NULL left behind by a
+// manual edit is reported as ErrMalformedJob naming the job, rather than
+// as an opaque scan error.
+func scanJob(row pgx.Row) (*model.Job, error) {
+	var job model.Job
+	var (
+		jobType, jobState                      *string
+		attempt, maxAttempts, priority, reruns *int
+		createdAt                              *time.Time
+	)
+	err := row.Scan(
+		&job.ID,
+		&jobType,
+		&job.Payload,
+		&jobState,
+		&attempt,
+		&maxAttempts,
+		&job.LastError,
+		&createdAt,
+		&job.ScheduledAt,
+		&job.StartedAt,
+		&job.CompletedAt,
+		&job.LastTransitionReason,
+		&priority,
+		&job.NextRetryAt,
+		&job.RunAt,
+		&job.Schedule,
+		&job.ParentID,
+		&job.PayloadRef,
+		&job.Result,
+		&reruns,
+		&job.IdempotencyKey,
+	)
+	if err != nil {
+		// id is scanned first, so it's known unless that column failed
+		if job.ID != "" {
+			return nil, fmt.Errorf("job %s: %w", job.ID, err)
+		}
+		return nil, err
+	}
+
+	required := []struct {
+		column string
+		isNull bool
+	}{
+		{"type", jobType == nil},
+		{"state", jobState == nil},
+		{"attempt", attempt == nil},
+		{"max_attempts", maxAttempts == nil},
+		{"created_at", createdAt == nil},
+		{"priority", priority == nil},
+		{"rerun_count", reruns == nil},
+	}
+	for _, field := range required {
+		if field.isNull {
+			return nil, fmt.Errorf("%w: job %s has NULL %s", ErrMalformedJob, job.ID, field.column)
+		}
+	}
+
+	job.Type = *jobType
+	job.State = state.State(*jobState)
+	job.Attempt = *attempt
+	job.MaxAttempts = *maxAttempts
+	job.CreatedAt = *createdAt
+	job.Priority = *priority
+	job.RerunCount = *reruns
+	return &job, nil
+}
+
+// skipMalformed reports whether a list query should skip the row scanJob
+// failed on, logging it so operators can find and fix the row. One bad
+// row shouldn't make a whole page of jobs unreadable.
+func skipMalformed(err error) bool {
+	if !errors.Is(err, ErrMalformedJob) {
+		return false
+	}
+	slog.Warn("Skipping malformed job row", "error", err)
+	return true
 }
 
 // Create inserts a new job into the database.
 func (r *PostgresJobRepository) Create(ctx context.Context, job *model.Job) error {
 	query := `
-		INSERT INTO jobs (
-			id, type, payload, state, attempt, max_attempts, last_error,
-			created_at, scheduled_at, started_at, completed_at
+		INSERT INTO jobs (` + jobColumns + `
 		) VALUES (
-			$1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11
+			$1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20, $21
 		)
 	`
 
@@ -50,10 +277,27 @@ func (r *PostgresJobRepository) Create(ctx context.Context, job *model.Job) erro
 		job.ScheduledAt,
 		job.StartedAt,
 		job.CompletedAt,
+		job.LastTransitionReason,
+		job.Priority,
+		job.NextRetryAt,
+		job.RunAt,
+		job.Schedule,
+		job.ParentID,
+		job.PayloadRef,
+		job.Result,
+		job.RerunCount,
+		job.IdempotencyKey,
 	)
 
 	if err != nil {
-		return fmt.Errorf("failed to create job: %w", err)
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == uniqueViolation {
+			if pgErr.ConstraintName == idempotencyKeyIndex {
+				return fmt.Errorf("%w: idempotency key %s for type %s", ErrDuplicateJob, *job.IdempotencyKey, job.Type)
+			}
+			return fmt.Errorf("%w: %s", ErrDuplicateJob, job.ID)
+		}
+		return fmt.Errorf("failed to create job: %w", classify(err))
 	}
 
 	return nil
@@ -62,36 +306,135 @@ func (r *PostgresJobRepository) Create(ctx context.Context, job *model.Job) erro
 // GetByID retrieves a job by its ID.
 func (r *PostgresJobRepository) GetByID(ctx context.Context, id string) (*model.Job, error) {
 	query := `
-		SELECT 
-			id, type, payload, state, attempt, max_attempts, last_error,
-			created_at, scheduled_at, started_at, completed_at
+		SELECT ` + jobColumns + `
 		FROM jobs
 		WHERE id = $1
 	`
 
-	var job model.Job
-	err := r.pool.QueryRow(ctx, query, id).Scan(
-		&job.ID,
-		&job.Type,
-		&job.Payload,
-		&job.State,
-		&job.Attempt,
-		&job.MaxAttempts,
-		&job.LastError,
-		&job.CreatedAt,
-		&job.ScheduledAt,
-		&job.StartedAt,
-		&job.CompletedAt,
-	)
-
+	job, err := scanJob(r.pool.QueryRow(ctx, query, id))
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			return nil, nil // Job not found, return nil without error
 		}
-		return nil, fmt.Errorf("failed to get job by ID: %w", err)
+		return nil, fmt.Errorf("failed to get job by ID: %w", classify(err))
 	}
 
-	return &job, nil
+	return job, nil
+}
+
+// GetByIDForUpdate retrieves a job by ID and locks its row for the rest
+// of the transaction.
+func (r *PostgresJobRepository) GetByIDForUpdate(ctx context.Context, id string) (*model.Job, error) {
+	query := `
+		SELECT ` + jobColumns + `
+		FROM jobs
+		WHERE id = $1
+		FOR UPDATE
+	`
+
+	job, err := scanJob(r.pool.QueryRow(ctx, query, id))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get job by ID for update: %w", classify(err))
+	}
+
+	return job, nil
+}
+
+// GetByIdempotencyKey retrieves the job of a type created with key.
+func (r *PostgresJobRepository) GetByIdempotencyKey(ctx context.Context, key, jobType string) (*model.Job, error) {
+	query := `
+		SELECT ` + jobColumns + `
+		FROM jobs
+		WHERE idempotency_key = $1 AND type = $2
+	`
+
+	job, err := scanJob(r.pool.QueryRow(ctx, query, key, jobType))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get job by idempotency key: %w", classify(err))
+	}
+
+	return job, nil
+}
+
+// GetStates returns the state of each listed job that exists, keyed by ID.
+func (r *PostgresJobRepository) GetStates(ctx context.Context, ids []string) (map[string]state.State, error) {
+	query := `SELECT id, state FROM jobs WHERE id = ANY($1)`
+
+	rows, err := r.pool.Query(ctx, query, ids)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get job states: %w", classify(err))
+	}
+	defer rows.Close()
+
+	states := make(map[string]state.State, len(ids))
+	for rows.Next() {
+		var id string
+		var jobState state.State
+		if err := rows.Scan(&id, &jobState); err != nil {
+			return nil, fmt.Errorf("failed to scan job state: %w", classify(err))
+		}
+		states[id] = jobState
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating job states: %w", classify(err))
+	}
+
+	return states, nil
+}
+
+// Exists reports whether a job with the given ID exists.
+func (r *PostgresJobRepository) Exists(ctx context.Context, id string) (bool, error) {
+	query := `SELECT EXISTS(SELECT 1 FROM jobs WHERE id = $1)`
+
+	var exists bool
+	if err := r.pool.QueryRow(ctx, query, id).Scan(&exists); err != nil {
+		return false, fmt.Errorf("failed to check job exists: %w", classify(err))
+	}
+
+	return exists, nil
+}
+
+// UpdateStateBatch updates the state of every listed job still in from.
+// The state check in the WHERE clause makes it safe against concurrent transitions.
+func (r *PostgresJobRepository) UpdateStateBatch(ctx context.Context, ids []string, from, to state.State, reason string) ([]string, error) {
+	query := `
+		UPDATE jobs
+		SET
+			state = $1,
+			last_transition_reason = NULLIF($4, ''),
+			scheduled_at = CASE WHEN $5 THEN NULL ELSE scheduled_at END,
+			started_at = CASE WHEN $5 THEN NULL ELSE started_at END
+		WHERE id = ANY($2) AND state = $3
+		RETURNING id
+	`
+
+	rows, err := r.pool.Query(ctx, query, to, ids, from, reason, isUnclaimed(to))
+	if err != nil {
+		return nil, fmt.Errorf("failed to update job states: %w", classify(err))
+	}
+	defer rows.Close()
+
+	updated := []string{}
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("failed to scan job ID: %w", classify(err))
+		}
+		updated = append(updated, id)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating job IDs: %w", classify(err))
+	}
+
+	return updated, nil
 }
 
 // UpdateState updates only the state field of a job.
@@ -104,7 +447,7 @@ func (r *PostgresJobRepository) UpdateState(ctx context.Context, id string, newS
 
 	result, err := r.pool.Exec(ctx, query, newState, id)
 	if err != nil {
-		return fmt.Errorf("failed to update job state: %w", err)
+		return fmt.Errorf("failed to update job state: %w", classify(err))
 	}
 
 	if result.RowsAffected() == 0 {
@@ -128,7 +471,17 @@ func (r *PostgresJobRepository) Update(ctx context.Context, job *model.Job) erro
 			created_at = $8,
 			scheduled_at = $9,
 			started_at = $10,
-			completed_at = $11
+			completed_at = $11,
+			last_transition_reason = $12,
+			priority = $13,
+			next_retry_at = $14,
+			run_at = $15,
+			schedule = $16,
+			parent_id = $17,
+			payload_ref = $18,
+			result = $19,
+			rerun_count = $20,
+			idempotency_key = $21
 		WHERE id = $1
 	`
 
@@ -146,10 +499,20 @@ func (r *PostgresJobRepository) Update(ctx context.Context, job *model.Job) erro
 		job.ScheduledAt,
 		job.StartedAt,
 		job.CompletedAt,
+		job.LastTransitionReason,
+		job.Priority,
+		job.NextRetryAt,
+		job.RunAt,
+		job.Schedule,
+		job.ParentID,
+		job.PayloadRef,
+		job.Result,
+		job.RerunCount,
+		job.IdempotencyKey,
 	)
 
 	if err != nil {
-		return fmt.Errorf("failed to update job: %w", err)
+		return fmt.Errorf("failed to update job: %w", classify(err))
 	}
 
 	if result.RowsAffected() == 0 {
@@ -159,60 +522,237 @@ func (r *PostgresJobRepository) Update(ctx context.Context, job *model.Job) erro
 	return nil
 }
 
+// UpdateProgress writes only the mutable lifecycle fields of a job.
+func (r *PostgresJobRepository) UpdateProgress(ctx context.Context, job *model.Job) error {
+	query := `
+		UPDATE jobs
+		SET
+			state = $2,
+			attempt = $3,
+			last_error = $4,
+			scheduled_at = $5,
+			started_at = $6,
+			completed_at = $7,
+			last_transition_reason = $8,
+			priority = $9,
+			next_retry_at = $10
+		WHERE id = $1
+	`
+
+	result, err := r.pool.Exec(
+		ctx,
+		query,
+		job.ID,
+		job.State,
+		job.Attempt,
+		job.LastError,
+		job.ScheduledAt,
+		job.StartedAt,
+		job.CompletedAt,
+		job.LastTransitionReason,
+		job.Priority,
+		job.NextRetryAt,
+	)
+
+	if err != nil {
+		return fmt.Errorf("failed to update job progress: %w", classify(err))
+	}
+
+	if result.RowsAffected() == 0 {
+		return fmt.Errorf("job not found: %s", job.ID)
+	}
+
+	return nil
+}
+
+// ListByResultKey returns SUCCEEDED jobs whose result has key set to value,
+// ordered by creation time. Matching uses JSONB containment on result.
+func (r *PostgresJobRepository) ListByResultKey(ctx context.Context, key, value string, limit int) ([]*model.Job, error) {
+	query := `
+		SELECT ` + jobColumns + `
+		FROM jobs
+		WHERE state = $1 AND result @> $2
+		ORDER BY created_at ASC, id ASC
+		LIMIT $3
+	`
+
+	filter, err := json.Marshal(map[string]string{key: value})
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode result filter: %w", classify(err))
+	}
+
+	rows, err := r.pool.Query(ctx, query, state.SUCCEEDED, filter, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list jobs by result: %w", classify(err))
+	}
+	defer rows.Close()
+
+	jobs := []*model.Job{}
+	for rows.Next() {
+		job, err := scanJob(rows)
+		if skipMalformed(err) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan job: %w", classify(err))
+		}
+		jobs = append(jobs, job)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating rows: %w", classify(err))
+	}
+
+	return jobs, nil
+}
+
 // ListByState returns jobs with a specific state, ordered by creation time.
 func (r *PostgresJobRepository) ListByState(ctx context.Context, jobState state.State, limit int) ([]*model.Job, error) {
+	return r.ListByStateOrdered(ctx, jobState, limit, SortAsc)
+}
+
+// ListByStateOrdered returns jobs with a specific state in the given creation order.
+func (r *PostgresJobRepository) ListByStateOrdered(ctx context.Context, jobState state.State, limit int, order SortOrder) ([]*model.Job, error) {
+	direction := "ASC"
+	if order == SortDesc {
+		direction = "DESC"
+	}
+
 	query := `
-		SELECT 
-			id, type, payload, state, attempt, max_attempts, last_error,
-			created_at, scheduled_at, started_at, completed_at
+		SELECT ` + jobColumns + `
 		FROM jobs
 		WHERE state = $1
-		ORDER BY created_at ASC
+		ORDER BY created_at ` + direction + `, id ` + direction + `
 		LIMIT $2
 	`
 
 	rows, err := r.pool.Query(ctx, query, jobState, limit)
 	if err != nil {
-		return nil, fmt.Errorf("failed to list jobs by state: %w", err)
+		return nil, fmt.Errorf("failed to list jobs by state: %w", classify(err))
 	}
 	defer rows.Close()
 
 	var jobs []*model.Job
 	for rows.Next() {
-		var job model.Job
-		err := rows.Scan(
-			&job.ID,
-			&job.Type,
-			&job.Payload,
-			&job.State,
-			&job.Attempt,
-			&job.MaxAttempts,
-			&job.LastError,
-			&job.CreatedAt,
-			&job.ScheduledAt,
-			&job.StartedAt,
-			&job.CompletedAt,
-		)
+		job, err := scanJob(rows)
+		if skipMalformed(err) {
+			continue
+		}
 		if err != nil {
-			return nil, fmt.Errorf("failed to scan job: %w", err)
+			return nil, fmt.Errorf("failed to scan job: %w", classify(err))
 		}
-		jobs = append(jobs, &job)
+		jobs = append(jobs, job)
 	}
 
 	if err := rows.Err(); err != nil {
-		return nil, fmt.Errorf("error iterating jobs: %w", err)
+		return nil, fmt.Errorf("error iterating jobs: %w", classify(err))
 	}
 
 	return jobs, nil
 }
 
+// CountByState returns how many jobs are in any of the given states.
+func (r *PostgresJobRepository) CountByState(ctx context.Context, states ...state.State) (int, error) {
+	query := `SELECT COUNT(*) FROM jobs WHERE state = ANY($1)`
+
+	var count int
+	if err := r.pool.QueryRow(ctx, query, states).Scan(&count); err != nil {
+		return 0, fmt.Errorf("failed to count jobs by state: %w", classify(err))
+	}
+
+	return count, nil
+}
+
+// CountByTypeAndState returns job counts grouped by type and state.
+func (r *PostgresJobRepository) CountByTypeAndState(ctx context.Context) (map[string]map[state.State]int, error) {
+	query := `SELECT type, state, COUNT(*) FROM jobs GROUP BY type, state`
+
+	rows, err := r.pool.Query(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count jobs by type and state: %w", classify(err))
+	}
+	defer rows.Close()
+
+	counts := make(map[string]map[state.State]int)
+	for rows.Next() {
+		var (
+			jobType  string
+			jobState state.State
+			count    int
+		)
+		if err := rows.Scan(&jobType, &jobState, &count); err != nil {
+			return nil, fmt.Errorf("failed to scan job count: %w", classify(err))
+		}
+		if counts[jobType] == nil {
+			counts[jobType] = make(map[state.State]int)
+		}
+		counts[jobType][jobState] = count
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating job counts: %w", classify(err))
+	}
+
+	return counts, nil
+}
+
+// OldestPendingAge returns how long the longest-waiting due PENDING job has been claimable.
+func (r *PostgresJobRepository) OldestPendingAge(ctx context.Context, now time.Time) (time.Duration, error) {
+	query := `
+		SELECT MIN(GREATEST(created_at, COALESCE(run_at, created_at)))
+		FROM jobs
+		WHERE state = $1 AND (run_at IS NULL OR run_at <= $2)
+	`
+
+	var oldest *time.Time
+	if err := r.pool.QueryRow(ctx, query, state.PENDING, now).Scan(&oldest); err != nil {
+		return 0, fmt.Errorf("failed to get oldest pending job: %w", classify(err))
+	}
+	if oldest == nil {
+		return 0, nil
+	}
+
+	return max(now.Sub(*oldest), 0), nil
+}
+
+// UpdateMaxAttempts sets a live job's max_attempts in one conditional
+// statement, so it can't race a worker saving the job's progress.
+func (r *PostgresJobRepository) UpdateMaxAttempts(ctx context.Context, id string, maxAttempts int) (bool, error) {
+	query := `
+		UPDATE jobs
+		SET max_attempts = $2
+		WHERE id = $1
+			AND attempt <= $2
+			AND state NOT IN ($3, $4, $5)
+	`
+
+	result, err := r.pool.Exec(ctx, query, id, maxAttempts, state.SUCCEEDED, state.FAILED, state.CANCELLED)
+	if err != nil {
+		return false, fmt.Errorf("failed to update max attempts: %w", classify(err))
+	}
+
+	return result.RowsAffected() == 1, nil
+}
+
+// TruncateAll deletes every job; error history goes with them.
+func (r *PostgresJobRepository) TruncateAll(ctx context.Context) error {
+	if !r.allowDestructive {
+		return ErrDestructiveDisabled
+	}
+
+	if _, err := r.pool.Exec(ctx, `TRUNCATE jobs, job_errors`); err != nil {
+		return fmt.Errorf("failed to truncate jobs: %w", classify(err))
+	}
+	return nil
+}
+
 // Delete removes a job from the database.
 func (r *PostgresJobRepository) Delete(ctx context.Context, id string) error {
 	query := `DELETE FROM jobs WHERE id = $1`
 
 	result, err := r.pool.Exec(ctx, query, id)
 	if err != nil {
-		return fmt.Errorf("failed to delete job: %w", err)
+		return fmt.Errorf("failed to delete job: %w", classify(err))
 	}
 
 	if result.RowsAffected() == 0 {
@@ -222,34 +762,222 @@ func (r *PostgresJobRepository) Delete(ctx context.Context, id string) error {
 	return nil
 }
 
-// ClaimPendingJobs atomically claims pending jobs by locking and transitioning them to SCHEDULED.
-// This prevents race conditions when multiple schedulers are running.
+// RecordAttemptError appends an entry to a job's error history.
+func (r *PostgresJobRepository) RecordAttemptError(ctx context.Context, attemptErr *model.AttemptError) error {
+	query := `
+		INSERT INTO job_errors (job_id, attempt, error, occurred_at)
+		VALUES ($1, $2, $3, $4)
+	`
+
+	_, err := r.pool.Exec(
+		ctx,
+		query,
+		attemptErr.JobID,
+		attemptErr.Attempt,
+		attemptErr.Error,
+		attemptErr.OccurredAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to record attempt error: %w", classify(err))
+	}
+
+	return nil
+}
+
+// ListAttemptErrors returns a job's error history, oldest first.
+func (r *PostgresJobRepository) ListAttemptErrors(ctx context.Context, jobID string) ([]*model.AttemptError, error) {
+	query := `
+		SELECT job_id, attempt, error, occurred_at
+		FROM job_errors
+		WHERE job_id = $1
+		ORDER BY occurred_at ASC, id ASC
+	`
+
+	rows, err := r.pool.Query(ctx, query, jobID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list attempt errors: %w", classify(err))
+	}
+	defer rows.Close()
+
+	attemptErrs := []*model.AttemptError{}
+	for rows.Next() {
+		var attemptErr model.AttemptError
+		err := rows.Scan(
+			&attemptErr.JobID,
+			&attemptErr.Attempt,
+			&attemptErr.Error,
+			&attemptErr.OccurredAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan attempt error: %w", classify(err))
+		}
+		attemptErrs = append(attemptErrs, &attemptErr)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating attempt errors: %w", classify(err))
+	}
+
+	return attemptErrs, nil
+}
+
+// FindStaleScheduled returns SCHEDULED jobs whose scheduled_at is before cutoff.
+func (r *PostgresJobRepository) FindStaleScheduled(ctx context.Context, cutoff time.Time) ([]*model.Job, error) {
+	query := `
+		SELECT ` + jobColumns + `
+		FROM jobs
+		WHERE state = $1 AND scheduled_at < $2
+		ORDER BY scheduled_at ASC, id ASC
+	`
+
+	rows, err := r.pool.Query(ctx, query, state.SCHEDULED, cutoff)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find stale scheduled jobs: %w", classify(err))
+	}
+	defer rows.Close()
+
+	jobs := []*model.Job{}
+	for rows.Next() {
+		job, err := scanJob(rows)
+		if skipMalformed(err) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan job: %w", classify(err))
+		}
+		jobs = append(jobs, job)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating jobs: %w", classify(err))
+	}
+
+	return jobs, nil
+}
+
+// DistinctTypes returns the sorted set of job types present in the jobs table.
+func (r *PostgresJobRepository) DistinctTypes(ctx context.Context) ([]string, error) {
+	query := `SELECT DISTINCT type FROM jobs ORDER BY type`
+
+	rows, err := r.pool.Query(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list job types: %w", classify(err))
+	}
+	defer rows.Close()
+
+	types := []string{}
+	for rows.Next() {
+		var jobType string
+		if err := rows.Scan(&jobType); err != nil {
+			return nil, fmt.Errorf("failed to scan job type: %w", classify(err))
+		}
+		types = append(types, jobType)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating job types: %w", classify(err))
+	}
+
+	return types, nil
+}
+
+// TopErrors returns the most common last_error values among FAILED jobs.
+func (r *PostgresJobRepository) TopErrors(ctx context.Context, limit int) ([]model.ErrorCount, error) {
+	query := `
+		SELECT last_error, COUNT(*)
+		FROM jobs
+		WHERE state = $1 AND last_error IS NOT NULL
+		GROUP BY last_error
+		ORDER BY COUNT(*) DESC, last_error ASC
+		LIMIT $2
+	`
+
+	rows, err := r.pool.Query(ctx, query, state.FAILED, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list top errors: %w", classify(err))
+	}
+	defer rows.Close()
+
+	counts := []model.ErrorCount{}
+	for rows.Next() {
+		var count model.ErrorCount
+		if err := rows.Scan(&count.Error, &count.Count); err != nil {
+			return nil, fmt.Errorf("failed to scan error count: %w", classify(err))
+		}
+		counts = append(counts, count)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating error counts: %w", classify(err))
+	}
+
+	return counts, nil
+}
+
+// PeekClaimable returns the jobs ClaimPendingJobs would claim next,
+// without FOR UPDATE or the state change.
+func (r *PostgresJobRepository) PeekClaimable(ctx context.Context, limit int, now time.Time, skipTypes ...string) ([]*model.Job, error) {
+	query := `
+		SELECT ` + jobColumns + `
+		FROM jobs` + r.claimableJobs()
+
+	rows, err := r.pool.Query(ctx, query, state.PENDING, state.RETRYING, limit, now, skipTypesArg(skipTypes))
+	if err != nil {
+		return nil, fmt.Errorf("failed to peek claimable jobs: %w", classify(err))
+	}
+	defer rows.Close()
+
+	jobs := []*model.Job{}
+	for rows.Next() {
+		job, err := scanJob(rows)
+		if skipMalformed(err) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan job: %w", classify(err))
+		}
+		jobs = append(jobs, job)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating jobs: %w", classify(err))
+	}
+
+	return jobs, nil
+}
+
 // ClaimPendingJobs atomically claims pending and retrying jobs by locking and transitioning them to SCHEDULED.
 // This prevents race conditions when multiple schedulers are running.
-func (r *PostgresJobRepository) ClaimPendingJobs(ctx context.Context, limit int) ([]*model.Job, error) {
+// Higher-priority jobs are claimed first, oldest first within a priority,
+// with the job ID breaking ties between jobs created in the same instant.
+// RETRYING jobs are only claimed once their next_retry_at is at or before now.
+// Jobs of the skipTypes, e.g. types paused in the worker pool, are left PENDING/RETRYING.
+func (r *PostgresJobRepository) ClaimPendingJobs(ctx context.Context, limit int, now time.Time, skipTypes ...string) ([]*model.Job, error) {
+	if r.claimSlots != nil {
+		if err := r.claimSlots.Acquire(ctx, 1); err != nil {
+			return nil, fmt.Errorf("failed to wait for a claim slot: %w", err)
+		}
+		defer r.claimSlots.Release(1)
+	}
+
 	// Start a transaction - critical for holding the lock
 	tx, err := r.pool.Begin(ctx)
 	if err != nil {
-		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+		return nil, fmt.Errorf("failed to begin transaction: %w", classify(err))
 	}
 	defer tx.Rollback(ctx) // Rollback if we don't commit
 
 	// Query with FOR UPDATE SKIP LOCKED to prevent race conditions
 	// Pick up both PENDING (new jobs) and RETRYING (failed jobs ready to retry)
 	query := `
-		SELECT
-			id, type, payload, state, attempt, max_attempts, last_error,
-			created_at, scheduled_at, started_at, completed_at
-		FROM jobs
-		WHERE state IN ($1, $2)
-		ORDER BY created_at ASC
-		LIMIT $3
+		SELECT ` + jobColumns + `
+		FROM jobs` + r.claimableJobs() + `
 		FOR UPDATE SKIP LOCKED
 	`
 
-	rows, err := tx.Query(ctx, query, state.PENDING, state.RETRYING, limit)
+	rows, err := tx.Query(ctx, query, state.PENDING, state.RETRYING, limit, now, skipTypesArg(skipTypes))
 	if err != nil {
-		return nil, fmt.Errorf("failed to query pending jobs: %w", err)
+		return nil, fmt.Errorf("failed to query pending jobs: %w", classify(err))
 	}
 	defer rows.Close()
 
@@ -258,22 +986,12 @@ func (r *PostgresJobRepository) ClaimPendingJobs(ctx context.Context, limit int)
 	var jobIDs []string
 
 	for rows.Next() {
-		job := &model.Job{}
-		err := rows.Scan(
-			&job.ID,
-			&job.Type,
-			&job.Payload,
-			&job.State,
-			&job.Attempt,
-			&job.MaxAttempts,
-			&job.LastError,
-			&job.CreatedAt,
-			&job.ScheduledAt,
-			&job.StartedAt,
-			&job.CompletedAt,
-		)
+		job, err := scanJob(rows)
+		if skipMalformed(err) {
+			continue
+		}
 		if err != nil {
-			return nil, fmt.Errorf("failed to scan job: %w", err)
+			return nil, fmt.Errorf("failed to scan job: %w", classify(err))
 		}
 
 		jobs = append(jobs, job)
@@ -281,7 +999,7 @@ func (r *PostgresJobRepository) ClaimPendingJobs(ctx context.Context, limit int)
 	}
 
 	if err := rows.Err(); err != nil {
-		return nil, fmt.Errorf("error iterating rows: %w", err)
+		return nil, fmt.Errorf("error iterating rows: %w", classify(err))
 	}
 
 	// If no jobs found, return empty slice (not an error)
@@ -296,15 +1014,14 @@ func (r *PostgresJobRepository) ClaimPendingJobs(ctx context.Context, limit int)
 		WHERE id = ANY($3)
 	`
 
-	now := time.Now()
 	_, err = tx.Exec(ctx, updateQuery, state.SCHEDULED, now, jobIDs)
 	if err != nil {
-		return nil, fmt.Errorf("failed to update jobs to SCHEDULED: %w", err)
+		return nil, fmt.Errorf("failed to update jobs to SCHEDULED: %w", classify(err))
 	}
 
 	// Commit the transaction - this releases the locks
 	if err := tx.Commit(ctx); err != nil {
-		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+		return nil, fmt.Errorf("failed to commit transaction: %w", classify(err))
 	}
 
 	// Update the in-memory job objects to reflect the new state
@@ -3,7 +3,11 @@ package repository
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt" // ← Add this
+	"reflect"
+	"slices"
+	"strings"
 	"testing"
 	"time"
 
@@ -31,13 +35,15 @@ func setupTestDB(t *testing.T) *PostgresJobRepository {
 		t.Fatalf("Failed to create connection pool: %v", err)
 	}
 
+	repo := NewPostgresJobRepository(pool)
+	repo.SetAllowDestructive(true)
+
 	// Clean up test data before each test
-	_, err = pool.Exec(context.Background(), "DELETE FROM jobs")
-	if err != nil {
+	if err := repo.TruncateAll(context.Background()); err != nil {
 		t.Fatalf("Failed to clean test data: %v", err)
 	}
 
-	return NewPostgresJobRepository(pool)
+	return repo
 }
 
 func TestCreate(t *testing.T) {
@@ -90,6 +96,37 @@ func TestGetByID_NotFound(t *testing.T) {
 	}
 }
 
+func TestExists(t *testing.T) {
+	repo := setupTestDB(t)
+	ctx := context.Background()
+
+	repo.Create(ctx, &model.Job{
+		ID:          "test_job_exists",
+		Type:        "test",
+		Payload:     []byte(`{}`),
+		State:       state.PENDING,
+		Attempt:     1,
+		MaxAttempts: 3,
+		CreatedAt:   time.Now(),
+	})
+
+	exists, err := repo.Exists(ctx, "test_job_exists")
+	if err != nil {
+		t.Fatalf("Exists failed: %v", err)
+	}
+	if !exists {
+		t.Error("Exists = false for a stored job, want true")
+	}
+
+	exists, err = repo.Exists(ctx, "nonexistent")
+	if err != nil {
+		t.Fatalf("Exists failed: %v", err)
+	}
+	if exists {
+		t.Error("Exists = true for a missing job, want false")
+	}
+}
+
 func TestUpdateState(t *testing.T) {
 	repo := setupTestDB(t)
 	ctx := context.Background()
@@ -174,6 +211,45 @@ func TestListByState(t *testing.T) {
 	}
 }
 
+func TestListByStateOrdered(t *testing.T) {
+	repo := setupTestDB(t)
+	ctx := context.Background()
+	now := time.Now()
+
+	for i := 1; i <= 3; i++ {
+		repo.Create(ctx, &model.Job{
+			ID:          fmt.Sprintf("test_job_%d", i),
+			Type:        "test",
+			Payload:     []byte(`{}`),
+			State:       state.PENDING,
+			Attempt:     1,
+			MaxAttempts: 3,
+			CreatedAt:   now.Add(time.Duration(i) * time.Second),
+		})
+	}
+
+	tests := []struct {
+		order SortOrder
+		want  []string
+	}{
+		{SortAsc, []string{"test_job_1", "test_job_2"}},
+		{SortDesc, []string{"test_job_3", "test_job_2"}},
+	}
+	for _, tt := range tests {
+		jobs, err := repo.ListByStateOrdered(ctx, state.PENDING, 2, tt.order)
+		if err != nil {
+			t.Fatalf("ListByStateOrdered(%s) failed: %v", tt.order, err)
+		}
+		var got []string
+		for _, job := range jobs {
+			got = append(got, job.ID)
+		}
+		if !slices.Equal(got, tt.want) {
+			t.Errorf("ListByStateOrdered(%s) = %v, want %v", tt.order, got, tt.want)
+		}
+	}
+}
+
 func TestUpdate(t *testing.T) {
 	repo := setupTestDB(t)
 	ctx := context.Background()
@@ -218,6 +294,34 @@ func TestUpdate(t *testing.T) {
 	}
 }
 
+func TestUpdate_TransitionReason(t *testing.T) {
+	repo := setupTestDB(t)
+	ctx := context.Background()
+
+	job := &model.Job{
+		ID:          "test_job_reason",
+		Type:        "test",
+		Payload:     []byte(`{}`),
+		State:       state.PENDING,
+		Attempt:     1,
+		MaxAttempts: 3,
+		CreatedAt:   time.Now(),
+	}
+	repo.Create(ctx, job)
+
+	reason := "user cancelled via API"
+	job.State = state.CANCELLED
+	job.LastTransitionReason = &reason
+	if err := repo.Update(ctx, job); err != nil {
+		t.Fatalf("Update failed: %v", err)
+	}
+
+	retrieved, _ := repo.GetByID(ctx, job.ID)
+	if retrieved.LastTransitionReason == nil || *retrieved.LastTransitionReason != reason {
+		t.Errorf("LastTransitionReason = %v, want %q", retrieved.LastTransitionReason, reason)
+	}
+}
+
 func TestDelete(t *testing.T) {
 	repo := setupTestDB(t)
 	ctx := context.Background()
@@ -249,3 +353,950 @@ func TestDelete(t *testing.T) {
 		t.Error("Job should be deleted, but still exists")
 	}
 }
+
+func TestAttemptErrors(t *testing.T) {
+	repo := setupTestDB(t)
+	ctx := context.Background()
+
+	job := &model.Job{
+		ID:          "test_job_errors",
+		Type:        "test",
+		Payload:     []byte(`{}`),
+		State:       state.RUNNING,
+		Attempt:     1,
+		MaxAttempts: 3,
+		CreatedAt:   time.Now(),
+	}
+	repo.Create(ctx, job)
+
+	// No errors yet
+	errs, err := repo.ListAttemptErrors(ctx, job.ID)
+	if err != nil {
+		t.Fatalf("ListAttemptErrors failed: %v", err)
+	}
+	if len(errs) != 0 {
+		t.Fatalf("Expected no errors, got %d", len(errs))
+	}
+
+	// Record two attempt errors
+	for i, msg := range []string{"timeout", "connection reset"} {
+		err := repo.RecordAttemptError(ctx, &model.AttemptError{
+			JobID:      job.ID,
+			Attempt:    i + 1,
+			Error:      msg,
+			OccurredAt: time.Now(),
+		})
+		if err != nil {
+			t.Fatalf("RecordAttemptError failed: %v", err)
+		}
+	}
+
+	errs, err = repo.ListAttemptErrors(ctx, job.ID)
+	if err != nil {
+		t.Fatalf("ListAttemptErrors failed: %v", err)
+	}
+	if len(errs) != 2 {
+		t.Fatalf("Expected 2 errors, got %d", len(errs))
+	}
+	if errs[0].Error != "timeout" || errs[1].Error != "connection reset" {
+		t.Errorf("Errors not in attempt order: %q, %q", errs[0].Error, errs[1].Error)
+	}
+}
+
+func TestFindStaleScheduled(t *testing.T) {
+	repo := setupTestDB(t)
+	ctx := context.Background()
+
+	backdated := time.Now().Add(-time.Hour)
+	now := time.Now()
+
+	stale := &model.Job{
+		ID:          "test_job_stale",
+		Type:        "test",
+		Payload:     []byte(`{}`),
+		State:       state.SCHEDULED,
+		Attempt:     1,
+		MaxAttempts: 3,
+		CreatedAt:   backdated,
+		ScheduledAt: &backdated,
+	}
+	fresh := &model.Job{
+		ID:          "test_job_fresh",
+		Type:        "test",
+		Payload:     []byte(`{}`),
+		State:       state.SCHEDULED,
+		Attempt:     1,
+		MaxAttempts: 3,
+		CreatedAt:   now,
+		ScheduledAt: &now,
+	}
+	repo.Create(ctx, stale)
+	repo.Create(ctx, fresh)
+
+	jobs, err := repo.FindStaleScheduled(ctx, time.Now().Add(-10*time.Minute))
+	if err != nil {
+		t.Fatalf("FindStaleScheduled failed: %v", err)
+	}
+	if len(jobs) != 1 || jobs[0].ID != stale.ID {
+		t.Errorf("Expected only %s, got %d jobs", stale.ID, len(jobs))
+	}
+}
+
+func TestDistinctTypes(t *testing.T) {
+	repo := setupTestDB(t)
+	ctx := context.Background()
+
+	for i, jobType := range []string{"send_email", "resize_image", "send_email"} {
+		repo.Create(ctx, &model.Job{
+			ID:          fmt.Sprintf("test_job_type_%d", i),
+			Type:        jobType,
+			Payload:     []byte(`{}`),
+			State:       state.PENDING,
+			Attempt:     1,
+			MaxAttempts: 3,
+			CreatedAt:   time.Now(),
+		})
+	}
+
+	types, err := repo.DistinctTypes(ctx)
+	if err != nil {
+		t.Fatalf("DistinctTypes failed: %v", err)
+	}
+	if len(types) != 2 || types[0] != "resize_image" || types[1] != "send_email" {
+		t.Errorf("Types = %v, want [resize_image send_email]", types)
+	}
+}
+
+func TestCountByTypeAndState(t *testing.T) {
+	repo := setupTestDB(t)
+	ctx := context.Background()
+
+	jobs := []struct {
+		jobType  string
+		jobState state.State
+	}{
+		{"email", state.PENDING},
+		{"email", state.PENDING},
+		{"email", state.RUNNING},
+		{"email", state.FAILED},
+		{"resize", state.PENDING},
+		{"resize", state.FAILED},
+		{"resize", state.FAILED},
+	}
+	for i, job := range jobs {
+		repo.Create(ctx, &model.Job{
+			ID:          fmt.Sprintf("test_job_matrix_%d", i),
+			Type:        job.jobType,
+			Payload:     []byte(`{}`),
+			State:       job.jobState,
+			Attempt:     1,
+			MaxAttempts: 3,
+			CreatedAt:   time.Now(),
+		})
+	}
+
+	counts, err := repo.CountByTypeAndState(ctx)
+	if err != nil {
+		t.Fatalf("CountByTypeAndState failed: %v", err)
+	}
+	want := map[string]map[state.State]int{
+		"email":  {state.PENDING: 2, state.RUNNING: 1, state.FAILED: 1},
+		"resize": {state.PENDING: 1, state.FAILED: 2},
+	}
+	if !reflect.DeepEqual(counts, want) {
+		t.Errorf("CountByTypeAndState = %v, want %v", counts, want)
+	}
+}
+
+func TestTopErrors(t *testing.T) {
+	repo := setupTestDB(t)
+	ctx := context.Background()
+
+	errs := []string{"timeout", "refused", "timeout", "timeout", "refused", "disk full"}
+	for i, msg := range errs {
+		repo.Create(ctx, &model.Job{
+			ID:          fmt.Sprintf("test_job_top_error_%d", i),
+			Type:        "test",
+			Payload:     []byte(`{}`),
+			State:       state.FAILED,
+			Attempt:     3,
+			MaxAttempts: 3,
+			LastError:   &msg,
+			CreatedAt:   time.Now(),
+		})
+	}
+	// Only FAILED jobs count, even if another state carries an error
+	retrying := "timeout"
+	repo.Create(ctx, &model.Job{
+		ID:          "test_job_top_error_retrying",
+		Type:        "test",
+		Payload:     []byte(`{}`),
+		State:       state.RETRYING,
+		Attempt:     2,
+		MaxAttempts: 3,
+		LastError:   &retrying,
+		CreatedAt:   time.Now(),
+	})
+
+	counts, err := repo.TopErrors(ctx, 2)
+	if err != nil {
+		t.Fatalf("TopErrors failed: %v", err)
+	}
+	want := []model.ErrorCount{{Error: "timeout", Count: 3}, {Error: "refused", Count: 2}}
+	if len(counts) != len(want) || counts[0] != want[0] || counts[1] != want[1] {
+		t.Errorf("TopErrors = %+v, want %+v", counts, want)
+	}
+}
+
+func TestWithTx_RollsBackOnError(t *testing.T) {
+	repo := setupTestDB(t)
+	ctx := context.Background()
+
+	job := &model.Job{
+		ID:          "test_job_tx",
+		Type:        "test",
+		Payload:     []byte(`{}`),
+		State:       state.RUNNING,
+		Attempt:     1,
+		MaxAttempts: 3,
+		CreatedAt:   time.Now(),
+	}
+	repo.Create(ctx, job)
+
+	errBoom := fmt.Errorf("boom")
+	err := repo.WithTx(ctx, func(tx JobRepository) error {
+		updated := *job
+		updated.State = state.RETRYING
+		updated.Attempt = 2
+		if err := tx.Update(ctx, &updated); err != nil {
+			t.Fatalf("Update failed: %v", err)
+		}
+		return errBoom
+	})
+	if err != errBoom {
+		t.Fatalf("WithTx error = %v, want %v", err, errBoom)
+	}
+
+	retrieved, _ := repo.GetByID(ctx, job.ID)
+	if retrieved.State != state.RUNNING || retrieved.Attempt != 1 {
+		t.Errorf("Job = %s attempt %d, want RUNNING attempt 1", retrieved.State, retrieved.Attempt)
+	}
+}
+
+func TestGetByIDForUpdate_BlocksConcurrentWriter(t *testing.T) {
+	repo := setupTestDB(t)
+	ctx := context.Background()
+
+	job := &model.Job{
+		ID:          "test_job_lock",
+		Type:        "test",
+		Payload:     []byte(`{}`),
+		State:       state.RUNNING,
+		Attempt:     1,
+		MaxAttempts: 3,
+		CreatedAt:   time.Now(),
+	}
+	repo.Create(ctx, job)
+
+	written := make(chan error, 1)
+	err := repo.WithTx(ctx, func(tx JobRepository) error {
+		locked, err := tx.GetByIDForUpdate(ctx, job.ID)
+		if err != nil || locked == nil {
+			t.Fatalf("GetByIDForUpdate = %v, %v; want the job", locked, err)
+		}
+
+		// A writer outside the transaction must wait for the lock
+		go func() { written <- repo.UpdateState(ctx, job.ID, state.FAILED) }()
+		select {
+		case err := <-written:
+			t.Fatalf("Concurrent write finished while the row was locked: %v", err)
+		case <-time.After(100 * time.Millisecond):
+		}
+
+		locked.State = state.RETRYING
+		return tx.UpdateProgress(ctx, locked)
+	})
+	if err != nil {
+		t.Fatalf("WithTx failed: %v", err)
+	}
+
+	if err := <-written; err != nil {
+		t.Fatalf("Concurrent write failed: %v", err)
+	}
+	retrieved, _ := repo.GetByID(ctx, job.ID)
+	if retrieved.State != state.FAILED {
+		t.Errorf("State = %s, want FAILED from the write that waited", retrieved.State)
+	}
+}
+
+func TestCreate_Duplicate(t *testing.T) {
+	repo := setupTestDB(t)
+	ctx := context.Background()
+
+	job := &model.Job{
+		ID:          "test_job_dup",
+		Type:        "test",
+		Payload:     []byte(`{}`),
+		State:       state.PENDING,
+		Attempt:     1,
+		MaxAttempts: 3,
+		CreatedAt:   time.Now(),
+	}
+	if err := repo.Create(ctx, job); err != nil {
+		t.Fatalf("First Create failed: %v", err)
+	}
+
+	if err := repo.Create(ctx, job); !errors.Is(err, ErrDuplicateJob) {
+		t.Errorf("Second Create error = %v, want ErrDuplicateJob", err)
+	}
+}
+
+func TestClaimPendingJobs_SkipsTypes(t *testing.T) {
+	repo := setupTestDB(t)
+	ctx := context.Background()
+
+	now := time.Now()
+	for _, jobType := range []string{"process_video", "send_email"} {
+		repo.Create(ctx, &model.Job{
+			ID:          "test_job_" + jobType,
+			Type:        jobType,
+			Payload:     []byte(`{}`),
+			State:       state.PENDING,
+			Attempt:     1,
+			MaxAttempts: 3,
+			CreatedAt:   now,
+		})
+	}
+
+	peeked, _ := repo.PeekClaimable(ctx, 10, now, "process_video")
+	if len(peeked) != 1 || peeked[0].Type != "send_email" {
+		t.Errorf("Peeked %v, want only the send_email job", peeked)
+	}
+
+	claimed, err := repo.ClaimPendingJobs(ctx, 10, now, "process_video")
+	if err != nil {
+		t.Fatalf("ClaimPendingJobs failed: %v", err)
+	}
+	if len(claimed) != 1 || claimed[0].Type != "send_email" {
+		t.Fatalf("Claimed %v, want only the send_email job", claimed)
+	}
+
+	// Without skipped types the other job is claimable again
+	if claimed, _ := repo.ClaimPendingJobs(ctx, 10, now); len(claimed) != 1 || claimed[0].Type != "process_video" {
+		t.Errorf("Claimed %v, want the process_video job", claimed)
+	}
+}
+
+func TestUpdateProgress_PreservesCreatedAt(t *testing.T) {
+	repo := setupTestDB(t)
+	ctx := context.Background()
+
+	createdAt := time.Now().Add(-time.Hour).Truncate(time.Microsecond)
+	job := &model.Job{
+		ID:          "test_job_progress",
+		Type:        "test",
+		Payload:     []byte(`{}`),
+		State:       state.SCHEDULED,
+		Attempt:     1,
+		MaxAttempts: 3,
+		CreatedAt:   createdAt,
+	}
+	repo.Create(ctx, job)
+
+	update := *job
+	update.State = state.RUNNING
+	update.CreatedAt = time.Now()
+	if err := repo.UpdateProgress(ctx, &update); err != nil {
+		t.Fatalf("UpdateProgress failed: %v", err)
+	}
+
+	retrieved, _ := repo.GetByID(ctx, job.ID)
+	if retrieved.State != state.RUNNING {
+		t.Errorf("State = %s, want RUNNING", retrieved.State)
+	}
+	if !retrieved.CreatedAt.Equal(createdAt) {
+		t.Errorf("CreatedAt = %v, want unchanged %v", retrieved.CreatedAt, createdAt)
+	}
+}
+
+func TestPeekClaimable_DoesNotClaim(t *testing.T) {
+	repo := setupTestDB(t)
+	ctx := context.Background()
+
+	for i := 0; i < 3; i++ {
+		repo.Create(ctx, &model.Job{
+			ID:          fmt.Sprintf("test_job_peek_%d", i),
+			Type:        "test",
+			Payload:     []byte(`{}`),
+			State:       state.PENDING,
+			Attempt:     1,
+			MaxAttempts: 3,
+			CreatedAt:   time.Now(),
+		})
+	}
+
+	peeked, err := repo.PeekClaimable(ctx, 10, time.Now())
+	if err != nil {
+		t.Fatalf("PeekClaimable failed: %v", err)
+	}
+	if len(peeked) != 3 {
+		t.Errorf("Peeked %d jobs, want 3", len(peeked))
+	}
+
+	pending, _ := repo.ListByState(ctx, state.PENDING, 10)
+	if len(pending) != 3 {
+		t.Errorf("Pending jobs after peek = %d, want 3", len(pending))
+	}
+}
+
+func TestUpdateStateBatch(t *testing.T) {
+	repo := setupTestDB(t)
+	ctx := context.Background()
+
+	// Three RUNNING jobs to complete, plus one that already moved on
+	for i, jobState := range []state.State{state.RUNNING, state.RUNNING, state.RUNNING, state.CANCELLED} {
+		repo.Create(ctx, &model.Job{
+			ID:          fmt.Sprintf("test_job_batch_%d", i),
+			Type:        "test",
+			Payload:     []byte(`{}`),
+			State:       jobState,
+			Attempt:     1,
+			MaxAttempts: 3,
+			CreatedAt:   time.Now(),
+		})
+	}
+
+	ids := []string{"test_job_batch_0", "test_job_batch_1", "test_job_batch_2", "test_job_batch_3", "missing"}
+	updated, err := repo.UpdateStateBatch(ctx, ids, state.RUNNING, state.SUCCEEDED, "")
+	if err != nil {
+		t.Fatalf("UpdateStateBatch failed: %v", err)
+	}
+	if len(updated) != 3 {
+		t.Fatalf("Updated %v, want the 3 RUNNING jobs", updated)
+	}
+
+	for i := 0; i < 3; i++ {
+		job, _ := repo.GetByID(ctx, fmt.Sprintf("test_job_batch_%d", i))
+		if job.State != state.SUCCEEDED {
+			t.Errorf("Job %s state = %s, want SUCCEEDED", job.ID, job.State)
+		}
+	}
+
+	// Jobs no longer in the expected prior state are left alone
+	skipped, _ := repo.GetByID(ctx, "test_job_batch_3")
+	if skipped.State != state.CANCELLED {
+		t.Errorf("Job %s state = %s, want CANCELLED", skipped.ID, skipped.State)
+	}
+}
+
+func TestClaimPendingJobs_MaxConcurrentClaims(t *testing.T) {
+	repo := setupTestDB(t)
+	repo.SetMaxConcurrentClaims(1)
+	ctx := context.Background()
+
+	repo.Create(ctx, &model.Job{
+		ID:          "test_job_claim_slot",
+		Type:        "test",
+		Payload:     []byte(`{}`),
+		State:       state.PENDING,
+		Attempt:     1,
+		MaxAttempts: 3,
+		CreatedAt:   time.Now(),
+	})
+
+	// Another claim holds the only slot: this one waits, then gives up
+	if err := repo.claimSlots.Acquire(ctx, 1); err != nil {
+		t.Fatalf("Failed to take the claim slot: %v", err)
+	}
+	waitCtx, cancel := context.WithTimeout(ctx, 50*time.Millisecond)
+	defer cancel()
+	if _, err := repo.ClaimPendingJobs(waitCtx, 1, time.Now()); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Claim while the slot is held: error = %v, want context.DeadlineExceeded", err)
+	}
+
+	// Once the slot frees up the next claim goes through
+	repo.claimSlots.Release(1)
+	claimed, err := repo.ClaimPendingJobs(ctx, 1, time.Now())
+	if err != nil {
+		t.Fatalf("ClaimPendingJobs failed: %v", err)
+	}
+	if len(claimed) != 1 {
+		t.Errorf("Claimed %d jobs after the slot was released, want 1", len(claimed))
+	}
+}
+
+func TestClaimPendingJobs_StableOrderOnTies(t *testing.T) {
+	repo := setupTestDB(t)
+	ctx := context.Background()
+
+	// Created in a tight loop sharing one timestamp, inserted in reverse ID order
+	now := time.Now()
+	for i := 9; i >= 0; i-- {
+		repo.Create(ctx, &model.Job{
+			ID:          fmt.Sprintf("test_job_tie_%02d", i),
+			Type:        "test",
+			Payload:     []byte(`{}`),
+			State:       state.PENDING,
+			Attempt:     1,
+			MaxAttempts: 3,
+			CreatedAt:   now,
+		})
+	}
+
+	claimed, err := repo.ClaimPendingJobs(ctx, 10, now)
+	if err != nil {
+		t.Fatalf("ClaimPendingJobs failed: %v", err)
+	}
+	if len(claimed) != 10 {
+		t.Fatalf("Claimed %d jobs, want 10", len(claimed))
+	}
+	for i, job := range claimed {
+		if want := fmt.Sprintf("test_job_tie_%02d", i); job.ID != want {
+			t.Errorf("claimed[%d] = %s, want %s", i, job.ID, want)
+		}
+	}
+}
+
+func TestListByResultKey(t *testing.T) {
+	repo := setupTestDB(t)
+	ctx := context.Background()
+
+	results := map[string]string{
+		"test_job_result_ok":      `{"status": "ok"}`,
+		"test_job_result_partial": `{"status": "partial"}`,
+	}
+	for id, result := range results {
+		repo.Create(ctx, &model.Job{
+			ID:          id,
+			Type:        "test",
+			Payload:     []byte(`{}`),
+			State:       state.SUCCEEDED,
+			Attempt:     1,
+			MaxAttempts: 3,
+			CreatedAt:   time.Now(),
+			Result:      []byte(result),
+		})
+	}
+
+	jobs, err := repo.ListByResultKey(ctx, "status", "ok", 10)
+	if err != nil {
+		t.Fatalf("ListByResultKey failed: %v", err)
+	}
+	if len(jobs) != 1 || jobs[0].ID != "test_job_result_ok" {
+		t.Errorf("Matched %v, want only test_job_result_ok", jobs)
+	}
+}
+
+func TestCreate_CancelledContext(t *testing.T) {
+	repo := setupTestDB(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	err := repo.Create(ctx, &model.Job{
+		ID:          "test_job_cancelled",
+		Type:        "test",
+		Payload:     []byte(`{}`),
+		State:       state.PENDING,
+		Attempt:     1,
+		MaxAttempts: 3,
+		CreatedAt:   time.Now(),
+	})
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("Create error = %v, want context.Canceled", err)
+	}
+
+	if job, _ := repo.GetByID(context.Background(), "test_job_cancelled"); job != nil {
+		t.Error("Expected no row for a cancelled create")
+	}
+}
+
+func TestAcquireTimeout_PoolExhausted(t *testing.T) {
+	cfg := DBConfig{
+		Host:           "localhost",
+		Port:           5434,
+		User:           "orchestrix",
+		Password:       "orchestrix_dev_password",
+		Database:       "orchestrix_dev",
+		SSLMode:        "disable",
+		MaxConnections: 1,
+		MinConnections: 1,
+		AcquireTimeout: 100 * time.Millisecond,
+	}
+
+	pool, err := NewConnectionPool(context.Background(), cfg)
+	if err != nil {
+		t.Fatalf("Failed to create connection pool: %v", err)
+	}
+	defer pool.Close()
+
+	repo := NewPostgresJobRepository(pool)
+	repo.SetAcquireTimeout(cfg.AcquireTimeout)
+	ctx := context.Background()
+
+	// A slow query holds the only connection
+	started := make(chan struct{})
+	done := make(chan error, 1)
+	go func() {
+		conn, err := pool.Acquire(ctx)
+		if err != nil {
+			close(started)
+			done <- err
+			return
+		}
+		defer conn.Release()
+		close(started)
+		_, err = conn.Exec(ctx, "SELECT pg_sleep(1)")
+		done <- err
+	}()
+	<-started
+
+	// A second query fails fast instead of waiting out the first
+	start := time.Now()
+	_, err = repo.CountByState(ctx, state.PENDING)
+	if !errors.Is(err, ErrPoolExhausted) {
+		t.Fatalf("CountByState error = %v, want ErrPoolExhausted", err)
+	}
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Errorf("CountByState took %v, want about the 100ms acquire timeout", elapsed)
+	}
+
+	if err := <-done; err != nil {
+		t.Fatalf("Slow query failed: %v", err)
+	}
+
+	// Once the connection is free, queries work again
+	if _, err := repo.CountByState(ctx, state.PENDING); err != nil {
+		t.Errorf("CountByState after release failed: %v", err)
+	}
+}
+
+func TestClaimPendingJobs_RetriesFirst(t *testing.T) {
+	repo := setupTestDB(t)
+	ctx := context.Background()
+
+	now := time.Now()
+	backdated := now.Add(-time.Minute)
+
+	// An older new job, then a due retry created after it
+	repo.Create(ctx, &model.Job{
+		ID:          "test_job_policy_new",
+		Type:        "test",
+		Payload:     []byte(`{}`),
+		State:       state.PENDING,
+		Attempt:     1,
+		MaxAttempts: 3,
+		CreatedAt:   now.Add(-time.Hour),
+	})
+	repo.Create(ctx, &model.Job{
+		ID:          "test_job_policy_retry",
+		Type:        "test",
+		Payload:     []byte(`{}`),
+		State:       state.RETRYING,
+		Attempt:     2,
+		MaxAttempts: 3,
+		CreatedAt:   now.Add(-time.Minute),
+		NextRetryAt: &backdated,
+	})
+
+	// FIFO by default: the older new job goes first
+	peeked, _ := repo.PeekClaimable(ctx, 1, now)
+	if len(peeked) != 1 || peeked[0].ID != "test_job_policy_new" {
+		t.Fatalf("FIFO peeked %v, want test_job_policy_new", peeked)
+	}
+
+	repo.SetClaimPolicy(ClaimRetriesFirst)
+	claimed, err := repo.ClaimPendingJobs(ctx, 1, now)
+	if err != nil {
+		t.Fatalf("ClaimPendingJobs failed: %v", err)
+	}
+	if len(claimed) != 1 || claimed[0].ID != "test_job_policy_retry" {
+		t.Errorf("Claimed %v, want the due retry first", claimed)
+	}
+}
+
+func TestClaimPendingJobs_PriorityAging(t *testing.T) {
+	repo := setupTestDB(t)
+	repo.SetPriorityAging(10 * time.Minute)
+	ctx := context.Background()
+
+	created := time.Now().Add(-2 * time.Hour)
+	newJob := func(id string, priority int, createdAt time.Time) {
+		repo.Create(ctx, &model.Job{
+			ID:          id,
+			Type:        "test",
+			Payload:     []byte(`{}`),
+			State:       state.PENDING,
+			Attempt:     1,
+			MaxAttempts: 3,
+			Priority:    priority,
+			CreatedAt:   createdAt,
+		})
+	}
+	claimNext := func(now time.Time) string {
+		claimed, err := repo.ClaimPendingJobs(ctx, 1, now)
+		if err != nil {
+			t.Fatalf("ClaimPendingJobs failed: %v", err)
+		}
+		if len(claimed) != 1 {
+			t.Fatalf("Claimed %d jobs, want 1", len(claimed))
+		}
+		return claimed[0].ID
+	}
+
+	newJob("test_job_aging_old", 1, created)
+
+	// After 20 minutes the old job ranks 1+2, below a fresh priority 5 job
+	newJob("test_job_aging_mid_1", 5, created.Add(19*time.Minute))
+	if got := claimNext(created.Add(20 * time.Minute)); got != "test_job_aging_mid_1" {
+		t.Errorf("Claimed %s after 20m, want test_job_aging_mid_1", got)
+	}
+
+	// After an hour it ranks 1+6 and overtakes the next fresh priority 5 job
+	newJob("test_job_aging_mid_2", 5, created.Add(59*time.Minute))
+	if got := claimNext(created.Add(time.Hour)); got != "test_job_aging_old" {
+		t.Errorf("Claimed %s after 1h, want test_job_aging_old", got)
+	}
+}
+
+func TestOldestPendingAge(t *testing.T) {
+	repo := setupTestDB(t)
+	ctx := context.Background()
+	now := time.Now()
+
+	age, err := repo.OldestPendingAge(ctx, now)
+	if err != nil {
+		t.Fatalf("OldestPendingAge failed: %v", err)
+	}
+	if age != 0 {
+		t.Errorf("Age with no jobs = %v, want 0", age)
+	}
+
+	future := now.Add(time.Hour)
+	for id, job := range map[string]struct {
+		state     state.State
+		createdAt time.Time
+		runAt     *time.Time
+	}{
+		"test_job_oldest_pending": {state.PENDING, now.Add(-time.Hour), nil},
+		"test_job_newer_pending":  {state.PENDING, now.Add(-time.Minute), nil},
+		"test_job_older_running":  {state.RUNNING, now.Add(-2 * time.Hour), nil},
+		"test_job_not_yet_due":    {state.PENDING, now.Add(-3 * time.Hour), &future},
+	} {
+		repo.Create(ctx, &model.Job{
+			ID:          id,
+			Type:        "test",
+			Payload:     []byte(`{}`),
+			State:       job.state,
+			Attempt:     1,
+			MaxAttempts: 3,
+			CreatedAt:   job.createdAt,
+			RunAt:       job.runAt,
+		})
+	}
+
+	age, err = repo.OldestPendingAge(ctx, now)
+	if err != nil {
+		t.Fatalf("OldestPendingAge failed: %v", err)
+	}
+	if age < time.Hour-time.Second || age > time.Hour+time.Second {
+		t.Errorf("Age = %v, want about 1h (running and not-yet-due jobs ignored)", age)
+	}
+}
+
+func TestListByState_SkipsRowWithNullType(t *testing.T) {
+	repo := setupTestDB(t)
+	ctx := context.Background()
+
+	// Simulate a bad migration or manual edit
+	if _, err := repo.base.Exec(ctx, `ALTER TABLE jobs ALTER COLUMN type DROP NOT NULL`); err != nil {
+		t.Fatalf("Failed to drop NOT NULL: %v", err)
+	}
+	t.Cleanup(func() {
+		repo.base.Exec(ctx, `DELETE FROM jobs WHERE type IS NULL`)
+		repo.base.Exec(ctx, `ALTER TABLE jobs ALTER COLUMN type SET NOT NULL`)
+	})
+
+	if _, err := repo.base.Exec(ctx,
+		`INSERT INTO jobs (id, type, state) VALUES ('test_job_null_type', NULL, 'PENDING')`); err != nil {
+		t.Fatalf("Failed to insert malformed row: %v", err)
+	}
+	good := &model.Job{
+		ID:          "test_job_good",
+		Type:        "test",
+		Payload:     []byte(`{}`),
+		State:       state.PENDING,
+		Attempt:     1,
+		MaxAttempts: 3,
+		CreatedAt:   time.Now(),
+	}
+	repo.Create(ctx, good)
+
+	// The list skips the bad row instead of failing
+	jobs, err := repo.ListByState(ctx, state.PENDING, 10)
+	if err != nil {
+		t.Fatalf("ListByState failed: %v", err)
+	}
+	if len(jobs) != 1 || jobs[0].ID != good.ID {
+		t.Errorf("Listed %d jobs, want only %s", len(jobs), good.ID)
+	}
+
+	// A direct lookup reports the row by ID
+	_, err = repo.GetByID(ctx, "test_job_null_type")
+	if !errors.Is(err, ErrMalformedJob) || !strings.Contains(err.Error(), "test_job_null_type") {
+		t.Errorf("GetByID error = %v, want ErrMalformedJob naming the job", err)
+	}
+}
+
+// fakeRow is a pgx.Row that scans fixed values, nil meaning SQL NULL.
+type fakeRow []any
+
+func (r fakeRow) Scan(dest ...any) error {
+	for i, value := range r {
+		if value == nil {
+			continue
+		}
+		target := reflect.ValueOf(dest[i]).Elem()
+		v := reflect.ValueOf(value)
+		if target.Kind() == reflect.Pointer {
+			ptr := reflect.New(target.Type().Elem())
+			ptr.Elem().Set(v.Convert(target.Type().Elem()))
+			target.Set(ptr)
+		} else {
+			target.Set(v.Convert(target.Type()))
+		}
+	}
+	return nil
+}
+
+func TestScanJob_NullRequiredColumn(t *testing.T) {
+	now := time.Now()
+	row := func(jobState any) fakeRow {
+		// jobColumns order; optional columns left NULL
+		return fakeRow{"job_1", "test", []byte(`{}`), jobState, 1, 3, nil, now,
+			nil, nil, nil, nil, 0, nil, nil, nil, nil, nil, nil, 0}
+	}
+
+	job, err := scanJob(row("PENDING"))
+	if err != nil {
+		t.Fatalf("scanJob failed: %v", err)
+	}
+	if job.State != state.PENDING || job.Type != "test" || job.MaxAttempts != 3 {
+		t.Errorf("Job = %+v, want the scanned fields", job)
+	}
+
+	_, err = scanJob(row(nil))
+	if !errors.Is(err, ErrMalformedJob) {
+		t.Fatalf("Error = %v, want ErrMalformedJob", err)
+	}
+	if !strings.Contains(err.Error(), "job_1") || !strings.Contains(err.Error(), "state") {
+		t.Errorf("Error %q should name the job and the NULL column", err)
+	}
+	if !skipMalformed(err) {
+		t.Error("skipMalformed = false, want list queries to skip the row")
+	}
+}
+
+func TestTruncateAll(t *testing.T) {
+	repo := setupTestDB(t)
+	ctx := context.Background()
+
+	for i := 0; i < 3; i++ {
+		repo.Create(ctx, &model.Job{
+			ID:          fmt.Sprintf("test_job_truncate_%d", i),
+			Type:        "test",
+			Payload:     []byte(`{}`),
+			State:       state.PENDING,
+			Attempt:     1,
+			MaxAttempts: 3,
+			CreatedAt:   time.Now(),
+		})
+	}
+
+	repo.SetAllowDestructive(false)
+	if err := repo.TruncateAll(ctx); !errors.Is(err, ErrDestructiveDisabled) {
+		t.Fatalf("TruncateAll without permission = %v, want ErrDestructiveDisabled", err)
+	}
+
+	repo.SetAllowDestructive(true)
+	if err := repo.TruncateAll(ctx); err != nil {
+		t.Fatalf("TruncateAll failed: %v", err)
+	}
+	if n, _ := repo.CountByState(ctx, state.PENDING); n != 0 {
+		t.Errorf("%d jobs left after TruncateAll, want 0", n)
+	}
+}
+
+func TestGetByIdempotencyKey(t *testing.T) {
+	repo := setupTestDB(t)
+	ctx := context.Background()
+
+	if job, err := repo.GetByIdempotencyKey(ctx, "order-42", "email"); err != nil || job != nil {
+		t.Fatalf("Unused key = %v, %v; want nil, nil", job, err)
+	}
+
+	key := "order-42"
+	newJob := func(id string) *model.Job {
+		return &model.Job{
+			ID:             id,
+			Type:           "email",
+			Payload:        []byte(`{}`),
+			State:          state.PENDING,
+			Attempt:        1,
+			MaxAttempts:    3,
+			CreatedAt:      time.Now(),
+			IdempotencyKey: &key,
+		}
+	}
+	if err := repo.Create(ctx, newJob("test_job_idempotent")); err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+
+	job, err := repo.GetByIdempotencyKey(ctx, "order-42", "email")
+	if err != nil || job == nil || job.ID != "test_job_idempotent" {
+		t.Fatalf("Used key = %v, %v; want test_job_idempotent", job, err)
+	}
+	if job.IdempotencyKey == nil || *job.IdempotencyKey != key {
+		t.Errorf("IdempotencyKey = %v, want %q", job.IdempotencyKey, key)
+	}
+	if job, _ := repo.GetByIdempotencyKey(ctx, "order-42", "sms"); job != nil {
+		t.Errorf("Key matched job %s of another type", job.ID)
+	}
+
+	// The unique index rejects a second job with the same key and type
+	if err := repo.Create(ctx, newJob("test_job_idempotent_2")); !errors.Is(err, ErrDuplicateJob) {
+		t.Errorf("Create with a used key = %v, want ErrDuplicateJob", err)
+	}
+}
+
+func TestUpdateMaxAttempts(t *testing.T) {
+	repo := setupTestDB(t)
+	ctx := context.Background()
+
+	repo.Create(ctx, &model.Job{
+		ID:          "test_job_max_attempts",
+		Type:        "test",
+		Payload:     []byte(`{}`),
+		State:       state.RETRYING,
+		Attempt:     2,
+		MaxAttempts: 2,
+		CreatedAt:   time.Now(),
+	})
+
+	if ok, err := repo.UpdateMaxAttempts(ctx, "test_job_max_attempts", 1); err != nil || ok {
+		t.Errorf("Below current attempt = %v, %v; want false, nil", ok, err)
+	}
+	if ok, err := repo.UpdateMaxAttempts(ctx, "test_job_max_attempts", 5); err != nil || !ok {
+		t.Fatalf("UpdateMaxAttempts = %v, %v; want true, nil", ok, err)
+	}
+
+	job, _ := repo.GetByID(ctx, "test_job_max_attempts")
+	if job.MaxAttempts != 5 || job.State != state.RETRYING {
+		t.Errorf("Job = %s with max attempts %d, want RETRYING with 5", job.State, job.MaxAttempts)
+	}
+
+	repo.UpdateState(ctx, "test_job_max_attempts", state.CANCELLED)
+	if ok, _ := repo.UpdateMaxAttempts(ctx, "test_job_max_attempts", 9); ok {
+		t.Error("Updated a finished job")
+	}
+}
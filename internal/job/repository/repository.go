@@ -2,11 +2,33 @@ package repository
 
 import (
 	"context"
+	"errors"
+	"time"
 
 	"github.com/dipak0000812/orchestrix/internal/job/model"
 	"github.com/dipak0000812/orchestrix/internal/job/state"
 )
 
+// ErrDuplicateJob is returned by Create when a job with the same ID already exists.
+var ErrDuplicateJob = errors.New("job already exists")
+
+// ErrMalformedJob is returned when a stored job row is missing a required
+// field, e.g. a NULL type left by a manual edit. List queries skip such
+// rows instead of failing.
+var ErrMalformedJob = errors.New("malformed job row")
+
+// ErrDestructiveDisabled is returned by TruncateAll unless destructive
+// operations were explicitly allowed on the repository.
+var ErrDestructiveDisabled = errors.New("destructive operations are disabled")
+
+// SortOrder is the direction a list is ordered by creation time.
+type SortOrder string
+
+const (
+	SortAsc  SortOrder = "asc"  // oldest first
+	SortDesc SortOrder = "desc" // newest first
+)
+
 // JobRepository defines the contract for job data persistence.
 // Any storage backend (PostgreSQL, MySQL, MongoDB, in-memory) must implement this interface.
 //
@@ -16,27 +38,116 @@ import (
 // - Clarity: Explicitly defines what operations are available
 type JobRepository interface {
 	// Create inserts a new job into the repository.
-	// Returns an error if the job ID already exists or if validation fails.
+	// Returns ErrDuplicateJob if the job ID already exists, or if a job
+	// of the same type already has its idempotency key.
 	Create(ctx context.Context, job *model.Job) error
 
 	// GetByID retrieves a job by its unique identifier.
 	// Returns nil if the job doesn't exist.
 	GetByID(ctx context.Context, id string) (*model.Job, error)
 
+	// GetByIDForUpdate is GetByID, locking the row until the enclosing
+	// WithTx transaction ends so concurrent writers wait. Outside a
+	// transaction the lock is released as soon as the read returns.
+	GetByIDForUpdate(ctx context.Context, id string) (*model.Job, error)
+
+	// GetByIdempotencyKey retrieves the job of jobType created with the
+	// idempotency key. Returns nil if there is none.
+	GetByIdempotencyKey(ctx context.Context, key, jobType string) (*model.Job, error)
+
+	// GetStates returns the state of each listed job, keyed by ID.
+	// Only the id and state columns are read. Unknown IDs are left out.
+	GetStates(ctx context.Context, ids []string) (map[string]state.State, error)
+
+	// Exists reports whether a job with the given ID exists,
+	// without fetching the row.
+	Exists(ctx context.Context, id string) (bool, error)
+
 	// UpdateState changes the state of a job.
 	// This is the most frequent operation (every state transition).
 	UpdateState(ctx context.Context, id string, newState state.State) error
 
+	// UpdateStateBatch moves every listed job that is still in from to to,
+	// in one round trip, recording reason as the transition reason (none
+	// if empty). Moving jobs back to PENDING or RETRYING clears their
+	// scheduled_at and started_at, as they wait to be claimed afresh.
+	// Jobs that moved on or don't exist are skipped.
+	// Returns the IDs that were transitioned.
+	UpdateStateBatch(ctx context.Context, ids []string, from, to state.State, reason string) ([]string, error)
+
 	// ListByState returns all jobs in a specific state, ordered by creation time.
 	// Used by scheduler to find PENDING jobs, workers to find SCHEDULED jobs, etc.
 	// Limit controls how many jobs to return (pagination).
 	ListByState(ctx context.Context, state state.State, limit int) ([]*model.Job, error)
 
+	// ListByStateOrdered is ListByState in the given order. SortDesc
+	// returns the newest jobs first, for display; anything else is SortAsc.
+	ListByStateOrdered(ctx context.Context, state state.State, limit int, order SortOrder) ([]*model.Job, error)
+
+	// ListByResultKey returns SUCCEEDED jobs whose JSON result has key set
+	// to the string value, ordered by creation time.
+	ListByResultKey(ctx context.Context, key, value string, limit int) ([]*model.Job, error)
+
+	// CountByState returns how many jobs are in any of the given states.
+	CountByState(ctx context.Context, states ...state.State) (int, error)
+
+	// CountByTypeAndState returns how many jobs of each type are in each
+	// state. States a type has no jobs in are left out.
+	CountByTypeAndState(ctx context.Context) (map[string]map[state.State]int, error)
+
+	// OldestPendingAge returns how long the longest-waiting due PENDING job
+	// has been claimable at now: since its run_at if it had one, otherwise
+	// since creation. Returns 0 if no PENDING job is due.
+	OldestPendingAge(ctx context.Context, now time.Time) (time.Duration, error)
+
 	// Update modifies an existing job's fields (except ID).
 	// Used for updating attempt count, error messages, timestamps, etc.
 	Update(ctx context.Context, job *model.Job) error
 
+	// UpdateProgress writes only the fields that change as a job runs:
+	// state, attempt, last error, lifecycle timestamps, transition reason,
+	// and priority. Immutable fields like type, payload, and created_at
+	// are never written, so it can't clobber them.
+	UpdateProgress(ctx context.Context, job *model.Job) error
+
+	// UpdateMaxAttempts sets max_attempts and nothing else, but only while
+	// the job is non-terminal and its attempt is at most maxAttempts.
+	// Returns false if the job doesn't exist or no longer qualifies.
+	UpdateMaxAttempts(ctx context.Context, id string, maxAttempts int) (bool, error)
+
 	// Delete removes a job from the repository (soft delete in production).
 	// Mainly for testing and cleanup. Production might use soft deletes instead.
 	Delete(ctx context.Context, id string) error
+
+	// RecordAttemptError appends an entry to a job's per-attempt error history.
+	RecordAttemptError(ctx context.Context, attemptErr *model.AttemptError) error
+
+	// ListAttemptErrors returns a job's error history, oldest first.
+	// Returns an empty slice if the job never errored.
+	ListAttemptErrors(ctx context.Context, jobID string) ([]*model.AttemptError, error)
+
+	// FindStaleScheduled returns SCHEDULED jobs whose scheduled_at is before cutoff.
+	// Used by the reaper to recover jobs that were claimed but never started running.
+	FindStaleScheduled(ctx context.Context, cutoff time.Time) ([]*model.Job, error)
+
+	// PeekClaimable returns the next jobs the scheduler would claim at now,
+	// in claim order, without locking or changing them. Jobs of the
+	// skipTypes are left out, as ClaimPendingJobs leaves them unclaimed.
+	PeekClaimable(ctx context.Context, limit int, now time.Time, skipTypes ...string) ([]*model.Job, error)
+
+	// DistinctTypes returns the sorted set of job types that currently exist.
+	DistinctTypes(ctx context.Context) ([]string, error)
+
+	// TopErrors groups FAILED jobs by their exact last error and returns
+	// the most common messages first. Jobs without an error are skipped.
+	TopErrors(ctx context.Context, limit int) ([]model.ErrorCount, error)
+
+	// TruncateAll deletes every job and its error history. It fails with
+	// ErrDestructiveDisabled unless the repository was built with
+	// SetAllowDestructive(true), which only tests should do.
+	TruncateAll(ctx context.Context) error
+
+	// WithTx runs fn atomically. fn must use the tx repository it is given;
+	// if fn returns an error, every change made through tx is rolled back.
+	WithTx(ctx context.Context, fn func(tx JobRepository) error) error
 }
@@ -2,6 +2,7 @@ package service
 
 import (
 	"crypto/rand"
+	"strings"
 	"time"
 
 	"github.com/oklog/ulid/v2"
@@ -42,3 +43,37 @@ func (g *ULIDGenerator) Generate() string {
 	// Return as string
 	return id.String()
 }
+
+// SetTypePrefixedIDs makes new job IDs start with their type, e.g.
+// "send_email_01HQX7Z9PMRGWKT8HHFQNR3XYZ", so the type is visible in logs.
+// Off by default. The generated part is unchanged, so IDs stay unique;
+// only characters that are safe in URLs are kept from the type.
+func (s *JobService) SetTypePrefixedIDs(enabled bool) {
+	s.typePrefixedIDs = enabled
+}
+
+// newID generates the ID for a new job of jobType.
+func (s *JobService) newID(jobType string) string {
+	id := s.idGenerator.Generate()
+	if !s.typePrefixedIDs {
+		return id
+	}
+
+	prefix := idPrefix(jobType)
+	if prefix == "" {
+		return id
+	}
+	return prefix + "_" + id
+}
+
+// idPrefix reduces a job type to the characters allowed in an ID prefix
+// (letters, digits, '_' and '-'), so prefixed IDs stay usable in URLs.
+func idPrefix(jobType string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '_', r == '-':
+			return r
+		}
+		return -1
+	}, jobType)
+}
@@ -0,0 +1,52 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/dipak0000812/orchestrix/internal/blobstore"
+	"github.com/dipak0000812/orchestrix/internal/job/model"
+)
+
+// SetBlobStore stores payloads larger than threshold bytes in store
+// instead of the database. By default every payload stays in the database.
+func (s *JobService) SetBlobStore(store blobstore.BlobStore, threshold int) {
+	s.blobs = store
+	s.blobThreshold = threshold
+}
+
+// offloadPayload moves an oversized payload to the blob store, keyed by
+// job ID, leaving only the reference on the job.
+func (s *JobService) offloadPayload(ctx context.Context, job *model.Job) error {
+	if s.blobs == nil || len(job.Payload) <= s.blobThreshold {
+		return nil
+	}
+
+	key := job.ID
+	if err := s.blobs.Put(ctx, key, job.Payload); err != nil {
+		return fmt.Errorf("failed to store payload: %w", err)
+	}
+
+	job.Payload = nil
+	job.PayloadRef = &key
+	return nil
+}
+
+// LoadPayload returns a job's payload, fetching it from the blob store
+// if it was stored externally.
+func (s *JobService) LoadPayload(ctx context.Context, job *model.Job) ([]byte, error) {
+	if job.PayloadRef == nil {
+		return job.Payload, nil
+	}
+
+	if s.blobs == nil {
+		return nil, fmt.Errorf("job %s has an external payload but no blob store is configured", job.ID)
+	}
+
+	payload, err := s.blobs.Get(ctx, *job.PayloadRef)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load payload: %w", err)
+	}
+
+	return payload, nil
+}
@@ -0,0 +1,140 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/dipak0000812/orchestrix/internal/job/model"
+	"github.com/dipak0000812/orchestrix/internal/job/state"
+)
+
+// recoveryBatchSize is how many orphaned jobs are fetched per query during recovery.
+const recoveryBatchSize = 100
+
+// ErrInterrupted is recorded as the failure of a RUNNING job whose process
+// went away before it finished.
+var ErrInterrupted = errors.New("interrupted by process restart")
+
+// RecoveryResult counts what RecoverInterruptedJobs did.
+type RecoveryResult struct {
+	Requeued int // SCHEDULED jobs put back up for claiming
+	Retried  int // RUNNING jobs moved to RETRYING
+	Failed   int // RUNNING jobs that had no attempts left
+}
+
+// RecoverInterruptedJobs makes jobs orphaned by a previous process claimable
+// again. The scheduler only claims PENDING/RETRYING, so without this a job
+// that was SCHEDULED or RUNNING when the process died is never picked up.
+//
+// SCHEDULED jobs never started, so they go back to PENDING (or RETRYING if
+// they were already on a retry) without using an attempt. RUNNING jobs did
+// use their attempt and are failed with ErrInterrupted, which retries them
+// or fails them permanently just like any other failure.
+//
+// Call it once at startup, before the scheduler and workers start. It
+// assumes this is the only instance: another live instance's in-flight jobs
+// would be recovered out from under it.
+func (s *JobService) RecoverInterruptedJobs(ctx context.Context) (RecoveryResult, error) {
+	var result RecoveryResult
+
+	// Each pass moves every job it sees out of the state, so re-listing
+	// until empty visits them all
+	for {
+		jobs, err := s.repo.ListByState(ctx, state.SCHEDULED, recoveryBatchSize)
+		if err != nil {
+			return result, fmt.Errorf("failed to list scheduled jobs: %w", err)
+		}
+		if len(jobs) == 0 {
+			break
+		}
+		requeued, err := s.requeueScheduled(ctx, jobs)
+		if err != nil {
+			return result, err
+		}
+		result.Requeued += requeued
+	}
+
+	for {
+		jobs, err := s.repo.ListByState(ctx, state.RUNNING, recoveryBatchSize)
+		if err != nil {
+			return result, fmt.Errorf("failed to list running jobs: %w", err)
+		}
+		if len(jobs) == 0 {
+			break
+		}
+		for _, job := range jobs {
+			exhausted, err := s.HandleFailure(ctx, job.ID, ErrInterrupted)
+			if err != nil {
+				return result, fmt.Errorf("failed to recover job %s: %w", job.ID, err)
+			}
+			if exhausted {
+				result.Failed++
+			} else {
+				result.Retried++
+			}
+		}
+	}
+
+	return result, nil
+}
+
+// requeueScheduled puts claimed-but-never-started jobs back up for claiming.
+// They never ran, so their attempt counts are left alone. Only jobs still
+// SCHEDULED are moved, so one that started meanwhile isn't run twice.
+// Returns how many were requeued.
+func (s *JobService) requeueScheduled(ctx context.Context, jobs []*model.Job) (int, error) {
+	byState := make(map[state.State][]string)
+	for _, job := range jobs {
+		to := unclaimedState(job)
+		byState[to] = append(byState[to], job.ID)
+	}
+
+	requeued := 0
+	for to, ids := range byState {
+		updated, err := s.repo.UpdateStateBatch(ctx, ids, state.SCHEDULED, to, "requeued after process restart")
+		if err != nil {
+			return requeued, fmt.Errorf("failed to requeue scheduled jobs: %w", err)
+		}
+		requeued += len(updated)
+	}
+	return requeued, nil
+}
+
+// ReleaseInterrupted puts a RUNNING job whose run this process cut short,
+// e.g. on shutdown, back up for claiming. The job itself didn't fail, so
+// its attempt count is left alone. Jobs that already left RUNNING, e.g.
+// because they were cancelled meanwhile, are left alone too.
+func (s *JobService) ReleaseInterrupted(ctx context.Context, id string) error {
+	job, err := s.GetJob(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	// Conditional on RUNNING, so a cancel that lands meanwhile wins
+	if _, err := s.repo.UpdateStateBatch(ctx, []string{id}, state.RUNNING, unclaimedState(job), "requeued after shutdown"); err != nil {
+		return fmt.Errorf("failed to requeue job: %w", err)
+	}
+	return nil
+}
+
+// ReleaseScheduled puts a SCHEDULED job that this process won't run
+// back up for claiming, recording reason. It never ran, so its attempt
+// count is left alone. A job that already left SCHEDULED is left alone
+// too. Reports whether the job was released.
+func (s *JobService) ReleaseScheduled(ctx context.Context, job *model.Job, reason string) (bool, error) {
+	released, err := s.repo.UpdateStateBatch(ctx, []string{job.ID}, state.SCHEDULED, unclaimedState(job), reason)
+	if err != nil {
+		return false, fmt.Errorf("failed to release job: %w", err)
+	}
+	return len(released) > 0, nil
+}
+
+// unclaimedState is the state the scheduler claims job from:
+// first attempts from PENDING, retries from RETRYING.
+func unclaimedState(job *model.Job) state.State {
+	if job.Attempt > 1 {
+		return state.RETRYING
+	}
+	return state.PENDING
+}
@@ -0,0 +1,79 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/robfig/cron/v3"
+
+	"github.com/dipak0000812/orchestrix/internal/job/model"
+	"github.com/dipak0000812/orchestrix/internal/job/repository"
+	"github.com/dipak0000812/orchestrix/internal/job/state"
+)
+
+// parseSchedule parses a standard 5-field cron expression
+// ("minute hour day-of-month month day-of-week") or a descriptor like "@daily".
+func parseSchedule(spec string) (cron.Schedule, error) {
+	schedule, err := cron.ParseStandard(spec)
+	if err != nil {
+		return nil, fmt.Errorf("invalid schedule %q: %w", spec, err)
+	}
+	return schedule, nil
+}
+
+// saveProgress writes a job's lifecycle fields. When a recurring job
+// reaches SUCCEEDED or FAILED, its next occurrence is created in the same
+// transaction, so a series can't be lost or forked by a partial write.
+func (s *JobService) saveProgress(ctx context.Context, repo repository.JobRepository, job *model.Job) error {
+	if !job.IsRecurring() || (job.State != state.SUCCEEDED && job.State != state.FAILED) {
+		return repo.UpdateProgress(ctx, job)
+	}
+
+	return repo.WithTx(ctx, func(tx repository.JobRepository) error {
+		if err := tx.UpdateProgress(ctx, job); err != nil {
+			return err
+		}
+		return s.enqueueNextOccurrence(ctx, tx, job)
+	})
+}
+
+// enqueueNextOccurrence creates the next PENDING instance of a recurring job,
+// due at the schedule's next tick after now. Cancelled jobs don't get here,
+// so cancelling an occurrence ends the series.
+func (s *JobService) enqueueNextOccurrence(ctx context.Context, repo repository.JobRepository, job *model.Job) error {
+	schedule, err := parseSchedule(*job.Schedule)
+	if err != nil {
+		return err
+	}
+
+	// Every occurrence points at the first job of the series
+	parentID := job.ID
+	if job.ParentID != nil {
+		parentID = *job.ParentID
+	}
+
+	now := s.clock.Now()
+	runAt := schedule.Next(now)
+	spec := *job.Schedule
+
+	next := &model.Job{
+		ID:          s.newID(job.Type),
+		Type:        job.Type,
+		Payload:     job.Payload,
+		PayloadRef:  job.PayloadRef,
+		State:       state.PENDING,
+		Attempt:     1,
+		MaxAttempts: job.MaxAttempts,
+		CreatedAt:   now,
+		Priority:    job.Priority,
+		RunAt:       &runAt,
+		Schedule:    &spec,
+		ParentID:    &parentID,
+	}
+
+	if err := repo.Create(ctx, next); err != nil {
+		return fmt.Errorf("failed to enqueue next occurrence: %w", err)
+	}
+
+	return nil
+}
@@ -11,6 +11,21 @@ type RetryConfig struct {
 	BaseDelay time.Duration // Initial delay (e.g., 2s)
 	MaxDelay  time.Duration // Maximum delay (e.g., 5m)
 	MaxJitter time.Duration // Random jitter range
+
+	// PriorityBoost is added to a job's priority on each retry so that
+	// repeatedly failing jobs aren't starved by newer high-priority work.
+	// Zero disables boosting.
+	PriorityBoost int
+	MaxPriority   int // Ceiling for boosted priority
+
+	// MaxAllowedAttempts caps the max_attempts a client may request,
+	// so a broken job can't be pinned retrying forever. Zero disables the cap.
+	MaxAllowedAttempts int
+
+	// MaxElapsed is the total retry window measured from job creation.
+	// Once exceeded, the next failure is permanent even if attempts remain.
+	// Zero disables the window.
+	MaxElapsed time.Duration
 }
 
 // DefaultRetryConfig returns sensible retry defaults.
@@ -19,6 +34,8 @@ func DefaultRetryConfig() RetryConfig {
 		BaseDelay: 10 * time.Millisecond,
 		MaxDelay:  50 * time.Millisecond,
 		MaxJitter: 0,
+
+		MaxAllowedAttempts: 10,
 	}
 }
 
@@ -52,3 +69,24 @@ func (c RetryConfig) CalculateBackoff(attempt int) time.Duration {
 
 	return time.Duration(delay) + jitter
 }
+
+// BoostPriority returns the priority for a job's next retry.
+// The boost never pushes priority past MaxPriority, and never lowers
+// a priority that already starts above the ceiling.
+func (c RetryConfig) BoostPriority(priority int) int {
+	if c.PriorityBoost <= 0 || priority >= c.MaxPriority {
+		return priority
+	}
+
+	boosted := priority + c.PriorityBoost
+	if boosted > c.MaxPriority {
+		boosted = c.MaxPriority
+	}
+	return boosted
+}
+
+// RetryWindowExceeded reports whether a job created at createdAt has
+// used up its MaxElapsed retry window as of now.
+func (c RetryConfig) RetryWindowExceeded(createdAt, now time.Time) bool {
+	return c.MaxElapsed > 0 && now.Sub(createdAt) > c.MaxElapsed
+}
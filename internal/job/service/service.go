@@ -3,9 +3,13 @@ package service
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"sync"
 	"time"
 
+	"github.com/dipak0000812/orchestrix/internal/blobstore"
+	"github.com/dipak0000812/orchestrix/internal/clock"
 	"github.com/dipak0000812/orchestrix/internal/job/model"
 	"github.com/dipak0000812/orchestrix/internal/job/repository"
 	"github.com/dipak0000812/orchestrix/internal/job/state"
@@ -18,6 +22,23 @@ type JobService struct {
 	stateMachine *state.StateMachine
 	idGenerator  IDGenerator
 	retryConfig  RetryConfig
+	clock        clock.Clock
+
+	// Payloads over blobThreshold bytes go to blobs when it's set.
+	blobs         blobstore.BlobStore
+	blobThreshold int
+
+	// maxListLimit caps how many jobs one list call returns.
+	maxListLimit int
+
+	// typePrefixedIDs prepends the job type to generated IDs.
+	typePrefixedIDs bool
+
+	// watchers are WaitForJob callers, by job ID.
+	watchMu  sync.Mutex
+	watchers map[string][]chan struct{}
+
+	closeOnce sync.Once
 }
 
 // NewJobService creates a new job service.
@@ -32,11 +53,192 @@ func NewJobService(
 		stateMachine: stateMachine,
 		idGenerator:  idGenerator,
 		retryConfig:  retryConfig,
+		clock:        clock.Real(),
+		maxListLimit: DefaultMaxListLimit,
+	}
+}
+
+// SetClock replaces the time source used for job timestamps and retry
+// backoff. Intended for tests; the default is the system clock.
+func (s *JobService) SetClock(c clock.Clock) {
+	s.clock = c
+}
+
+// Default and maximum page sizes for list calls.
+const (
+	defaultListLimit    = 10
+	DefaultMaxListLimit = 100
+)
+
+// SetMaxListLimit caps how many jobs a single list call may return.
+// Values <= 0 restore DefaultMaxListLimit.
+func (s *JobService) SetMaxListLimit(n int) {
+	if n <= 0 {
+		n = DefaultMaxListLimit
+	}
+	s.maxListLimit = n
+}
+
+// ListLimit returns the page size a list call will actually use for the
+// requested limit: the default when unset, clamped to the maximum.
+func (s *JobService) ListLimit(requested int) int {
+	if requested <= 0 {
+		return defaultListLimit
 	}
+	return min(requested, s.maxListLimit)
 }
 
+// Close releases resources the service owns, such as background
+// goroutines. It doesn't close the repository, which the caller owns.
+// Safe to call more than once; calls after the first do nothing.
+func (s *JobService) Close() error {
+	s.closeOnce.Do(func() {
+		// Nothing is owned yet; subscriptions and hooks get released here
+	})
+	return nil
+}
+
+// JobOptions holds optional settings for a new job.
+// The zero value gives a job with default settings.
+type JobOptions struct {
+	// Priority orders claimable jobs; higher runs first.
+	Priority int
+
+	// MaxAttempts overrides the default attempt budget when > 0.
+	// Capped by RetryConfig.MaxAllowedAttempts.
+	MaxAttempts int
+
+	// Schedule makes the job recurring: a cron expression such as
+	// "0 3 * * *" or "@daily". The first run waits for the next tick.
+	Schedule string
+
+	// IdempotencyKey makes the create safe to retry: if a job of the same
+	// type was already created with this key, that job is returned instead.
+	IdempotencyKey string
+}
+
+// defaultMaxAttempts is used when JobOptions.MaxAttempts is unset.
+const defaultMaxAttempts = 3
+
 // CreateJob creates a new job with initial state PENDING.
 func (s *JobService) CreateJob(ctx context.Context, jobType string, payload []byte) (*model.Job, error) {
+	return s.CreateJobWithOptions(ctx, jobType, payload, JobOptions{})
+}
+
+// CreateJobWithOptions creates a new PENDING job with the given options.
+// With an idempotency key that was already used for this type, the
+// existing job is returned as is and nothing is created.
+func (s *JobService) CreateJobWithOptions(ctx context.Context, jobType string, payload []byte, opts JobOptions) (*model.Job, error) {
+	job, err := s.newJob(jobType, payload, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	// The caller may have given up (e.g. client disconnected); write nothing
+	if err := ctx.Err(); err != nil {
+		return nil, fmt.Errorf("failed to create job: %w", err)
+	}
+
+	if existing, err := s.existingJob(ctx, s.repo, job); err != nil || existing != nil {
+		return existing, err
+	}
+
+	if err := s.offloadPayload(ctx, job); err != nil {
+		return nil, err
+	}
+
+	// Save to repository
+	if err := s.repo.Create(ctx, job); err != nil {
+		// A concurrent create with the same key won the race
+		if errors.Is(err, repository.ErrDuplicateJob) && job.IdempotencyKey != nil {
+			if existing, lookupErr := s.existingJob(ctx, s.repo, job); lookupErr == nil && existing != nil {
+				return existing, nil
+			}
+		}
+		return nil, fmt.Errorf("failed to create job: %w", err)
+	}
+
+	return job, nil
+}
+
+// existingJob returns the job already created with job's idempotency key,
+// or nil if job has no key or the key is unused.
+func (s *JobService) existingJob(ctx context.Context, repo repository.JobRepository, job *model.Job) (*model.Job, error) {
+	if job.IdempotencyKey == nil {
+		return nil, nil
+	}
+
+	existing, err := repo.GetByIdempotencyKey(ctx, *job.IdempotencyKey, job.Type)
+	if err != nil {
+		return nil, fmt.Errorf("failed to look up idempotency key: %w", err)
+	}
+	return existing, nil
+}
+
+// JobSpec describes one job to create with CreateJobs.
+type JobSpec struct {
+	Type    string
+	Payload []byte
+	Options JobOptions
+}
+
+// CreateJobs creates all of specs in one transaction: if any job is
+// invalid or can't be stored, none are created. Jobs are returned in
+// the order given. A spec whose idempotency key was already used,
+// earlier in the batch or before, gets the existing job.
+func (s *JobService) CreateJobs(ctx context.Context, specs []JobSpec) ([]*model.Job, error) {
+	jobs := make([]*model.Job, len(specs))
+	for i, spec := range specs {
+		job, err := s.newJob(spec.Type, spec.Payload, spec.Options)
+		if err != nil {
+			return nil, fmt.Errorf("job %d: %w", i, err)
+		}
+		jobs[i] = job
+	}
+
+	// The caller may have given up (e.g. client disconnected); write nothing
+	if err := ctx.Err(); err != nil {
+		return nil, fmt.Errorf("failed to create jobs: %w", err)
+	}
+
+	for _, job := range jobs {
+		if err := s.offloadPayload(ctx, job); err != nil {
+			return nil, err
+		}
+	}
+
+	err := s.repo.WithTx(ctx, func(tx repository.JobRepository) error {
+		for i, job := range jobs {
+			existing, err := s.existingJob(ctx, tx, job)
+			if err != nil {
+				return fmt.Errorf("job %d: %w", i, err)
+			}
+			if existing != nil {
+				jobs[i] = existing
+				continue
+			}
+			if err := tx.Create(ctx, job); err != nil {
+				return fmt.Errorf("failed to create job %d: %w", i, err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return jobs, nil
+}
+
+// ValidateJob checks that a job with this type and payload could be created,
+// without persisting anything. CreateJob runs exactly the same checks.
+func (s *JobService) ValidateJob(jobType string, payload []byte, opts JobOptions) error {
+	_, err := s.newJob(jobType, payload, opts)
+	return err
+}
+
+// newJob validates input and builds a new PENDING job without saving it.
+func (s *JobService) newJob(jobType string, payload []byte, opts JobOptions) (*model.Job, error) {
 	// Validate input
 	if jobType == "" {
 		return nil, fmt.Errorf("job type is required")
@@ -48,7 +250,7 @@ func (s *JobService) CreateJob(ctx context.Context, jobType string, payload []by
 	}
 
 	// Generate unique ID
-	id := s.idGenerator.Generate()
+	id := s.newID(jobType)
 
 	// Create job with initial state
 	job := &model.Job{
@@ -57,8 +259,30 @@ func (s *JobService) CreateJob(ctx context.Context, jobType string, payload []by
 		Payload:     payload,
 		State:       state.PENDING,
 		Attempt:     1,
-		MaxAttempts: 3, // Default, could be configurable
-		CreatedAt:   time.Now(),
+		MaxAttempts: defaultMaxAttempts,
+		CreatedAt:   s.clock.Now(),
+		Priority:    opts.Priority,
+	}
+
+	if opts.MaxAttempts != 0 {
+		job.MaxAttempts = opts.MaxAttempts
+	}
+	if limit := s.retryConfig.MaxAllowedAttempts; limit > 0 && job.MaxAttempts > limit {
+		return nil, fmt.Errorf("max attempts must be at most %d, got %d", limit, job.MaxAttempts)
+	}
+
+	if opts.IdempotencyKey != "" {
+		job.IdempotencyKey = &opts.IdempotencyKey
+	}
+
+	if opts.Schedule != "" {
+		schedule, err := parseSchedule(opts.Schedule)
+		if err != nil {
+			return nil, err
+		}
+		runAt := schedule.Next(job.CreatedAt)
+		job.Schedule = &opts.Schedule
+		job.RunAt = &runAt
 	}
 
 	// Validate job
@@ -66,17 +290,17 @@ func (s *JobService) CreateJob(ctx context.Context, jobType string, payload []by
 		return nil, fmt.Errorf("job validation failed: %w", err)
 	}
 
-	// Save to repository
-	if err := s.repo.Create(ctx, job); err != nil {
-		return nil, fmt.Errorf("failed to create job: %w", err)
-	}
-
 	return job, nil
 }
 
 // GetJob retrieves a job by ID.
 func (s *JobService) GetJob(ctx context.Context, id string) (*model.Job, error) {
-	job, err := s.repo.GetByID(ctx, id)
+	return getJob(ctx, s.repo, id)
+}
+
+// getJob retrieves a job by ID from repo, which may be a transaction.
+func getJob(ctx context.Context, repo repository.JobRepository, id string) (*model.Job, error) {
+	job, err := repo.GetByID(ctx, id)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get job: %w", err)
 	}
@@ -88,13 +312,47 @@ func (s *JobService) GetJob(ctx context.Context, id string) (*model.Job, error)
 	return job, nil
 }
 
-// ListJobsByState lists jobs in a specific state.
-func (s *JobService) ListJobsByState(ctx context.Context, jobState state.State, limit int) ([]*model.Job, error) {
-	if limit <= 0 {
-		limit = 10 // Default limit
+// GetJobStates returns the current state of each listed job, keyed by ID.
+// IDs that don't exist are left out of the result.
+func (s *JobService) GetJobStates(ctx context.Context, ids []string) (map[string]state.State, error) {
+	states, err := s.repo.GetStates(ctx, ids)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get job states: %w", err)
+	}
+
+	return states, nil
+}
+
+// ListJobErrors returns the per-attempt error history of a job, oldest first.
+// Jobs that never errored return an empty slice.
+func (s *JobService) ListJobErrors(ctx context.Context, id string) ([]*model.AttemptError, error) {
+	// Ensure the job exists so callers can tell "no errors" from "no job"
+	exists, err := s.repo.Exists(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get job: %w", err)
+	}
+	if !exists {
+		return nil, fmt.Errorf("job not found: %s", id)
 	}
 
-	jobs, err := s.repo.ListByState(ctx, jobState, limit)
+	attemptErrs, err := s.repo.ListAttemptErrors(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list job errors: %w", err)
+	}
+
+	return attemptErrs, nil
+}
+
+// ListJobsByState lists jobs in a specific state, oldest first.
+func (s *JobService) ListJobsByState(ctx context.Context, jobState state.State, limit int) ([]*model.Job, error) {
+	return s.ListJobsByStateOrdered(ctx, jobState, limit, repository.SortAsc)
+}
+
+// ListJobsByStateOrdered lists jobs in a specific state in the given creation order.
+func (s *JobService) ListJobsByStateOrdered(ctx context.Context, jobState state.State, limit int, order repository.SortOrder) ([]*model.Job, error) {
+	limit = s.ListLimit(limit)
+
+	jobs, err := s.repo.ListByStateOrdered(ctx, jobState, limit, order)
 	if err != nil {
 		return nil, fmt.Errorf("failed to list jobs: %w", err)
 	}
@@ -102,15 +360,118 @@ func (s *JobService) ListJobsByState(ctx context.Context, jobState state.State,
 	return jobs, nil
 }
 
+// CountJobsByState counts jobs in any of the given states.
+func (s *JobService) CountJobsByState(ctx context.Context, states ...state.State) (int, error) {
+	count, err := s.repo.CountByState(ctx, states...)
+	if err != nil {
+		return 0, fmt.Errorf("failed to count jobs: %w", err)
+	}
+
+	return count, nil
+}
+
+// CountJobsByTypeAndState counts jobs per type and state, for a types ×
+// states grid. Missing entries mean zero.
+func (s *JobService) CountJobsByTypeAndState(ctx context.Context) (map[string]map[state.State]int, error) {
+	counts, err := s.repo.CountByTypeAndState(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count jobs: %w", err)
+	}
+
+	return counts, nil
+}
+
+// OldestPendingAge returns how long the oldest due PENDING job has been
+// waiting to be claimed, or 0 if none is. A growing value means the
+// scheduler or workers aren't keeping up.
+func (s *JobService) OldestPendingAge(ctx context.Context) (time.Duration, error) {
+	age, err := s.repo.OldestPendingAge(ctx, s.clock.Now())
+	if err != nil {
+		return 0, fmt.Errorf("failed to get oldest pending age: %w", err)
+	}
+
+	return age, nil
+}
+
+// ListJobsByResult returns SUCCEEDED jobs whose result has key set to value.
+func (s *JobService) ListJobsByResult(ctx context.Context, key, value string, limit int) ([]*model.Job, error) {
+	limit = s.ListLimit(limit)
+
+	jobs, err := s.repo.ListByResultKey(ctx, key, value, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list jobs by result: %w", err)
+	}
+
+	return jobs, nil
+}
+
+// PeekQueue returns the next jobs the scheduler would claim, in order,
+// without claiming them. Useful for debugging why a job isn't running.
+func (s *JobService) PeekQueue(ctx context.Context, limit int) ([]*model.Job, error) {
+	limit = s.ListLimit(limit)
+
+	jobs, err := s.repo.PeekClaimable(ctx, limit, s.clock.Now())
+	if err != nil {
+		return nil, fmt.Errorf("failed to peek queue: %w", err)
+	}
+
+	return jobs, nil
+}
+
+// ListJobTypes returns the distinct job types that currently exist in storage.
+// Types that are registered with an executor but have no jobs are not included.
+func (s *JobService) ListJobTypes(ctx context.Context) ([]string, error) {
+	types, err := s.repo.DistinctTypes(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list job types: %w", err)
+	}
+
+	return types, nil
+}
+
+// TopErrors returns the most common error messages among FAILED jobs,
+// with how many jobs failed with each. Messages are grouped verbatim.
+func (s *JobService) TopErrors(ctx context.Context, limit int) ([]model.ErrorCount, error) {
+	counts, err := s.repo.TopErrors(ctx, s.ListLimit(limit))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list top errors: %w", err)
+	}
+
+	return counts, nil
+}
+
 // TransitionState transitions a job to a new state.
 // Validates the transition using the state machine.
 func (s *JobService) TransitionState(ctx context.Context, id string, newState state.State) error {
+	return s.TransitionStateWithReason(ctx, id, newState, "")
+}
+
+// TransitionStateWithReason transitions a job to a new state and records why.
+// An empty reason clears any reason left by a previous transition.
+func (s *JobService) TransitionStateWithReason(ctx context.Context, id string, newState state.State, reason string) error {
+	return s.transitionState(ctx, id, newState, reason, false)
+}
+
+// TransitionStateIdempotent is TransitionState for callers that only care
+// about the end state: if the job is already in newState it returns nil
+// and leaves the job untouched, instead of rejecting the self-transition.
+func (s *JobService) TransitionStateIdempotent(ctx context.Context, id string, newState state.State) error {
+	return s.transitionState(ctx, id, newState, "", true)
+}
+
+// transitionState implements the TransitionState variants. With
+// idempotent set, a job already in newState is a no-op rather than an error.
+func (s *JobService) transitionState(ctx context.Context, id string, newState state.State, reason string, idempotent bool) error {
 	// Get current job
 	job, err := s.GetJob(ctx, id)
 	if err != nil {
 		return err
 	}
 
+	if idempotent && job.State == newState {
+		return nil
+	}
+
 	// Validate transition
 	if err := s.stateMachine.ValidateTransition(job.State, newState); err != nil {
 		return fmt.Errorf("invalid state transition: %w", err)
@@ -118,9 +479,10 @@ func (s *JobService) TransitionState(ctx context.Context, id string, newState st
 
 	// Update state
 	job.State = newState
+	job.LastTransitionReason = reasonPtr(reason)
 
 	// Update timestamps based on new state
-	now := time.Now()
+	now := s.clock.Now()
 	switch newState {
 	case state.SCHEDULED:
 		job.ScheduledAt = &now
@@ -131,50 +493,121 @@ func (s *JobService) TransitionState(ctx context.Context, id string, newState st
 	}
 
 	// Save changes
-	if err := s.repo.Update(ctx, job); err != nil {
+	if err := s.saveProgress(ctx, s.repo, job); err != nil {
 		return fmt.Errorf("failed to update job state: %w", err)
 	}
+	s.notifyIfDone(job)
 
 	return nil
 }
 
 // HandleFailure handles a job failure, deciding whether to retry or fail permanently.
-func (s *JobService) HandleFailure(ctx context.Context, id string, failureErr error) error {
-	// Get current job
-	job, err := s.GetJob(ctx, id)
+// exhausted reports whether the job was failed for good, as written in the
+// same transaction, so callers needn't re-read a state others may change.
+//
+// A job that already left RUNNING, e.g. force-failed or cancelled while its
+// executor was returning, is left as it is and exhausted is false.
+func (s *JobService) HandleFailure(ctx context.Context, id string, failureErr error) (exhausted bool, err error) {
+	var job *model.Job
+	handled := false
+
+	// Read-modify-write in one transaction so a crash can't leave the
+	// error history and the job's attempt/state out of sync. The row is
+	// locked so a concurrent force-fail or cancel can't be overwritten.
+	err = s.repo.WithTx(ctx, func(tx repository.JobRepository) error {
+		// Get current job
+		var err error
+		job, err = tx.GetByIDForUpdate(ctx, id)
+		if err != nil {
+			return fmt.Errorf("failed to get job: %w", err)
+		}
+		if job == nil {
+			return fmt.Errorf("job not found: %s", id)
+		}
+		if job.State != state.RUNNING {
+			return nil
+		}
+		handled = true
+
+		// Record error, both on the job and in its per-attempt history
+		job.RecordError(failureErr)
+		if failureErr != nil {
+			attemptErr := &model.AttemptError{
+				JobID:      job.ID,
+				Attempt:    job.Attempt,
+				Error:      failureErr.Error(),
+				OccurredAt: s.clock.Now(),
+			}
+			if err := tx.RecordAttemptError(ctx, attemptErr); err != nil {
+				return fmt.Errorf("failed to record attempt error: %w", err)
+			}
+		}
+
+		// Decide: retry or fail permanently?
+		// Jobs past their total retry window fail even with attempts left
+		now := s.clock.Now()
+		if job.CanRetry() && !s.retryConfig.RetryWindowExceeded(job.CreatedAt, now) {
+			// The scheduler won't claim the job again until its backoff expires.
+			// Backoff is keyed by the attempt that failed, so the first retry waits BaseDelay.
+			nextRetryAt := now.Add(s.retryConfig.CalculateBackoff(job.Attempt))
+			job.NextRetryAt = &nextRetryAt
+
+			// Increment attempt for next retry
+			job.IncrementAttempt()
+
+			// Transition to RETRYING
+			job.State = state.RETRYING
+
+			// Bump priority so repeated failures still get a turn
+			job.Priority = s.retryConfig.BoostPriority(job.Priority)
+
+		} else {
+			// Max attempts exhausted, fail permanently
+			job.State = state.FAILED
+			job.CompletedAt = &now
+		}
+
+		// Save changes
+		if err := s.saveProgress(ctx, tx, job); err != nil {
+			return fmt.Errorf("failed to update job after failure: %w", err)
+		}
+
+		return nil
+	})
 	if err != nil {
-		return err
+		return false, err
+	}
+	if !handled {
+		return false, nil
 	}
 
-	// Record error
-	job.RecordError(failureErr)
-
-	// Decide: retry or fail permanently?
-	if job.CanRetry() {
-		// Increment attempt for next retry
-		job.IncrementAttempt()
-
-		// Transition to RETRYING
-		job.State = state.RETRYING
-
-		// Calculate backoff delay (for scheduler to use)
-		// Note: We don't implement the delay here, just calculate it
-		_ = s.retryConfig.CalculateBackoff(job.Attempt)
-		// In Phase D, scheduler will use this delay
+	s.notifyIfDone(job)
+	return job.State == state.FAILED, nil
+}
 
-	} else {
-		// Max attempts exhausted, fail permanently
-		job.State = state.FAILED
-		now := time.Now()
-		job.CompletedAt = &now
+// NextRetryDelay returns the retry delay computed for a RETRYING job:
+// retryConfig.CalculateBackoff(job.Attempt). It is the configured
+// backoff, not the time left until next_retry_at.
+func (s *JobService) NextRetryDelay(ctx context.Context, id string) (time.Duration, error) {
+	job, err := s.GetJob(ctx, id)
+	if err != nil {
+		return 0, err
 	}
 
-	// Save changes
-	if err := s.repo.Update(ctx, job); err != nil {
-		return fmt.Errorf("failed to update job after failure: %w", err)
+	delay, ok := s.RetryDelay(job)
+	if !ok {
+		return 0, fmt.Errorf("job is not retrying, state is %s", job.State)
 	}
+	return delay, nil
+}
 
-	return nil
+// RetryDelay is NextRetryDelay for a job already read, e.g. one being
+// rendered in a response. ok is false unless the job is RETRYING.
+func (s *JobService) RetryDelay(job *model.Job) (delay time.Duration, ok bool) {
+	if job.State != state.RETRYING {
+		return 0, false
+	}
+	return s.retryConfig.CalculateBackoff(job.Attempt), true
 }
 
 // CancelJob cancels a job if it's in a cancellable state.
@@ -197,13 +630,192 @@ func (s *JobService) CancelJob(ctx context.Context, id string) error {
 
 	// Transition to CANCELLED
 	job.State = state.CANCELLED
-	now := time.Now()
+	job.LastTransitionReason = reasonPtr("user cancelled via API")
+	now := s.clock.Now()
 	job.CompletedAt = &now
 
 	// Save changes
-	if err := s.repo.Update(ctx, job); err != nil {
+	if err := s.repo.UpdateProgress(ctx, job); err != nil {
 		return fmt.Errorf("failed to cancel job: %w", err)
 	}
+	s.notifyIfDone(job)
 
 	return nil
 }
+
+// RequeueJob resets a FAILED job back to PENDING so it runs again.
+// The job gets a fresh set of attempts (Attempt resets to 1), and
+// additionalAttempts is added to MaxAttempts to grant extra tries, up to
+// RetryConfig.MaxAllowedAttempts when configured.
+//
+// This deliberately bypasses the state machine: FAILED is terminal for
+// automatic processing, and requeueing is an explicit manual intervention.
+func (s *JobService) RequeueJob(ctx context.Context, id string, additionalAttempts int) (*model.Job, error) {
+	if additionalAttempts < 0 {
+		return nil, fmt.Errorf("additional attempts must be non-negative, got %d", additionalAttempts)
+	}
+
+	// Get current job
+	job, err := s.GetJob(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if job.State != state.FAILED {
+		return nil, fmt.Errorf("only FAILED jobs can be requeued, job is %s", job.State)
+	}
+	maxAttempts := job.MaxAttempts + additionalAttempts
+	if limit := s.retryConfig.MaxAllowedAttempts; limit > 0 && additionalAttempts > 0 && maxAttempts > limit {
+		return nil, fmt.Errorf("max attempts must be at most %d, got %d", limit, maxAttempts)
+	}
+
+	// Reset to a fresh PENDING job with the extended attempt budget
+	job.State = state.PENDING
+	job.MaxAttempts = maxAttempts
+	job.Attempt = 1
+	job.ClearError()
+	job.ScheduledAt = nil
+	job.StartedAt = nil
+	job.CompletedAt = nil
+	job.NextRetryAt = nil
+
+	// This occurrence's successor was enqueued when it failed; re-running
+	// it as a recurring job would fork the series
+	job.Schedule = nil
+
+	// Validate job (Attempt <= MaxAttempts etc.)
+	if err := job.Validate(); err != nil {
+		return nil, fmt.Errorf("job validation failed: %w", err)
+	}
+
+	// Save changes
+	if err := s.repo.Update(ctx, job); err != nil {
+		return nil, fmt.Errorf("failed to requeue job: %w", err)
+	}
+
+	return job, nil
+}
+
+// SetMaxAttempts changes the attempt budget of a job that hasn't finished,
+// e.g. to give a RETRYING job more attempts while a dependency is down.
+// n must be at least the job's current attempt and, when configured, at
+// most RetryConfig.MaxAllowedAttempts. Only max attempts is written, so a
+// worker running the job concurrently is unaffected.
+func (s *JobService) SetMaxAttempts(ctx context.Context, id string, n int) (*model.Job, error) {
+	if limit := s.retryConfig.MaxAllowedAttempts; limit > 0 && n > limit {
+		return nil, fmt.Errorf("max attempts must be at most %d, got %d", limit, n)
+	}
+
+	// Get current job
+	job, err := s.GetJob(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if job.IsTerminal() {
+		return nil, fmt.Errorf("max attempts of a finished job can't change, job is %s", job.State)
+	}
+	if n < job.Attempt {
+		return nil, fmt.Errorf("max attempts must be at least the current attempt %d, got %d", job.Attempt, n)
+	}
+
+	// Conditional write: the job may have finished or moved on since the read
+	updated, err := s.repo.UpdateMaxAttempts(ctx, id, n)
+	if err != nil {
+		return nil, fmt.Errorf("failed to set max attempts: %w", err)
+	}
+	if !updated {
+		return nil, fmt.Errorf("job %s changed while setting max attempts; check its state and retry", id)
+	}
+
+	job.MaxAttempts = n
+	return job, nil
+}
+
+// RerunJob runs a finished job again with the same payload, e.g. to
+// re-send a report. The job is reset in place rather than copied: it goes
+// back to PENDING with Attempt 1, its error, lifecycle timestamps and
+// result are cleared, and RerunCount is incremented. Its per-attempt
+// error history is kept.
+//
+// Like RequeueJob this deliberately bypasses the state machine, and it
+// works from any terminal state. A rerun occurrence of a recurring job
+// runs once; its successor already exists, so the series isn't forked.
+func (s *JobService) RerunJob(ctx context.Context, id string) (*model.Job, error) {
+	// Get current job
+	job, err := s.GetJob(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if !job.IsTerminal() {
+		return nil, fmt.Errorf("only finished jobs can be rerun, job is %s", job.State)
+	}
+
+	job.State = state.PENDING
+	job.Attempt = 1
+	job.ClearError()
+	job.ScheduledAt = nil
+	job.StartedAt = nil
+	job.CompletedAt = nil
+	job.NextRetryAt = nil
+	job.RunAt = nil
+	job.Result = nil
+	job.Schedule = nil
+	job.RerunCount++
+	job.LastTransitionReason = reasonPtr("rerun via API")
+
+	// Save changes
+	if err := s.repo.Update(ctx, job); err != nil {
+		return nil, fmt.Errorf("failed to rerun job: %w", err)
+	}
+
+	return job, nil
+}
+
+// ForceFail marks a stuck job as FAILED without waiting for its timeout.
+// Allowed from RUNNING, SCHEDULED, and RETRYING; reason is stored as the
+// job's last error so it shows up alongside normal failures.
+//
+// SCHEDULED/RETRYING -> FAILED aren't normal lifecycle transitions, so like
+// RequeueJob this is an explicit manual intervention outside the state machine.
+// Callers are responsible for stopping any local execution of the job.
+func (s *JobService) ForceFail(ctx context.Context, id string, reason string) (*model.Job, error) {
+	if reason == "" {
+		return nil, fmt.Errorf("reason is required")
+	}
+
+	// Get current job
+	job, err := s.GetJob(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	switch job.State {
+	case state.RUNNING, state.SCHEDULED, state.RETRYING:
+	default:
+		return nil, fmt.Errorf("cannot force-fail job in state: %s", job.State)
+	}
+
+	job.State = state.FAILED
+	job.LastError = &reason
+	job.LastTransitionReason = reasonPtr("force-failed: " + reason)
+	now := s.clock.Now()
+	job.CompletedAt = &now
+
+	// Save changes
+	if err := s.saveProgress(ctx, s.repo, job); err != nil {
+		return nil, fmt.Errorf("failed to force-fail job: %w", err)
+	}
+	s.notifyIfDone(job)
+
+	return job, nil
+}
+
+// reasonPtr converts a transition reason to its stored form (nil if empty).
+func reasonPtr(reason string) *string {
+	if reason == "" {
+		return nil
+	}
+	return &reason
+}
@@ -5,10 +5,14 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt" // ← Add this
+	"strings"
 	"testing"
 	"time"
 
+	"github.com/dipak0000812/orchestrix/internal/blobstore"
+	"github.com/dipak0000812/orchestrix/internal/clock"
 	"github.com/dipak0000812/orchestrix/internal/job/model"
+	"github.com/dipak0000812/orchestrix/internal/job/repository"
 	"github.com/dipak0000812/orchestrix/internal/job/state"
 )
 
@@ -23,12 +27,14 @@ func (m *mockIDGenerator) Generate() string {
 
 // Mock Repository (in-memory, for unit tests)
 type mockRepository struct {
-	jobs map[string]*model.Job
+	jobs   map[string]*model.Job
+	errors map[string][]*model.AttemptError
 }
 
 func newMockRepository() *mockRepository {
 	return &mockRepository{
-		jobs: make(map[string]*model.Job),
+		jobs:   make(map[string]*model.Job),
+		errors: make(map[string][]*model.AttemptError),
 	}
 }
 
@@ -48,6 +54,10 @@ func (r *mockRepository) GetByID(ctx context.Context, id string) (*model.Job, er
 	return job, nil
 }
 
+func (r *mockRepository) GetByIDForUpdate(ctx context.Context, id string) (*model.Job, error) {
+	return r.GetByID(ctx, id)
+}
+
 func (r *mockRepository) Update(ctx context.Context, job *model.Job) error {
 	if _, exists := r.jobs[job.ID]; !exists {
 		return errors.New("job not found")
@@ -56,6 +66,54 @@ func (r *mockRepository) Update(ctx context.Context, job *model.Job) error {
 	return nil
 }
 
+func (r *mockRepository) UpdateProgress(ctx context.Context, job *model.Job) error {
+	stored, exists := r.jobs[job.ID]
+	if !exists {
+		return errors.New("job not found")
+	}
+	stored.State = job.State
+	stored.Attempt = job.Attempt
+	stored.LastError = job.LastError
+	stored.ScheduledAt = job.ScheduledAt
+	stored.StartedAt = job.StartedAt
+	stored.CompletedAt = job.CompletedAt
+	stored.LastTransitionReason = job.LastTransitionReason
+	stored.Priority = job.Priority
+	stored.NextRetryAt = job.NextRetryAt
+	return nil
+}
+
+func (r *mockRepository) GetStates(ctx context.Context, ids []string) (map[string]state.State, error) {
+	states := make(map[string]state.State)
+	for _, id := range ids {
+		if job, exists := r.jobs[id]; exists {
+			states[id] = job.State
+		}
+	}
+	return states, nil
+}
+
+func (r *mockRepository) Exists(ctx context.Context, id string) (bool, error) {
+	_, exists := r.jobs[id]
+	return exists, nil
+}
+
+func (r *mockRepository) UpdateStateBatch(ctx context.Context, ids []string, from, to state.State, reason string) ([]string, error) {
+	updated := []string{}
+	for _, id := range ids {
+		if job, exists := r.jobs[id]; exists && job.State == from {
+			job.State = to
+			job.LastTransitionReason = reasonPtr(reason)
+			if to == state.PENDING || to == state.RETRYING {
+				job.ScheduledAt = nil
+				job.StartedAt = nil
+			}
+			updated = append(updated, id)
+		}
+	}
+	return updated, nil
+}
+
 func (r *mockRepository) UpdateState(ctx context.Context, id string, newState state.State) error {
 	job, exists := r.jobs[id]
 	if !exists {
@@ -65,6 +123,14 @@ func (r *mockRepository) UpdateState(ctx context.Context, id string, newState st
 	return nil
 }
 
+func (r *mockRepository) ListByResultKey(ctx context.Context, key, value string, limit int) ([]*model.Job, error) {
+	return []*model.Job{}, nil
+}
+
+func (r *mockRepository) TopErrors(ctx context.Context, limit int) ([]model.ErrorCount, error) {
+	return []model.ErrorCount{}, nil
+}
+
 func (r *mockRepository) ListByState(ctx context.Context, jobState state.State, limit int) ([]*model.Job, error) {
 	var jobs []*model.Job
 	for _, job := range r.jobs {
@@ -78,11 +144,121 @@ func (r *mockRepository) ListByState(ctx context.Context, jobState state.State,
 	return jobs, nil
 }
 
+func (r *mockRepository) ListByStateOrdered(ctx context.Context, jobState state.State, limit int, order repository.SortOrder) ([]*model.Job, error) {
+	return r.ListByState(ctx, jobState, limit)
+}
+
 func (r *mockRepository) Delete(ctx context.Context, id string) error {
 	delete(r.jobs, id)
 	return nil
 }
 
+func (r *mockRepository) RecordAttemptError(ctx context.Context, attemptErr *model.AttemptError) error {
+	r.errors[attemptErr.JobID] = append(r.errors[attemptErr.JobID], attemptErr)
+	return nil
+}
+
+func (r *mockRepository) ListAttemptErrors(ctx context.Context, jobID string) ([]*model.AttemptError, error) {
+	return append([]*model.AttemptError{}, r.errors[jobID]...), nil
+}
+
+func (r *mockRepository) CountByState(ctx context.Context, states ...state.State) (int, error) {
+	count := 0
+	for _, job := range r.jobs {
+		for _, s := range states {
+			if job.State == s {
+				count++
+				break
+			}
+		}
+	}
+	return count, nil
+}
+
+func (r *mockRepository) CountByTypeAndState(ctx context.Context) (map[string]map[state.State]int, error) {
+	counts := make(map[string]map[state.State]int)
+	for _, job := range r.jobs {
+		if counts[job.Type] == nil {
+			counts[job.Type] = make(map[state.State]int)
+		}
+		counts[job.Type][job.State]++
+	}
+	return counts, nil
+}
+
+func (r *mockRepository) OldestPendingAge(ctx context.Context, now time.Time) (time.Duration, error) {
+	var oldest time.Duration
+	for _, job := range r.jobs {
+		if job.State == state.PENDING && (job.RunAt == nil || !job.RunAt.After(now)) {
+			oldest = max(oldest, now.Sub(job.CreatedAt))
+		}
+	}
+	return oldest, nil
+}
+
+func (r *mockRepository) GetByIdempotencyKey(ctx context.Context, key, jobType string) (*model.Job, error) {
+	for _, job := range r.jobs {
+		if job.IdempotencyKey != nil && *job.IdempotencyKey == key && job.Type == jobType {
+			return job, nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *mockRepository) UpdateMaxAttempts(ctx context.Context, id string, maxAttempts int) (bool, error) {
+	job, exists := r.jobs[id]
+	if !exists || job.IsTerminal() || job.Attempt > maxAttempts {
+		return false, nil
+	}
+	job.MaxAttempts = maxAttempts
+	return true, nil
+}
+
+func (r *mockRepository) TruncateAll(ctx context.Context) error {
+	r.jobs = make(map[string]*model.Job)
+	r.errors = make(map[string][]*model.AttemptError)
+	return nil
+}
+
+func (r *mockRepository) WithTx(ctx context.Context, fn func(tx repository.JobRepository) error) error {
+	return fn(r)
+}
+
+func (r *mockRepository) PeekClaimable(ctx context.Context, limit int, now time.Time, skipTypes ...string) ([]*model.Job, error) {
+	jobs := []*model.Job{}
+	for _, job := range r.jobs {
+		if job.State == state.PENDING || job.State == state.RETRYING {
+			jobs = append(jobs, job)
+			if len(jobs) >= limit {
+				break
+			}
+		}
+	}
+	return jobs, nil
+}
+
+func (r *mockRepository) DistinctTypes(ctx context.Context) ([]string, error) {
+	seen := make(map[string]bool)
+	types := []string{}
+	for _, job := range r.jobs {
+		if !seen[job.Type] {
+			seen[job.Type] = true
+			types = append(types, job.Type)
+		}
+	}
+	return types, nil
+}
+
+func (r *mockRepository) FindStaleScheduled(ctx context.Context, cutoff time.Time) ([]*model.Job, error) {
+	jobs := []*model.Job{}
+	for _, job := range r.jobs {
+		if job.State == state.SCHEDULED && job.ScheduledAt != nil && job.ScheduledAt.Before(cutoff) {
+			jobs = append(jobs, job)
+		}
+	}
+	return jobs, nil
+}
+
 // Test helper: create test service
 func setupTestService() *JobService {
 	repo := newMockRepository()
@@ -93,6 +269,16 @@ func setupTestService() *JobService {
 	return NewJobService(repo, stateMachine, idGen, retryConfig)
 }
 
+func TestClose_Idempotent(t *testing.T) {
+	service := NewJobService(repository.NewMemoryJobRepository(), state.NewStateMachine(), NewULIDGenerator(), DefaultRetryConfig())
+
+	for i := 0; i < 3; i++ {
+		if err := service.Close(); err != nil {
+			t.Fatalf("Close call %d failed: %v", i+1, err)
+		}
+	}
+}
+
 func TestCreateJob(t *testing.T) {
 	service := setupTestService()
 	ctx := context.Background()
@@ -122,6 +308,134 @@ func TestCreateJob(t *testing.T) {
 	}
 }
 
+func TestCreateJob_CancelledContext(t *testing.T) {
+	repo := repository.NewMemoryJobRepository()
+	service := NewJobService(repo, state.NewStateMachine(), NewULIDGenerator(), DefaultRetryConfig())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	job, err := service.CreateJob(ctx, "send_email", []byte(`{}`))
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("CreateJob error = %v, want context.Canceled", err)
+	}
+	if job != nil {
+		t.Errorf("CreateJob returned job %s for a cancelled context", job.ID)
+	}
+
+	if count, _ := repo.CountByState(context.Background(), state.PENDING); count != 0 {
+		t.Errorf("Pending jobs = %d, want 0", count)
+	}
+}
+
+func TestCreateJobs_AllOrNothing(t *testing.T) {
+	ctx := context.Background()
+	repo := repository.NewMemoryJobRepository()
+	service := NewJobService(repo, state.NewStateMachine(), NewULIDGenerator(), DefaultRetryConfig())
+
+	_, err := service.CreateJobs(ctx, []JobSpec{
+		{Type: "test_job", Payload: []byte(`{}`)},
+		{Type: "test_job", Payload: []byte(`{invalid`)},
+	})
+	if err == nil || !strings.Contains(err.Error(), "job 1") {
+		t.Fatalf("CreateJobs error = %v, want one naming job 1", err)
+	}
+	if count, _ := repo.CountByState(ctx, state.PENDING); count != 0 {
+		t.Errorf("Pending jobs = %d, want 0", count)
+	}
+
+	jobs, err := service.CreateJobs(ctx, []JobSpec{
+		{Type: "first", Payload: []byte(`{}`)},
+		{Type: "second", Payload: []byte(`{}`), Options: JobOptions{Priority: 5}},
+	})
+	if err != nil {
+		t.Fatalf("CreateJobs failed: %v", err)
+	}
+	if len(jobs) != 2 || jobs[0].Type != "first" || jobs[1].Priority != 5 {
+		t.Errorf("Jobs = %+v, want first then second with priority 5", jobs)
+	}
+	if count, _ := repo.CountByState(ctx, state.PENDING); count != 2 {
+		t.Errorf("Pending jobs = %d, want 2", count)
+	}
+}
+
+func TestCreateJob_TypePrefixedIDs(t *testing.T) {
+	ctx := context.Background()
+	repo := repository.NewMemoryJobRepository()
+	service := NewJobService(repo, state.NewStateMachine(), NewULIDGenerator(), DefaultRetryConfig())
+	service.SetTypePrefixedIDs(true)
+
+	seen := make(map[string]bool)
+	for i := 0; i < 100; i++ {
+		job, err := service.CreateJob(ctx, "email", []byte(`{}`))
+		if err != nil {
+			t.Fatalf("CreateJob failed: %v", err)
+		}
+		if !strings.HasPrefix(job.ID, "email_") || len(job.ID) != len("email_")+26 {
+			t.Fatalf("ID = %q, want email_ followed by a ULID", job.ID)
+		}
+		if seen[job.ID] {
+			t.Fatalf("Duplicate ID %q", job.ID)
+		}
+		seen[job.ID] = true
+	}
+
+	// Characters that don't belong in a URL are dropped from the prefix
+	job, _ := service.CreateJob(ctx, "reports/daily pdf", []byte(`{}`))
+	if !strings.HasPrefix(job.ID, "reportsdailypdf_") {
+		t.Errorf("ID = %q, want the prefix reduced to reportsdailypdf_", job.ID)
+	}
+	if stored, _ := repo.GetByID(ctx, job.ID); stored == nil {
+		t.Errorf("Job %q not found by its prefixed ID", job.ID)
+	}
+}
+
+func TestCreateJob_IdempotencyKey(t *testing.T) {
+	ctx := context.Background()
+	repo := repository.NewMemoryJobRepository()
+	service := NewJobService(repo, state.NewStateMachine(), NewULIDGenerator(), DefaultRetryConfig())
+	opts := JobOptions{IdempotencyKey: "order-42"}
+
+	first, err := service.CreateJobWithOptions(ctx, "email", []byte(`{}`), opts)
+	if err != nil {
+		t.Fatalf("CreateJobWithOptions failed: %v", err)
+	}
+
+	// Same key and type: the first job comes back, nothing new is stored
+	again, err := service.CreateJobWithOptions(ctx, "email", []byte(`{"different": true}`), opts)
+	if err != nil {
+		t.Fatalf("Repeated CreateJobWithOptions failed: %v", err)
+	}
+	if again.ID != first.ID {
+		t.Errorf("Repeat returned job %s, want the original %s", again.ID, first.ID)
+	}
+
+	// The key is scoped to the type
+	other, _ := service.CreateJobWithOptions(ctx, "sms", []byte(`{}`), opts)
+	if other.ID == first.ID {
+		t.Error("Same key for another type should create a new job")
+	}
+	if count, _ := repo.CountByState(ctx, state.PENDING); count != 2 {
+		t.Errorf("Pending jobs = %d, want 2", count)
+	}
+
+	// Batches reuse existing jobs too, including within the batch
+	jobs, err := service.CreateJobs(ctx, []JobSpec{
+		{Type: "email", Payload: []byte(`{}`), Options: opts},
+		{Type: "push", Payload: []byte(`{}`), Options: JobOptions{IdempotencyKey: "batch-1"}},
+		{Type: "push", Payload: []byte(`{}`), Options: JobOptions{IdempotencyKey: "batch-1"}},
+	})
+	if err != nil {
+		t.Fatalf("CreateJobs failed: %v", err)
+	}
+	if jobs[0].ID != first.ID || jobs[1].ID != jobs[2].ID {
+		t.Errorf("Batch IDs = %s, %s, %s; want %s then one job twice", jobs[0].ID, jobs[1].ID, jobs[2].ID, first.ID)
+	}
+	if count, _ := repo.CountByState(ctx, state.PENDING); count != 3 {
+		t.Errorf("Pending jobs = %d, want 3", count)
+	}
+}
+
 func TestCreateJob_EmptyType(t *testing.T) {
 	service := setupTestService()
 	ctx := context.Background()
@@ -142,6 +456,26 @@ func TestCreateJob_InvalidJSON(t *testing.T) {
 	}
 }
 
+func TestCreateJobWithOptions_MaxAttemptsCap(t *testing.T) {
+	service := setupTestService()
+	ctx := context.Background()
+	limit := DefaultRetryConfig().MaxAllowedAttempts
+
+	// Exactly at the cap is allowed
+	job, err := service.CreateJobWithOptions(ctx, "test_job", nil, JobOptions{MaxAttempts: limit})
+	if err != nil {
+		t.Fatalf("CreateJobWithOptions at cap failed: %v", err)
+	}
+	if job.MaxAttempts != limit {
+		t.Errorf("MaxAttempts = %d, want %d", job.MaxAttempts, limit)
+	}
+
+	// One over the cap is rejected
+	if _, err := service.CreateJobWithOptions(ctx, "test_job", nil, JobOptions{MaxAttempts: limit + 1}); err == nil {
+		t.Error("Expected error when MaxAttempts exceeds the cap")
+	}
+}
+
 func TestGetJob(t *testing.T) {
 	service := setupTestService()
 	ctx := context.Background()
@@ -212,6 +546,63 @@ func TestTransitionState_Invalid(t *testing.T) {
 	}
 }
 
+func TestTransitionStateIdempotent(t *testing.T) {
+	service := setupTestService()
+	ctx := context.Background()
+
+	job, _ := service.CreateJob(ctx, "test_job", []byte(`{}`))
+	service.TransitionState(ctx, job.ID, state.SCHEDULED)
+	service.TransitionState(ctx, job.ID, state.RUNNING)
+	running, _ := service.GetJob(ctx, job.ID)
+
+	// Strict mode still rejects the redundant transition
+	if err := service.TransitionState(ctx, job.ID, state.RUNNING); err == nil {
+		t.Error("Expected error for RUNNING -> RUNNING in strict mode")
+	}
+
+	if err := service.TransitionStateIdempotent(ctx, job.ID, state.RUNNING); err != nil {
+		t.Fatalf("TransitionStateIdempotent failed: %v", err)
+	}
+	updated, _ := service.GetJob(ctx, job.ID)
+	if updated.State != state.RUNNING || !updated.StartedAt.Equal(*running.StartedAt) {
+		t.Errorf("Job = %s started %v, want RUNNING and StartedAt unchanged (%v)", updated.State, updated.StartedAt, running.StartedAt)
+	}
+
+	// Real transitions still happen and are still validated
+	if err := service.TransitionStateIdempotent(ctx, job.ID, state.SUCCEEDED); err != nil {
+		t.Fatalf("TransitionStateIdempotent failed: %v", err)
+	}
+	if err := service.TransitionStateIdempotent(ctx, job.ID, state.RUNNING); err == nil {
+		t.Error("Expected error for SUCCEEDED -> RUNNING")
+	}
+}
+
+func TestTransitionStateWithReason(t *testing.T) {
+	service := setupTestService()
+	ctx := context.Background()
+
+	payload, _ := json.Marshal(map[string]string{"test": "data"})
+	job, _ := service.CreateJob(ctx, "test_job", payload)
+
+	err := service.TransitionStateWithReason(ctx, job.ID, state.SCHEDULED, "picked by scheduler")
+	if err != nil {
+		t.Fatalf("TransitionStateWithReason failed: %v", err)
+	}
+
+	updated, _ := service.GetJob(ctx, job.ID)
+	if updated.LastTransitionReason == nil || *updated.LastTransitionReason != "picked by scheduler" {
+		t.Errorf("LastTransitionReason = %v, want \"picked by scheduler\"", updated.LastTransitionReason)
+	}
+
+	// A transition without a reason clears the previous one
+	service.TransitionState(ctx, job.ID, state.RUNNING)
+
+	updated, _ = service.GetJob(ctx, job.ID)
+	if updated.LastTransitionReason != nil {
+		t.Errorf("LastTransitionReason = %q, want nil", *updated.LastTransitionReason)
+	}
+}
+
 func TestHandleFailure_CanRetry(t *testing.T) {
 	service := setupTestService()
 	ctx := context.Background()
@@ -226,7 +617,7 @@ func TestHandleFailure_CanRetry(t *testing.T) {
 
 	// Simulate failure
 	failureErr := errors.New("connection timeout")
-	err := service.HandleFailure(ctx, job.ID, failureErr)
+	_, err := service.HandleFailure(ctx, job.ID, failureErr)
 	if err != nil {
 		t.Fatalf("HandleFailure failed: %v", err)
 	}
@@ -248,6 +639,132 @@ func TestHandleFailure_CanRetry(t *testing.T) {
 	}
 }
 
+func TestHandleFailure_ReportsExhaustion(t *testing.T) {
+	service := setupTestService()
+	ctx := context.Background()
+
+	payload, _ := json.Marshal(map[string]string{"test": "data"})
+	job, _ := service.CreateJob(ctx, "test_job", payload)
+
+	// max_attempts = 3: two retries, then the third failure is final
+	for attempt, wantExhausted := range []bool{false, false, true} {
+		service.TransitionState(ctx, job.ID, state.SCHEDULED)
+		service.TransitionState(ctx, job.ID, state.RUNNING)
+
+		exhausted, err := service.HandleFailure(ctx, job.ID, errors.New("flaky"))
+		if err != nil {
+			t.Fatalf("HandleFailure failed: %v", err)
+		}
+
+		updated, _ := service.GetJob(ctx, job.ID)
+		if exhausted != wantExhausted {
+			t.Errorf("Attempt %d: exhausted = %v, want %v", attempt+1, exhausted, wantExhausted)
+		}
+		if exhausted != (updated.State == state.FAILED) {
+			t.Errorf("Attempt %d: exhausted = %v but persisted state is %s", attempt+1, exhausted, updated.State)
+		}
+	}
+}
+
+func TestHandleFailure_AfterForceFail(t *testing.T) {
+	service := setupTestService()
+	ctx := context.Background()
+
+	payload, _ := json.Marshal(map[string]string{"test": "data"})
+	job, _ := service.CreateJob(ctx, "test_job", payload)
+	service.TransitionState(ctx, job.ID, state.SCHEDULED)
+	service.TransitionState(ctx, job.ID, state.RUNNING)
+
+	// Force-failed while the executor was still returning its error
+	if _, err := service.ForceFail(ctx, job.ID, "stuck"); err != nil {
+		t.Fatalf("ForceFail failed: %v", err)
+	}
+
+	exhausted, err := service.HandleFailure(ctx, job.ID, errors.New("connection timeout"))
+	if err != nil {
+		t.Fatalf("HandleFailure failed: %v", err)
+	}
+	if exhausted {
+		t.Error("exhausted = true for a job HandleFailure left alone")
+	}
+
+	updated, _ := service.GetJob(ctx, job.ID)
+	if updated.State != state.FAILED {
+		t.Errorf("State = %s, want FAILED", updated.State)
+	}
+	if updated.Attempt != 1 {
+		t.Errorf("Attempt = %d, want 1", updated.Attempt)
+	}
+	if updated.LastError == nil || *updated.LastError != "stuck" {
+		t.Errorf("LastError = %v, want the force-fail reason", updated.LastError)
+	}
+}
+
+func TestHandleFailure_PriorityBoost(t *testing.T) {
+	retryConfig := DefaultRetryConfig()
+	retryConfig.PriorityBoost = 5
+	retryConfig.MaxPriority = 8
+
+	service := NewJobService(
+		newMockRepository(),
+		state.NewStateMachine(),
+		&mockIDGenerator{nextID: "test_job_123"},
+		retryConfig,
+	)
+	ctx := context.Background()
+
+	payload, _ := json.Marshal(map[string]string{"test": "data"})
+	job, _ := service.CreateJobWithOptions(ctx, "test_job", payload, JobOptions{Priority: 1})
+
+	// Priority rises on each retry, capped at MaxPriority
+	for _, want := range []int{6, 8} {
+		service.TransitionState(ctx, job.ID, state.SCHEDULED)
+		service.TransitionState(ctx, job.ID, state.RUNNING)
+		if _, err := service.HandleFailure(ctx, job.ID, errors.New("flaky")); err != nil {
+			t.Fatalf("HandleFailure failed: %v", err)
+		}
+
+		updated, _ := service.GetJob(ctx, job.ID)
+		if updated.Priority != want {
+			t.Errorf("Priority after attempt %d = %d, want %d", updated.Attempt-1, updated.Priority, want)
+		}
+	}
+}
+
+func TestHandleFailure_RetryWindowExceeded(t *testing.T) {
+	retryConfig := DefaultRetryConfig()
+	retryConfig.MaxElapsed = time.Hour
+
+	repo := newMockRepository()
+	service := NewJobService(
+		repo,
+		state.NewStateMachine(),
+		&mockIDGenerator{nextID: "test_job_123"},
+		retryConfig,
+	)
+	ctx := context.Background()
+
+	payload, _ := json.Marshal(map[string]string{"test": "data"})
+	job, _ := service.CreateJob(ctx, "test_job", payload)
+	service.TransitionState(ctx, job.ID, state.SCHEDULED)
+	service.TransitionState(ctx, job.ID, state.RUNNING)
+
+	// Created well outside the retry window
+	repo.jobs[job.ID].CreatedAt = time.Now().Add(-2 * time.Hour)
+
+	if _, err := service.HandleFailure(ctx, job.ID, errors.New("still broken")); err != nil {
+		t.Fatalf("HandleFailure failed: %v", err)
+	}
+
+	updated, _ := service.GetJob(ctx, job.ID)
+	if updated.State != state.FAILED {
+		t.Errorf("State = %s, want FAILED even with attempts remaining", updated.State)
+	}
+	if updated.Attempt != 1 {
+		t.Errorf("Attempt = %d, want 1", updated.Attempt)
+	}
+}
+
 func TestHandleFailure_ExhaustedRetries(t *testing.T) {
 	service := setupTestService()
 	ctx := context.Background()
@@ -280,6 +797,56 @@ func TestHandleFailure_ExhaustedRetries(t *testing.T) {
 	}
 }
 
+func TestListJobErrors(t *testing.T) {
+	service := setupTestService()
+	ctx := context.Background()
+
+	payload, _ := json.Marshal(map[string]string{"test": "data"})
+	job, _ := service.CreateJob(ctx, "test_job", payload)
+
+	// No errors yet: empty, not nil
+	errs, err := service.ListJobErrors(ctx, job.ID)
+	if err != nil {
+		t.Fatalf("ListJobErrors failed: %v", err)
+	}
+	if errs == nil || len(errs) != 0 {
+		t.Fatalf("Expected empty error history, got %v", errs)
+	}
+
+	// Fail twice, then succeed
+	for _, msg := range []string{"timeout", "connection reset"} {
+		service.TransitionState(ctx, job.ID, state.SCHEDULED)
+		service.TransitionState(ctx, job.ID, state.RUNNING)
+		service.HandleFailure(ctx, job.ID, errors.New(msg))
+	}
+	service.TransitionState(ctx, job.ID, state.SCHEDULED)
+	service.TransitionState(ctx, job.ID, state.RUNNING)
+	service.TransitionState(ctx, job.ID, state.SUCCEEDED)
+
+	errs, err = service.ListJobErrors(ctx, job.ID)
+	if err != nil {
+		t.Fatalf("ListJobErrors failed: %v", err)
+	}
+	if len(errs) != 2 {
+		t.Fatalf("Expected 2 errors, got %d", len(errs))
+	}
+	if errs[0].Attempt != 1 || errs[0].Error != "timeout" {
+		t.Errorf("First error = attempt %d %q, want attempt 1 \"timeout\"", errs[0].Attempt, errs[0].Error)
+	}
+	if errs[1].Attempt != 2 || errs[1].Error != "connection reset" {
+		t.Errorf("Second error = attempt %d %q, want attempt 2 \"connection reset\"", errs[1].Attempt, errs[1].Error)
+	}
+}
+
+func TestListJobErrors_NotFound(t *testing.T) {
+	service := setupTestService()
+	ctx := context.Background()
+
+	if _, err := service.ListJobErrors(ctx, "nonexistent"); err == nil {
+		t.Error("Expected error for nonexistent job")
+	}
+}
+
 func TestCancelJob(t *testing.T) {
 	service := setupTestService()
 	ctx := context.Background()
@@ -304,6 +871,11 @@ func TestCancelJob(t *testing.T) {
 	if updated.CompletedAt == nil {
 		t.Error("CompletedAt should be set after cancellation")
 	}
+
+	// Verify the cancellation reason was recorded
+	if updated.LastTransitionReason == nil || *updated.LastTransitionReason != "user cancelled via API" {
+		t.Errorf("LastTransitionReason = %v, want \"user cancelled via API\"", updated.LastTransitionReason)
+	}
 }
 
 func TestCancelJob_AlreadyTerminal(t *testing.T) {
@@ -324,6 +896,216 @@ func TestCancelJob_AlreadyTerminal(t *testing.T) {
 	}
 }
 
+func TestRequeueJob_AdditionalAttempts(t *testing.T) {
+	service := setupTestService()
+	ctx := context.Background()
+
+	// Create job and exhaust all 3 attempts
+	payload, _ := json.Marshal(map[string]string{"test": "data"})
+	job, _ := service.CreateJob(ctx, "test_job", payload)
+	service.TransitionState(ctx, job.ID, state.SCHEDULED)
+	service.TransitionState(ctx, job.ID, state.RUNNING)
+	job.Attempt = 3
+	service.repo.Update(ctx, job)
+	service.HandleFailure(ctx, job.ID, errors.New("permanent error"))
+
+	failed, _ := service.GetJob(ctx, job.ID)
+	if failed.State != state.FAILED {
+		t.Fatalf("State = %s, want FAILED before requeue", failed.State)
+	}
+
+	// Requeue with 2 extra attempts
+	requeued, err := service.RequeueJob(ctx, job.ID, 2)
+	if err != nil {
+		t.Fatalf("RequeueJob failed: %v", err)
+	}
+
+	if requeued.State != state.PENDING {
+		t.Errorf("State = %s, want PENDING", requeued.State)
+	}
+	if requeued.MaxAttempts != 5 {
+		t.Errorf("MaxAttempts = %d, want 5", requeued.MaxAttempts)
+	}
+	if requeued.Attempt != 1 {
+		t.Errorf("Attempt = %d, want 1", requeued.Attempt)
+	}
+	if !requeued.CanRetry() {
+		t.Error("Requeued job should be retryable")
+	}
+	if requeued.LastError != nil || requeued.CompletedAt != nil {
+		t.Error("Requeued job should have error and CompletedAt cleared")
+	}
+}
+
+func TestRequeueJob_NotFailed(t *testing.T) {
+	service := setupTestService()
+	ctx := context.Background()
+
+	payload, _ := json.Marshal(map[string]string{"test": "data"})
+	job, _ := service.CreateJob(ctx, "test_job", payload)
+
+	if _, err := service.RequeueJob(ctx, job.ID, 0); err == nil {
+		t.Error("Expected error when requeueing a PENDING job")
+	}
+}
+
+func TestRequeueJob_NegativeAttempts(t *testing.T) {
+	service := setupTestService()
+	ctx := context.Background()
+
+	if _, err := service.RequeueJob(ctx, "any", -1); err == nil {
+		t.Error("Expected error for negative additional attempts")
+	}
+}
+
+func TestRequeueJob_MaxAttemptsCap(t *testing.T) {
+	service := setupTestService()
+	ctx := context.Background()
+	limit := DefaultRetryConfig().MaxAllowedAttempts
+
+	job, _ := service.CreateJob(ctx, "test_job", []byte(`{}`))
+	service.TransitionState(ctx, job.ID, state.SCHEDULED)
+	service.TransitionState(ctx, job.ID, state.RUNNING)
+	service.ForceFail(ctx, job.ID, "broken")
+
+	// One over the cap is rejected and leaves the job FAILED
+	if _, err := service.RequeueJob(ctx, job.ID, limit-job.MaxAttempts+1); err == nil {
+		t.Fatal("Expected error when requeueing past the max attempts cap")
+	}
+	if failed, _ := service.GetJob(ctx, job.ID); failed.State != state.FAILED || failed.MaxAttempts != job.MaxAttempts {
+		t.Fatalf("Job = %s with max attempts %d, want unchanged", failed.State, failed.MaxAttempts)
+	}
+
+	// Exactly at the cap is allowed
+	requeued, err := service.RequeueJob(ctx, job.ID, limit-job.MaxAttempts)
+	if err != nil {
+		t.Fatalf("RequeueJob at cap failed: %v", err)
+	}
+	if requeued.MaxAttempts != limit {
+		t.Errorf("MaxAttempts = %d, want %d", requeued.MaxAttempts, limit)
+	}
+}
+
+func TestRerunJob_Succeeded(t *testing.T) {
+	ctx := context.Background()
+	repo := repository.NewMemoryJobRepository()
+	service := NewJobService(repo, state.NewStateMachine(), NewULIDGenerator(), DefaultRetryConfig())
+
+	job, _ := service.CreateJob(ctx, "send_report", []byte(`{"to": "ops"}`))
+	service.TransitionState(ctx, job.ID, state.SCHEDULED)
+	service.TransitionState(ctx, job.ID, state.RUNNING)
+	service.TransitionState(ctx, job.ID, state.SUCCEEDED)
+
+	rerun, err := service.RerunJob(ctx, job.ID)
+	if err != nil {
+		t.Fatalf("RerunJob failed: %v", err)
+	}
+	if rerun.State != state.PENDING || rerun.Attempt != 1 || rerun.RerunCount != 1 {
+		t.Errorf("Rerun job = %s attempt %d rerun %d, want PENDING attempt 1 rerun 1", rerun.State, rerun.Attempt, rerun.RerunCount)
+	}
+	if rerun.StartedAt != nil || rerun.CompletedAt != nil {
+		t.Error("Expected lifecycle timestamps to be cleared")
+	}
+
+	claimed, _ := repo.ClaimPendingJobs(ctx, 10, time.Now())
+	if len(claimed) != 1 || claimed[0].ID != job.ID {
+		t.Fatalf("Claimed %d jobs, want the rerun job", len(claimed))
+	}
+	if string(claimed[0].Payload) != `{"to": "ops"}` {
+		t.Errorf("Payload = %s, want the original payload", claimed[0].Payload)
+	}
+}
+
+func TestRerunJob_NotFinished(t *testing.T) {
+	service := setupTestService()
+	ctx := context.Background()
+
+	job, _ := service.CreateJob(ctx, "test_job", []byte(`{}`))
+
+	if _, err := service.RerunJob(ctx, job.ID); err == nil {
+		t.Error("Expected error when rerunning a PENDING job")
+	}
+}
+
+func TestSetMaxAttempts_ExtendsRetryingJob(t *testing.T) {
+	ctx := context.Background()
+	repo := repository.NewMemoryJobRepository()
+	service := NewJobService(repo, state.NewStateMachine(), NewULIDGenerator(), DefaultRetryConfig())
+
+	// First of two attempts fails: the job is RETRYING on its last attempt
+	job, _ := service.CreateJobWithOptions(ctx, "test_job", []byte(`{}`), JobOptions{MaxAttempts: 2})
+	service.TransitionState(ctx, job.ID, state.SCHEDULED)
+	service.TransitionState(ctx, job.ID, state.RUNNING)
+	service.HandleFailure(ctx, job.ID, errors.New("downstream unavailable"))
+
+	if _, err := service.SetMaxAttempts(ctx, job.ID, 1); err == nil {
+		t.Error("Expected error for max attempts below the current attempt")
+	}
+
+	extended, err := service.SetMaxAttempts(ctx, job.ID, 4)
+	if err != nil {
+		t.Fatalf("SetMaxAttempts failed: %v", err)
+	}
+	if extended.MaxAttempts != 4 || extended.State != state.RETRYING {
+		t.Errorf("Job = %s with max attempts %d, want RETRYING with 4", extended.State, extended.MaxAttempts)
+	}
+
+	// The second attempt fails too, but now there are attempts left
+	service.TransitionState(ctx, job.ID, state.SCHEDULED)
+	service.TransitionState(ctx, job.ID, state.RUNNING)
+	service.HandleFailure(ctx, job.ID, errors.New("downstream unavailable"))
+
+	retried, _ := service.GetJob(ctx, job.ID)
+	if retried.State != state.RETRYING || retried.Attempt != 3 {
+		t.Errorf("Job = %s attempt %d, want RETRYING attempt 3", retried.State, retried.Attempt)
+	}
+
+	// Finished jobs are left alone
+	service.CancelJob(ctx, job.ID)
+	if _, err := service.SetMaxAttempts(ctx, job.ID, 10); err == nil {
+		t.Error("Expected error for a finished job")
+	}
+}
+
+func TestForceFail(t *testing.T) {
+	service := setupTestService()
+	ctx := context.Background()
+
+	// Create job and move it to RUNNING
+	payload, _ := json.Marshal(map[string]string{"test": "data"})
+	job, _ := service.CreateJob(ctx, "test_job", payload)
+	service.TransitionState(ctx, job.ID, state.SCHEDULED)
+	service.TransitionState(ctx, job.ID, state.RUNNING)
+
+	failed, err := service.ForceFail(ctx, job.ID, "upstream never responds")
+	if err != nil {
+		t.Fatalf("ForceFail failed: %v", err)
+	}
+
+	if failed.State != state.FAILED {
+		t.Errorf("State = %s, want FAILED", failed.State)
+	}
+	if failed.CompletedAt == nil {
+		t.Error("CompletedAt should be set after force-fail")
+	}
+	if failed.LastError == nil || *failed.LastError != "upstream never responds" {
+		t.Errorf("LastError = %v, want \"upstream never responds\"", failed.LastError)
+	}
+}
+
+func TestForceFail_InvalidState(t *testing.T) {
+	service := setupTestService()
+	ctx := context.Background()
+
+	// PENDING jobs should be cancelled instead
+	payload, _ := json.Marshal(map[string]string{"test": "data"})
+	job, _ := service.CreateJob(ctx, "test_job", payload)
+
+	if _, err := service.ForceFail(ctx, job.ID, "stuck"); err == nil {
+		t.Error("Expected error when force-failing a PENDING job")
+	}
+}
+
 func TestListJobsByState(t *testing.T) {
 	service := setupTestService()
 	ctx := context.Background()
@@ -390,3 +1172,217 @@ func TestCalculateBackoff(t *testing.T) {
 		})
 	}
 }
+
+func TestRecurringJob_CompletionSpawnsNextOccurrence(t *testing.T) {
+	ctx := context.Background()
+	repo := repository.NewMemoryJobRepository()
+	service := NewJobService(repo, state.NewStateMachine(), NewULIDGenerator(), DefaultRetryConfig())
+	fakeClock := clock.NewFakeClock(time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC))
+	service.SetClock(fakeClock)
+
+	job, err := service.CreateJobWithOptions(ctx, "nightly_cleanup", []byte(`{}`), JobOptions{Schedule: "0 3 * * *"})
+	if err != nil {
+		t.Fatalf("CreateJobWithOptions failed: %v", err)
+	}
+
+	// First run waits for the next tick
+	wantFirst := time.Date(2026, 1, 2, 3, 0, 0, 0, time.UTC)
+	if job.RunAt == nil || !job.RunAt.Equal(wantFirst) {
+		t.Fatalf("RunAt = %v, want %v", job.RunAt, wantFirst)
+	}
+
+	// Run it at 03:00 and succeed
+	fakeClock.Advance(15 * time.Hour)
+	service.TransitionState(ctx, job.ID, state.SCHEDULED)
+	service.TransitionState(ctx, job.ID, state.RUNNING)
+	if err := service.TransitionState(ctx, job.ID, state.SUCCEEDED); err != nil {
+		t.Fatalf("TransitionState failed: %v", err)
+	}
+
+	pending, _ := service.ListJobsByState(ctx, state.PENDING, 10)
+	if len(pending) != 1 {
+		t.Fatalf("Expected 1 next occurrence, got %d", len(pending))
+	}
+
+	next := pending[0]
+	wantNext := time.Date(2026, 1, 3, 3, 0, 0, 0, time.UTC)
+	if next.RunAt == nil || !next.RunAt.Equal(wantNext) {
+		t.Errorf("Next RunAt = %v, want %v", next.RunAt, wantNext)
+	}
+	if next.ParentID == nil || *next.ParentID != job.ID {
+		t.Errorf("Next ParentID = %v, want %s", next.ParentID, job.ID)
+	}
+	if next.Schedule == nil || *next.Schedule != "0 3 * * *" {
+		t.Errorf("Next Schedule = %v, want \"0 3 * * *\"", next.Schedule)
+	}
+	if next.Type != job.Type || next.Attempt != 1 {
+		t.Errorf("Next = %s attempt %d, want %s attempt 1", next.Type, next.Attempt, job.Type)
+	}
+
+	// The occurrence after that still points at the first job
+	service.TransitionState(ctx, next.ID, state.SCHEDULED)
+	service.TransitionState(ctx, next.ID, state.RUNNING)
+	service.ForceFail(ctx, next.ID, "stuck")
+
+	pending, _ = service.ListJobsByState(ctx, state.PENDING, 10)
+	if len(pending) != 1 || pending[0].ParentID == nil || *pending[0].ParentID != job.ID {
+		t.Errorf("Expected a third occurrence linked to %s, got %+v", job.ID, pending)
+	}
+}
+
+func TestRecurringJob_CancelEndsSeries(t *testing.T) {
+	ctx := context.Background()
+	repo := repository.NewMemoryJobRepository()
+	service := NewJobService(repo, state.NewStateMachine(), NewULIDGenerator(), DefaultRetryConfig())
+
+	job, _ := service.CreateJobWithOptions(ctx, "nightly_cleanup", []byte(`{}`), JobOptions{Schedule: "@daily"})
+	if err := service.CancelJob(ctx, job.ID); err != nil {
+		t.Fatalf("CancelJob failed: %v", err)
+	}
+
+	if count, _ := repo.CountByState(ctx, state.PENDING); count != 0 {
+		t.Errorf("Expected no next occurrence after cancel, got %d pending", count)
+	}
+}
+
+func TestRecurringJob_InvalidSchedule(t *testing.T) {
+	service := NewJobService(newMockRepository(), state.NewStateMachine(), NewULIDGenerator(), DefaultRetryConfig())
+
+	err := service.ValidateJob("nightly_cleanup", []byte(`{}`), JobOptions{Schedule: "every night"})
+	if err == nil {
+		t.Error("Expected error for invalid schedule")
+	}
+}
+
+func TestCreateJob_LargePayloadRoundTrip(t *testing.T) {
+	ctx := context.Background()
+	repo := repository.NewMemoryJobRepository()
+	service := NewJobService(repo, state.NewStateMachine(), NewULIDGenerator(), DefaultRetryConfig())
+
+	blobs, err := blobstore.NewFSBlobStore(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewFSBlobStore failed: %v", err)
+	}
+	service.SetBlobStore(blobs, 1024)
+
+	large, _ := json.Marshal(map[string]string{"data": strings.Repeat("x", 4096)})
+	job, err := service.CreateJob(ctx, "process_video", large)
+	if err != nil {
+		t.Fatalf("CreateJob failed: %v", err)
+	}
+
+	// Only the reference is kept in the repository
+	stored, _ := repo.GetByID(ctx, job.ID)
+	if stored.PayloadRef == nil || len(stored.Payload) != 0 {
+		t.Fatalf("Stored job has payload %d bytes, ref %v; want external payload", len(stored.Payload), stored.PayloadRef)
+	}
+
+	payload, err := service.LoadPayload(ctx, stored)
+	if err != nil {
+		t.Fatalf("LoadPayload failed: %v", err)
+	}
+	if string(payload) != string(large) {
+		t.Errorf("Loaded %d bytes, want the original %d bytes", len(payload), len(large))
+	}
+
+	// Small payloads stay inline
+	small, _ := service.CreateJob(ctx, "process_video", []byte(`{"data": "x"}`))
+	stored, _ = repo.GetByID(ctx, small.ID)
+	if stored.PayloadRef != nil || string(stored.Payload) != `{"data": "x"}` {
+		t.Errorf("Small payload should stay inline, got ref %v payload %q", stored.PayloadRef, stored.Payload)
+	}
+}
+
+func TestNextRetryDelay(t *testing.T) {
+	ctx := context.Background()
+	config := RetryConfig{
+		BaseDelay: 2 * time.Second,
+		MaxDelay:  time.Minute,
+	}
+	service := NewJobService(repository.NewMemoryJobRepository(), state.NewStateMachine(), NewULIDGenerator(), config)
+	fakeClock := clock.NewFakeClock(time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC))
+	service.SetClock(fakeClock)
+
+	job, _ := service.CreateJob(ctx, "test", []byte(`{}`))
+
+	// Not retrying yet
+	if _, err := service.NextRetryDelay(ctx, job.ID); err == nil {
+		t.Error("Expected error for a PENDING job")
+	}
+
+	for attempt := 2; attempt <= 3; attempt++ {
+		service.TransitionState(ctx, job.ID, state.SCHEDULED)
+		service.TransitionState(ctx, job.ID, state.RUNNING)
+		service.HandleFailure(ctx, job.ID, errors.New("boom"))
+
+		delay, err := service.NextRetryDelay(ctx, job.ID)
+		if err != nil {
+			t.Fatalf("NextRetryDelay failed: %v", err)
+		}
+		if want := config.CalculateBackoff(attempt); delay != want {
+			t.Errorf("At attempt %d: delay = %v, want %v", attempt, delay, want)
+		}
+
+		// The configured delay, so it doesn't count down as time passes
+		fakeClock.Advance(time.Second)
+		if delay, _ = service.NextRetryDelay(ctx, job.ID); delay != config.CalculateBackoff(attempt) {
+			t.Errorf("At attempt %d + 1s: delay = %v, want %v", attempt, delay, config.CalculateBackoff(attempt))
+		}
+		fakeClock.Advance(time.Hour)
+	}
+}
+
+func TestRecoverInterruptedJobs(t *testing.T) {
+	ctx := context.Background()
+	repo := repository.NewMemoryJobRepository()
+	// No backoff, so recovered retries are claimable right away
+	service := NewJobService(repo, state.NewStateMachine(), NewULIDGenerator(), RetryConfig{})
+
+	// Jobs left behind by a process that died mid-flight
+	orphan := func(s state.State, attempt, maxAttempts int) *model.Job {
+		job, err := service.CreateJobWithOptions(ctx, "test_job", []byte(`{}`), JobOptions{MaxAttempts: maxAttempts})
+		if err != nil {
+			t.Fatalf("CreateJobWithOptions failed: %v", err)
+		}
+		job.State = s
+		job.Attempt = attempt
+		if err := repo.Update(ctx, job); err != nil {
+			t.Fatalf("Update failed: %v", err)
+		}
+		return job
+	}
+	scheduled := orphan(state.SCHEDULED, 1, 3)
+	running := orphan(state.RUNNING, 1, 3)
+	exhausted := orphan(state.RUNNING, 3, 3)
+
+	result, err := service.RecoverInterruptedJobs(ctx)
+	if err != nil {
+		t.Fatalf("RecoverInterruptedJobs failed: %v", err)
+	}
+	if want := (RecoveryResult{Requeued: 1, Retried: 1, Failed: 1}); result != want {
+		t.Errorf("result = %+v, want %+v", result, want)
+	}
+
+	claimed, err := repo.ClaimPendingJobs(ctx, 10, time.Now())
+	if err != nil {
+		t.Fatalf("ClaimPendingJobs failed: %v", err)
+	}
+	ids := map[string]bool{}
+	for _, job := range claimed {
+		ids[job.ID] = true
+	}
+	if len(claimed) != 2 || !ids[scheduled.ID] || !ids[running.ID] {
+		t.Errorf("Claimed %v, want %s and %s", ids, scheduled.ID, running.ID)
+	}
+
+	// The interrupted run used an attempt; the never-started one didn't
+	if job, _ := repo.GetByID(ctx, scheduled.ID); job.Attempt != 1 {
+		t.Errorf("Scheduled job attempt = %d, want 1", job.Attempt)
+	}
+	if job, _ := repo.GetByID(ctx, running.ID); job.Attempt != 2 || job.LastError == nil || *job.LastError != ErrInterrupted.Error() {
+		t.Errorf("Running job attempt = %d, last error %v; want 2, %q", job.Attempt, job.LastError, ErrInterrupted)
+	}
+	if job, _ := repo.GetByID(ctx, exhausted.ID); job.State != state.FAILED {
+		t.Errorf("Exhausted job state = %s, want FAILED", job.State)
+	}
+}
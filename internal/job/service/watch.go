@@ -0,0 +1,94 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/dipak0000812/orchestrix/internal/job/model"
+)
+
+// waitPollInterval is how often WaitForJob re-reads a job between
+// notifications. Notifications only cover jobs finished by this process;
+// polling catches the ones finished elsewhere.
+const waitPollInterval = time.Second
+
+// WaitForJob blocks until the job reaches a terminal state or ctx ends.
+// It returns the job as last read; on ctx expiry that is the job's
+// current, non-terminal state together with ctx.Err().
+func (s *JobService) WaitForJob(ctx context.Context, id string) (*model.Job, error) {
+	// Subscribe before the first read so a finish in between isn't missed
+	done := s.watch(id)
+	defer s.unwatch(id, done)
+
+	ticker := time.NewTicker(waitPollInterval)
+	defer ticker.Stop()
+
+	for {
+		job, err := s.GetJob(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		if job.IsTerminal() {
+			return job, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return job, ctx.Err()
+		case <-done:
+		case <-ticker.C:
+		}
+	}
+}
+
+// watch registers interest in a job finishing. The returned channel
+// receives a value each time the job is saved in a terminal state.
+func (s *JobService) watch(id string) chan struct{} {
+	ch := make(chan struct{}, 1)
+
+	s.watchMu.Lock()
+	defer s.watchMu.Unlock()
+	if s.watchers == nil {
+		s.watchers = make(map[string][]chan struct{})
+	}
+	s.watchers[id] = append(s.watchers[id], ch)
+	return ch
+}
+
+// unwatch removes a channel registered by watch.
+func (s *JobService) unwatch(id string, ch chan struct{}) {
+	s.watchMu.Lock()
+	defer s.watchMu.Unlock()
+
+	chans := s.watchers[id]
+	for i, c := range chans {
+		if c == ch {
+			chans = append(chans[:i], chans[i+1:]...)
+			break
+		}
+	}
+	if len(chans) == 0 {
+		delete(s.watchers, id)
+	} else {
+		s.watchers[id] = chans
+	}
+}
+
+// notifyIfDone wakes WaitForJob callers once job has been saved in a
+// terminal state. Must be called after the write is committed, so a
+// woken waiter reads the final state.
+func (s *JobService) notifyIfDone(job *model.Job) {
+	if !job.IsTerminal() {
+		return
+	}
+
+	s.watchMu.Lock()
+	defer s.watchMu.Unlock()
+	for _, ch := range s.watchers[job.ID] {
+		// Buffered: a waiter that's busy re-reading sees it next loop
+		select {
+		case ch <- struct{}{}:
+		default:
+		}
+	}
+}
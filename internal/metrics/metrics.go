@@ -7,44 +7,127 @@ import (
 
 // Metrics holds all Prometheus metrics.
 type Metrics struct {
-	JobsCreated   prometheus.Counter
-	JobsSucceeded prometheus.Counter
-	JobsFailed    prometheus.Counter
-	JobsCancelled prometheus.Counter
-	JobDuration   prometheus.Histogram
-	QueueDepth    prometheus.Gauge
-	HTTPRequests  *prometheus.CounterVec
+	JobsCreated         prometheus.Counter
+	JobsSucceeded       prometheus.Counter
+	JobsFailed          prometheus.Counter
+	JobsCancelled       prometheus.Counter
+	JobsExhausted       *prometheus.CounterVec
+	JobsTimedOut        prometheus.Counter
+	JobDuration         prometheus.Histogram
+	JobAttempts         prometheus.Histogram
+	QueueWaitDuration   prometheus.Histogram
+	StateDuration       *prometheus.HistogramVec
+	QueueDepth          prometheus.Gauge
+	JobsRunning         prometheus.Gauge
+	ChannelFull         prometheus.Counter
+	SendBlockDuration   prometheus.Histogram
+	SchedulerEmptyPolls prometheus.Counter
+	SchedulerContention prometheus.Counter
+	JobsReclaimed       prometheus.Counter
+	WorkerIdleSeconds   prometheus.Counter
+	WorkerBusySeconds   prometheus.Counter
+	HTTPRequests        *prometheus.CounterVec
 }
 
-// NewMetrics creates and registers all metrics.
-func NewMetrics() *Metrics {
+// NewMetrics creates all metrics and registers them with reg.
+// A nil reg uses prometheus.DefaultRegisterer, which is what /metrics serves.
+// Pass prometheus.NewRegistry() for an isolated set, e.g. in tests.
+func NewMetrics(reg prometheus.Registerer) *Metrics {
+	if reg == nil {
+		reg = prometheus.DefaultRegisterer
+	}
+	factory := promauto.With(reg)
+
 	return &Metrics{
-		JobsCreated: promauto.NewCounter(prometheus.CounterOpts{
+		JobsCreated: factory.NewCounter(prometheus.CounterOpts{
 			Name: "orchestrix_jobs_created_total",
 			Help: "Total number of jobs created",
 		}),
-		JobsSucceeded: promauto.NewCounter(prometheus.CounterOpts{
+		JobsSucceeded: factory.NewCounter(prometheus.CounterOpts{
 			Name: "orchestrix_jobs_succeeded_total",
 			Help: "Total number of jobs that succeeded",
 		}),
-		JobsFailed: promauto.NewCounter(prometheus.CounterOpts{
+		JobsFailed: factory.NewCounter(prometheus.CounterOpts{
 			Name: "orchestrix_jobs_failed_total",
 			Help: "Total number of jobs that failed",
 		}),
-		JobsCancelled: promauto.NewCounter(prometheus.CounterOpts{
+		JobsCancelled: factory.NewCounter(prometheus.CounterOpts{
 			Name: "orchestrix_jobs_cancelled_total",
 			Help: "Total number of jobs cancelled",
 		}),
-		JobDuration: promauto.NewHistogram(prometheus.HistogramOpts{
+		JobsExhausted: factory.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "orchestrix_jobs_exhausted_total",
+				Help: "Total number of jobs that failed permanently after using all their retries, by type",
+			},
+			[]string{"type"},
+		),
+		JobsTimedOut: factory.NewCounter(prometheus.CounterOpts{
+			Name: "orchestrix_jobs_timed_out_total",
+			Help: "Total number of job executions cut off by their timeout",
+		}),
+		JobDuration: factory.NewHistogram(prometheus.HistogramOpts{
 			Name:    "orchestrix_job_duration_seconds",
 			Help:    "Job execution duration in seconds",
 			Buckets: prometheus.DefBuckets,
 		}),
-		QueueDepth: promauto.NewGauge(prometheus.GaugeOpts{
+		JobAttempts: factory.NewHistogram(prometheus.HistogramOpts{
+			Name:    "orchestrix_job_attempts",
+			Help:    "Attempts a job took to reach SUCCEEDED or FAILED",
+			Buckets: prometheus.LinearBuckets(1, 1, 10), // 1 to 10 attempts
+		}),
+		QueueWaitDuration: factory.NewHistogram(prometheus.HistogramOpts{
+			Name:    "orchestrix_job_queue_wait_seconds",
+			Help:    "Time from job creation until execution starts, in seconds",
+			Buckets: prometheus.DefBuckets,
+		}),
+		StateDuration: factory.NewHistogramVec(
+			prometheus.HistogramOpts{
+				Name:    "orchestrix_job_state_duration_seconds",
+				Help:    "Time a job spent in a state before leaving it, in seconds",
+				Buckets: prometheus.ExponentialBuckets(0.01, 4, 10), // 10ms to ~44m
+			},
+			[]string{"state"},
+		),
+		QueueDepth: factory.NewGauge(prometheus.GaugeOpts{
 			Name: "orchestrix_queue_depth",
 			Help: "Current number of jobs in queue",
 		}),
-		HTTPRequests: promauto.NewCounterVec(
+		JobsRunning: factory.NewGauge(prometheus.GaugeOpts{
+			Name: "orchestrix_jobs_running",
+			Help: "Current number of jobs executing in workers",
+		}),
+		ChannelFull: factory.NewCounter(prometheus.CounterOpts{
+			Name: "orchestrix_job_channel_full_total",
+			Help: "Total number of times a send to the job channel found the buffer full",
+		}),
+		SendBlockDuration: factory.NewHistogram(prometheus.HistogramOpts{
+			Name:    "orchestrix_scheduler_send_block_seconds",
+			Help:    "Time the scheduler spent blocked sending a job to a full channel",
+			Buckets: prometheus.ExponentialBuckets(0.001, 4, 8), // 1ms to ~16s
+		}),
+		SchedulerEmptyPolls: factory.NewCounter(prometheus.CounterOpts{
+			Name: "orchestrix_scheduler_empty_polls_total",
+			Help: "Total number of scheduler polls that claimed no jobs",
+		}),
+		SchedulerContention: factory.NewCounter(prometheus.CounterOpts{
+			Name: "orchestrix_scheduler_contention_total",
+			Help: "Total number of empty polls while claimable jobs existed (claimed by other schedulers)",
+		}),
+		// Keeps its original name so existing dashboards still work
+		JobsReclaimed: factory.NewCounter(prometheus.CounterOpts{
+			Name: "orchestrix_jobs_reaped_total",
+			Help: "Total number of stale SCHEDULED jobs the reaper reclaimed and returned to the queue",
+		}),
+		WorkerIdleSeconds: factory.NewCounter(prometheus.CounterOpts{
+			Name: "orchestrix_worker_idle_seconds_total",
+			Help: "Total time workers spent waiting for jobs, in seconds",
+		}),
+		WorkerBusySeconds: factory.NewCounter(prometheus.CounterOpts{
+			Name: "orchestrix_worker_busy_seconds_total",
+			Help: "Total time workers spent processing jobs, in seconds",
+		}),
+		HTTPRequests: factory.NewCounterVec(
 			prometheus.CounterOpts{
 				Name: "orchestrix_http_requests_total",
 				Help: "Total HTTP requests by endpoint and status",
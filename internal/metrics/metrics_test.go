@@ -0,0 +1,44 @@
+package metrics
+
+import (
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestNewMetrics_IndependentRegistries(t *testing.T) {
+	// Would panic with a duplicate registration on a shared registry
+	first := NewMetrics(prometheus.NewRegistry())
+	second := NewMetrics(prometheus.NewRegistry())
+
+	first.JobsCreated.Inc()
+
+	if got := testutil.ToFloat64(first.JobsCreated); got != 1 {
+		t.Errorf("first JobsCreated = %v, want 1", got)
+	}
+	if got := testutil.ToFloat64(second.JobsCreated); got != 0 {
+		t.Errorf("second JobsCreated = %v, want 0", got)
+	}
+}
+
+func TestNewMetrics_RegistersWithRegisterer(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	m := NewMetrics(reg)
+	m.JobsSucceeded.Inc()
+
+	families, err := reg.Gather()
+	if err != nil {
+		t.Fatalf("Gather failed: %v", err)
+	}
+
+	found := false
+	for _, family := range families {
+		if family.GetName() == "orchestrix_jobs_succeeded_total" {
+			found = true
+		}
+	}
+	if !found {
+		t.Error("Expected orchestrix_jobs_succeeded_total in the given registry")
+	}
+}
@@ -0,0 +1,125 @@
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+// BackgroundTask is one run of a periodic task. It should return promptly
+// once ctx is done.
+type BackgroundTask func(ctx context.Context)
+
+// backgroundTask is a task registered with a BackgroundRunner.
+type backgroundTask struct {
+	name     string
+	interval time.Duration
+	run      BackgroundTask
+}
+
+// BackgroundRunner runs named periodic tasks, each on its own interval,
+// so features that need housekeeping (reaping, cleanup, health checks)
+// share one lifecycle instead of each managing a goroutine.
+//
+// A task never overlaps itself: a run that takes longer than the
+// interval delays the next one. A panicking run is logged and the task
+// carries on at its next tick.
+type BackgroundRunner struct {
+	mu      sync.Mutex
+	tasks   []backgroundTask
+	started bool
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// NewBackgroundRunner creates a runner with no tasks.
+func NewBackgroundRunner() *BackgroundRunner {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	return &BackgroundRunner{
+		ctx:    ctx,
+		cancel: cancel,
+	}
+}
+
+// Register adds a task that runs every interval once the runner starts.
+// Names must be unique and intervals positive. Call it before Start.
+func (r *BackgroundRunner) Register(name string, interval time.Duration, task BackgroundTask) error {
+	if interval <= 0 {
+		return fmt.Errorf("background task %s: interval must be positive, got %v", name, interval)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.started {
+		return fmt.Errorf("background task %s: runner already started", name)
+	}
+	for _, t := range r.tasks {
+		if t.name == name {
+			return fmt.Errorf("background task %s: already registered", name)
+		}
+	}
+
+	r.tasks = append(r.tasks, backgroundTask{name: name, interval: interval, run: task})
+	return nil
+}
+
+// Start begins running every registered task. The first run of each
+// happens one interval after Start.
+func (r *BackgroundRunner) Start() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.started {
+		return
+	}
+	r.started = true
+
+	for _, t := range r.tasks {
+		r.wg.Add(1)
+		go r.loop(t)
+	}
+	slog.Info("Background runner started", "tasks", len(r.tasks))
+}
+
+// Stop cancels the tasks' context and waits for in-progress runs to return.
+func (r *BackgroundRunner) Stop() {
+	slog.Info("Background runner stopping...")
+	r.cancel()
+	r.wg.Wait()
+	slog.Info("Background runner stopped")
+}
+
+// loop runs one task every interval until the runner stops.
+func (r *BackgroundRunner) loop(t backgroundTask) {
+	defer r.wg.Done()
+
+	ticker := time.NewTicker(t.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			r.runOnce(t)
+
+		case <-r.ctx.Done():
+			return
+		}
+	}
+}
+
+// runOnce runs a task, recovering from a panic so the task keeps its schedule.
+func (r *BackgroundRunner) runOnce(t backgroundTask) {
+	defer func() {
+		if p := recover(); p != nil {
+			slog.Error("PANIC in background task", "task", t.name, "panic", p)
+		}
+	}()
+
+	t.run(r.ctx)
+}
@@ -0,0 +1,91 @@
+package scheduler
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestBackgroundRunner_RunsAtIntervalUntilStopped(t *testing.T) {
+	runner := NewBackgroundRunner()
+
+	var runs atomic.Int64
+	var taskCtx atomic.Value
+	err := runner.Register("counter", 10*time.Millisecond, func(ctx context.Context) {
+		taskCtx.Store(ctx)
+		runs.Add(1)
+	})
+	if err != nil {
+		t.Fatalf("Register failed: %v", err)
+	}
+
+	// Nothing runs before Start
+	time.Sleep(30 * time.Millisecond)
+	if got := runs.Load(); got != 0 {
+		t.Fatalf("Task ran %d times before Start", got)
+	}
+
+	runner.Start()
+	start := time.Now()
+	for runs.Load() < 3 && time.Since(start) < time.Second {
+		time.Sleep(5 * time.Millisecond)
+	}
+	if got := runs.Load(); got < 3 {
+		t.Fatalf("Task ran %d times in %v, want at least 3 at a 10ms interval", got, time.Since(start))
+	}
+
+	runner.Stop()
+	if ctx := taskCtx.Load().(context.Context); ctx.Err() == nil {
+		t.Error("Task context not cancelled by Stop")
+	}
+
+	stoppedAt := runs.Load()
+	time.Sleep(50 * time.Millisecond)
+	if got := runs.Load(); got != stoppedAt {
+		t.Errorf("Task ran %d more times after Stop", got-stoppedAt)
+	}
+}
+
+func TestBackgroundRunner_RecoversFromPanic(t *testing.T) {
+	runner := NewBackgroundRunner()
+
+	var runs atomic.Int64
+	runner.Register("flaky", 10*time.Millisecond, func(ctx context.Context) {
+		if runs.Add(1) == 1 {
+			panic("boom")
+		}
+	})
+
+	runner.Start()
+	defer runner.Stop()
+
+	deadline := time.Now().Add(time.Second)
+	for runs.Load() < 3 && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	if got := runs.Load(); got < 3 {
+		t.Errorf("Task ran %d times, want it to keep running after a panic", got)
+	}
+}
+
+func TestBackgroundRunner_RegisterValidation(t *testing.T) {
+	runner := NewBackgroundRunner()
+	noop := func(ctx context.Context) {}
+
+	if err := runner.Register("zero", 0, noop); err == nil {
+		t.Error("Expected an error for a zero interval")
+	}
+	if err := runner.Register("task", time.Second, noop); err != nil {
+		t.Fatalf("Register failed: %v", err)
+	}
+	if err := runner.Register("task", time.Second, noop); err == nil {
+		t.Error("Expected an error for a duplicate name")
+	}
+
+	runner.Start()
+	defer runner.Stop()
+	if err := runner.Register("late", time.Second, noop); err == nil {
+		t.Error("Expected an error registering after Start")
+	}
+}
@@ -0,0 +1,167 @@
+package scheduler
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/dipak0000812/orchestrix/internal/clock"
+	"github.com/dipak0000812/orchestrix/internal/job/model"
+	"github.com/dipak0000812/orchestrix/internal/job/repository"
+	"github.com/dipak0000812/orchestrix/internal/job/state"
+	"github.com/dipak0000812/orchestrix/internal/metrics"
+)
+
+// Reaper returns jobs stuck in SCHEDULED back to the queue.
+//
+// A job can get stuck if it was claimed but never reached a worker,
+// e.g. the send to the job channel timed out or the process crashed
+// between claim and run. Without the reaper those jobs sit in SCHEDULED forever.
+//
+// The reaper has no loop of its own; register Run with a BackgroundRunner
+// at Interval.
+type Reaper struct {
+	repository repository.JobRepository
+	interval   time.Duration
+	staleAfter time.Duration
+	metrics    *metrics.Metrics
+	clock      clock.Clock
+
+	// Optional alert on a burst of requeues; see SetRequeueAlert.
+	alertThreshold int
+	alertWindow    time.Duration
+	alertFn        func(count int)
+	alerted        bool
+	requeueTimes   []time.Time
+}
+
+// NewReaper creates a reaper that checks every interval for jobs
+// that have been SCHEDULED for longer than staleAfter.
+func NewReaper(
+	jobRepository repository.JobRepository,
+	interval time.Duration,
+	staleAfter time.Duration,
+	m *metrics.Metrics,
+) *Reaper {
+	return &Reaper{
+		repository: jobRepository,
+		interval:   interval,
+		staleAfter: staleAfter,
+		metrics:    m,
+		clock:      clock.Real(),
+	}
+}
+
+// SetClock replaces the time source used to decide which jobs are stale.
+// Intended for tests; the default is the system clock.
+func (r *Reaper) SetClock(c clock.Clock) {
+	r.clock = c
+}
+
+// SetRequeueAlert logs a warning and calls fn (if non-nil) when more than
+// threshold jobs were requeued within the trailing window. Frequent
+// requeues usually mean workers are crashing or hanging. The alert fires
+// once per burst, from Run, and again only after the count has dropped
+// back to the threshold. A threshold <= 0 disables it.
+// Call it before the reaper first runs.
+func (r *Reaper) SetRequeueAlert(threshold int, window time.Duration, fn func(count int)) {
+	r.alertThreshold = threshold
+	r.alertWindow = window
+	r.alertFn = fn
+}
+
+// Interval is how often the reaper should run.
+func (r *Reaper) Interval() time.Duration {
+	return r.interval
+}
+
+// Run does one reaping pass. It is a BackgroundTask.
+func (r *Reaper) Run(ctx context.Context) {
+	r.reap(ctx)
+}
+
+// reap finds stale SCHEDULED jobs and makes them claimable again.
+// Returns the number of jobs requeued.
+func (r *Reaper) reap(ctx context.Context) int {
+	cutoff := r.clock.Now().Add(-r.staleAfter)
+	jobs, err := r.repository.FindStaleScheduled(ctx, cutoff)
+	if err != nil {
+		slog.Error("Failed to find stale scheduled jobs", "error", err)
+		return 0
+	}
+
+	requeued := r.requeue(ctx, jobs)
+
+	if requeued > 0 {
+		slog.Info("Requeued stale scheduled jobs", "count", requeued)
+	}
+	r.checkRequeueAlert(requeued)
+	return requeued
+}
+
+// checkRequeueAlert records requeued jobs and fires the alert when the
+// count within the window first goes over the threshold.
+func (r *Reaper) checkRequeueAlert(requeued int) {
+	if r.alertThreshold <= 0 {
+		return
+	}
+
+	now := r.clock.Now()
+	for i := 0; i < requeued; i++ {
+		r.requeueTimes = append(r.requeueTimes, now)
+	}
+
+	// Drop requeues that fell out of the window
+	cutoff := now.Add(-r.alertWindow)
+	kept := r.requeueTimes[:0]
+	for _, t := range r.requeueTimes {
+		if t.After(cutoff) {
+			kept = append(kept, t)
+		}
+	}
+	r.requeueTimes = kept
+
+	count := len(r.requeueTimes)
+	if count <= r.alertThreshold {
+		r.alerted = false
+		return
+	}
+	if !r.alerted {
+		r.alerted = true
+		slog.Warn("Stale job requeues over threshold",
+			"count", count, "threshold", r.alertThreshold, "window", r.alertWindow)
+		if r.alertFn != nil {
+			r.alertFn(count)
+		}
+	}
+}
+
+// requeue moves stale jobs back to the state they were claimed from.
+// First attempts go back to PENDING, retries go back to RETRYING.
+// Returns the number of jobs requeued.
+//
+// SCHEDULED -> PENDING/RETRYING isn't a normal lifecycle transition,
+// so this writes through the repository rather than the state machine.
+// The write only applies to jobs still SCHEDULED: one a worker started
+// since FindStaleScheduled is left alone rather than run twice.
+func (r *Reaper) requeue(ctx context.Context, jobs []*model.Job) int {
+	byState := make(map[state.State][]string)
+	for _, job := range jobs {
+		to := unclaimedState(job)
+		byState[to] = append(byState[to], job.ID)
+	}
+
+	reason := "requeued after being scheduled for longer than " + r.staleAfter.String()
+	requeued := 0
+	for to, ids := range byState {
+		updated, err := r.repository.UpdateStateBatch(ctx, ids, state.SCHEDULED, to, reason)
+		if err != nil {
+			slog.Error("Failed to requeue stale jobs", "job_ids", ids, "error", err)
+			continue
+		}
+		requeued += len(updated)
+	}
+
+	r.metrics.JobsReclaimed.Add(float64(requeued))
+	return requeued
+}
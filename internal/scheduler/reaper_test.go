@@ -0,0 +1,178 @@
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/dipak0000812/orchestrix/internal/clock"
+	"github.com/dipak0000812/orchestrix/internal/job/model"
+	"github.com/dipak0000812/orchestrix/internal/job/repository"
+	"github.com/dipak0000812/orchestrix/internal/job/state"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestReaper_RequeuesStaleScheduledJobs(t *testing.T) {
+	ctx := context.Background()
+	repo := repository.NewMemoryJobRepository()
+	m := newTestMetrics()
+
+	// Backdated first attempt: should go back to PENDING
+	stale := newTestJob("job_stale")
+	backdated := time.Now().Add(-time.Hour)
+	stale.ScheduledAt = &backdated
+
+	// Backdated retry: should go back to RETRYING
+	staleRetry := newTestJob("job_stale_retry")
+	staleRetry.Attempt = 2
+	staleRetry.ScheduledAt = &backdated
+
+	// Recently scheduled: should be left alone
+	fresh := newTestJob("job_fresh")
+	now := time.Now()
+	fresh.ScheduledAt = &now
+
+	for _, job := range []*model.Job{stale, staleRetry, fresh} {
+		if err := repo.Create(ctx, job); err != nil {
+			t.Fatalf("Failed to create job: %v", err)
+		}
+	}
+
+	before := testutil.ToFloat64(m.JobsReclaimed)
+
+	reaper := NewReaper(repo, time.Minute, 10*time.Minute, m)
+
+	if got := reaper.reap(ctx); got != 2 {
+		t.Fatalf("reap() = %d, want 2", got)
+	}
+
+	wantStates := map[string]state.State{
+		"job_stale":       state.PENDING,
+		"job_stale_retry": state.RETRYING,
+		"job_fresh":       state.SCHEDULED,
+	}
+	for id, want := range wantStates {
+		job, _ := repo.GetByID(ctx, id)
+		if job.State != want {
+			t.Errorf("Job %s state = %s, want %s", id, job.State, want)
+		}
+	}
+
+	requeued, _ := repo.GetByID(ctx, "job_stale")
+	if requeued.ScheduledAt != nil {
+		t.Error("Expected ScheduledAt to be cleared")
+	}
+	if requeued.LastTransitionReason == nil {
+		t.Error("Expected a transition reason on the requeued job")
+	}
+
+	if got := testutil.ToFloat64(m.JobsReclaimed) - before; got != 2 {
+		t.Errorf("JobsReclaimed incremented by %v, want 2", got)
+	}
+
+	// Requeued jobs are claimable again
+	claimed, err := repo.ClaimPendingJobs(ctx, 10, time.Now())
+	if err != nil {
+		t.Fatalf("ClaimPendingJobs failed: %v", err)
+	}
+	if len(claimed) != 2 {
+		t.Errorf("Claimed %d jobs after reap, want 2", len(claimed))
+	}
+}
+
+// racingRepository starts a job right after the reaper has found it
+// stale, as a worker picking it up at that moment would.
+type racingRepository struct {
+	*repository.MemoryJobRepository
+	startID string
+}
+
+func (r *racingRepository) FindStaleScheduled(ctx context.Context, cutoff time.Time) ([]*model.Job, error) {
+	jobs, err := r.MemoryJobRepository.FindStaleScheduled(ctx, cutoff)
+	if err == nil {
+		r.UpdateState(ctx, r.startID, state.RUNNING)
+	}
+	return jobs, err
+}
+
+func TestReaper_SkipsJobStartedSinceFind(t *testing.T) {
+	ctx := context.Background()
+	repo := &racingRepository{MemoryJobRepository: repository.NewMemoryJobRepository(), startID: "job_started"}
+
+	backdated := time.Now().Add(-time.Hour)
+	for _, id := range []string{"job_stale", "job_started"} {
+		job := newTestJob(id)
+		job.ScheduledAt = &backdated
+		repo.Create(ctx, job)
+	}
+
+	reaper := NewReaper(repo, time.Minute, 10*time.Minute, newTestMetrics())
+	if got := reaper.reap(ctx); got != 1 {
+		t.Errorf("reap() = %d, want 1", got)
+	}
+
+	// The started job must not go back to the queue to be run a second time
+	started, _ := repo.GetByID(ctx, "job_started")
+	if started.State != state.RUNNING {
+		t.Errorf("Started job state = %s, want RUNNING", started.State)
+	}
+	requeued, _ := repo.GetByID(ctx, "job_stale")
+	if requeued.State != state.PENDING {
+		t.Errorf("Stale job state = %s, want PENDING", requeued.State)
+	}
+}
+
+func TestReaper_RequeueAlert(t *testing.T) {
+	ctx := context.Background()
+	repo := repository.NewMemoryJobRepository()
+	fakeClock := clock.NewFakeClock(time.Now())
+
+	reaper := NewReaper(repo, time.Minute, 10*time.Minute, newTestMetrics())
+	reaper.SetClock(fakeClock)
+
+	var alerts []int
+	reaper.SetRequeueAlert(2, time.Hour, func(count int) {
+		alerts = append(alerts, count)
+	})
+
+	// addStale stores n jobs that are already past the stale cutoff
+	addStale := func(prefix string, n int) {
+		backdated := fakeClock.Now().Add(-time.Hour)
+		for i := 0; i < n; i++ {
+			job := newTestJob(fmt.Sprintf("%s_%d", prefix, i))
+			job.ScheduledAt = &backdated
+			repo.Create(ctx, job)
+		}
+	}
+
+	// Two requeues: at the threshold, no alert
+	addStale("job_a", 2)
+	reaper.reap(ctx)
+	if len(alerts) != 0 {
+		t.Fatalf("Alerts after 2 requeues = %v, want none", alerts)
+	}
+
+	// A third within the window goes over
+	addStale("job_b", 1)
+	reaper.reap(ctx)
+	if len(alerts) != 1 || alerts[0] != 3 {
+		t.Fatalf("Alerts = %v, want [3]", alerts)
+	}
+
+	// Still over the threshold: no repeat alert for the same burst
+	addStale("job_c", 1)
+	reaper.reap(ctx)
+	if len(alerts) != 1 {
+		t.Fatalf("Alerts = %v, want a single alert per burst", alerts)
+	}
+
+	// Once the window has passed, the count resets
+	fakeClock.Advance(2 * time.Hour)
+	reaper.reap(ctx)
+	addStale("job_d", 3)
+	reaper.reap(ctx)
+	if len(alerts) != 2 || alerts[1] != 3 {
+		t.Errorf("Alerts = %v, want a second alert of 3", alerts)
+	}
+}
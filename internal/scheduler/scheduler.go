@@ -2,21 +2,74 @@ package scheduler
 
 import (
 	"context"
-	"fmt"
-	"log"
+	"errors"
+	"log/slog"
 	"sync"
+	"sync/atomic"
 	"time"
 
+	"github.com/dipak0000812/orchestrix/internal/clock"
 	"github.com/dipak0000812/orchestrix/internal/job/model"
-	"github.com/dipak0000812/orchestrix/internal/job/repository"
+	"github.com/dipak0000812/orchestrix/internal/job/state"
+	"github.com/dipak0000812/orchestrix/internal/metrics"
 )
 
+// DefaultChannelSize is the job channel buffer used when no size is configured.
+const DefaultChannelSize = 100
+
+// NewJobChannel creates the channel the scheduler uses to hand jobs to workers.
+// Sizes <= 0 fall back to DefaultChannelSize.
+func NewJobChannel(size int) chan *model.Job {
+	if size <= 0 {
+		size = DefaultChannelSize
+	}
+	return make(chan *model.Job, size)
+}
+
+// MinPollInterval is the shortest poll interval NewScheduler accepts;
+// anything shorter would hammer the database.
+const MinPollInterval = 10 * time.Millisecond
+
+// DefaultBatchSize is the claim batch size used when none is configured.
+const DefaultBatchSize = 10
+
+// DefaultSendTimeout bounds how long one poll may block handing its batch to workers.
+const DefaultSendTimeout = 5 * time.Second
+
+// LivenessIntervals is how many poll intervals the scheduler may go
+// without completing a poll before Alive reports it dead.
+const LivenessIntervals = 3
+
+// errSendTimeout is returned by sendToWorkers when the batch deadline fires.
+var errSendTimeout = errors.New("timeout sending job to channel")
+
+// JobClaimer is the subset of the job repository the scheduler needs.
+type JobClaimer interface {
+	ClaimPendingJobs(ctx context.Context, limit int, now time.Time, skipTypes ...string) ([]*model.Job, error)
+	PeekClaimable(ctx context.Context, limit int, now time.Time, skipTypes ...string) ([]*model.Job, error)
+	UpdateStateBatch(ctx context.Context, ids []string, from, to state.State, reason string) ([]string, error)
+}
+
 // Scheduler polls the database for PENDING jobs and schedules them.
 type Scheduler struct {
-	repository   *repository.PostgresJobRepository
+	repository   JobClaimer
 	pollInterval time.Duration
 	batchSize    int
 	jobChannel   chan *model.Job
+	metrics      *metrics.Metrics
+	clock        clock.Clock
+
+	// sendTimeout bounds the time spent sending a whole batch, not each job.
+	sendTimeout time.Duration
+
+	// lastTick is when the loop last completed a poll, in Unix nanoseconds.
+	lastTick atomic.Int64
+
+	// paused stops the loop from claiming jobs while it keeps ticking.
+	paused atomic.Bool
+
+	// pausedTypes lists job types left unclaimed; see SetPausedTypes.
+	pausedTypes func() []string
 
 	ctx    context.Context
 	cancel context.CancelFunc
@@ -24,37 +77,124 @@ type Scheduler struct {
 }
 
 // NewScheduler creates a new scheduler.
+// Out-of-range settings are clamped with a warning rather than rejected:
+// pollInterval below MinPollInterval becomes MinPollInterval and
+// batchSize <= 0 becomes DefaultBatchSize. sendTimeout bounds how long
+// each poll may wait on a full job channel; values <= 0 fall back to
+// DefaultSendTimeout.
 func NewScheduler(
-	jobRepository *repository.PostgresJobRepository,
+	jobRepository JobClaimer,
 	pollInterval time.Duration,
 	batchSize int,
 	jobChannel chan *model.Job,
+	m *metrics.Metrics,
+	sendTimeout time.Duration,
 ) *Scheduler {
 	ctx, cancel := context.WithCancel(context.Background())
 
+	if pollInterval < MinPollInterval {
+		slog.Warn("Poll interval too short, using minimum",
+			"poll_interval", pollInterval, "minimum", MinPollInterval)
+		pollInterval = MinPollInterval
+	}
+	if batchSize <= 0 {
+		slog.Warn("Batch size must be positive, using default",
+			"batch_size", batchSize, "default", DefaultBatchSize)
+		batchSize = DefaultBatchSize
+	}
+	if sendTimeout <= 0 {
+		sendTimeout = DefaultSendTimeout
+	}
+
 	return &Scheduler{
 		repository:   jobRepository,
 		pollInterval: pollInterval,
 		batchSize:    batchSize,
 		jobChannel:   jobChannel,
+		metrics:      m,
+		clock:        clock.Real(),
+		sendTimeout:  sendTimeout,
 		ctx:          ctx,
 		cancel:       cancel,
 	}
 }
 
+// SetClock replaces the time source used to decide which retries are due.
+// Intended for tests; the default is the system clock.
+func (s *Scheduler) SetClock(c clock.Clock) {
+	s.clock = c
+}
+
+// SetPausedTypes makes each poll leave jobs of the types pausedTypes
+// returns in PENDING/RETRYING, so a type paused in the worker pool isn't
+// claimed into SCHEDULED only to be released again.
+// Call it before Start.
+func (s *Scheduler) SetPausedTypes(pausedTypes func() []string) {
+	s.pausedTypes = pausedTypes
+}
+
+// skipTypes returns the job types this poll should leave unclaimed.
+func (s *Scheduler) skipTypes() []string {
+	if s.pausedTypes == nil {
+		return nil
+	}
+	return s.pausedTypes()
+}
+
 // Start begins the scheduling loop.
 func (s *Scheduler) Start() {
+	s.lastTick.Store(time.Now().UnixNano())
 	s.wg.Add(1)
 	go s.run()
-	log.Println("Scheduler started")
+	slog.Info("Scheduler started")
 }
 
 // Stop gracefully stops the scheduler.
 func (s *Scheduler) Stop() {
-	log.Println("Scheduler stopping...")
+	slog.Info("Scheduler stopping...")
 	s.cancel()
 	s.wg.Wait()
-	log.Println("Scheduler stopped")
+	slog.Info("Scheduler stopped")
+}
+
+// LastTick returns when the scheduling loop last completed a poll,
+// or when it started if it hasn't polled yet. Zero before Start.
+func (s *Scheduler) LastTick() time.Time {
+	if last := s.lastTick.Load(); last != 0 {
+		return time.Unix(0, last)
+	}
+	return time.Time{}
+}
+
+// Alive reports whether the scheduler is running and has completed a poll
+// within LivenessIntervals poll intervals. The send timeout is allowed on
+// top, since a poll may legitimately block that long on a full channel.
+func (s *Scheduler) Alive() bool {
+	last := s.LastTick()
+	if last.IsZero() || s.ctx.Err() != nil {
+		return false
+	}
+	return time.Since(last) <= LivenessIntervals*s.pollInterval+s.sendTimeout
+}
+
+// Pause stops claiming new jobs. Jobs already handed to workers are
+// unaffected. The loop keeps ticking, so a paused scheduler stays alive.
+func (s *Scheduler) Pause() {
+	if !s.paused.Swap(true) {
+		slog.Info("Scheduler paused")
+	}
+}
+
+// Resume undoes Pause.
+func (s *Scheduler) Resume() {
+	if s.paused.Swap(false) {
+		slog.Info("Scheduler resumed")
+	}
+}
+
+// Paused reports whether the scheduler is paused.
+func (s *Scheduler) Paused() bool {
+	return s.paused.Load()
 }
 
 // run is the main scheduling loop.
@@ -67,7 +207,10 @@ func (s *Scheduler) run() {
 	for {
 		select {
 		case <-ticker.C:
-			s.pollAndSchedule()
+			if !s.paused.Load() {
+				s.pollAndSchedule()
+			}
+			s.lastTick.Store(time.Now().UnixNano())
 
 		case <-s.ctx.Done():
 			return
@@ -78,39 +221,133 @@ func (s *Scheduler) run() {
 // pollAndSchedule finds and claims PENDING jobs atomically.
 func (s *Scheduler) pollAndSchedule() {
 	// Atomically claim pending jobs (locks + updates state to SCHEDULED)
-	jobs, err := s.repository.ClaimPendingJobs(s.ctx, s.batchSize)
+	jobs, err := s.repository.ClaimPendingJobs(s.ctx, s.batchSize, s.clock.Now(), s.skipTypes()...)
 	if err != nil {
-		log.Printf("Failed to claim pending jobs: %v", err)
+		slog.Error("Failed to claim pending jobs", "error", err)
 		return
 	}
 
 	if len(jobs) == 0 {
+		s.recordEmptyPoll()
 		return // No jobs to schedule
 	}
 
-	log.Printf("Found %d pending jobs", len(jobs))
+	slog.Debug("Found pending jobs", "count", len(jobs))
+
+	// One deadline for the whole batch, so a full channel can't stall
+	// the poll for sendTimeout per job
+	deadline := time.NewTimer(s.sendTimeout)
+	defer deadline.Stop()
 
 	// Send jobs to worker pool
-	for _, job := range jobs {
-		if err := s.sendToWorkers(job); err != nil {
-			log.Printf("Failed to send job %s to workers: %v", job.ID, err)
-			continue
+	for i, job := range jobs {
+		if err := s.sendToWorkers(job, deadline.C); err != nil {
+			if errors.Is(err, errSendTimeout) {
+				slog.Warn("Timed out sending batch to workers",
+					"job_id", job.ID, "timeout", s.sendTimeout, "unsent", len(jobs)-i)
+			} else {
+				slog.Warn("Failed to send job to workers", "job_id", job.ID, "error", err)
+			}
+			// Workers are saturated; give the rest back instead of holding them
+			s.release(jobs[i:])
+			return
 		}
+		s.observePending(job)
+	}
+}
+
+// observePending records how long a job just handed to workers sat PENDING.
+// That is measured from CreatedAt, so only a job's first pass through
+// PENDING is observed; retries and reruns would include earlier runs.
+func (s *Scheduler) observePending(job *model.Job) {
+	if job.Attempt != 1 || job.RerunCount != 0 || job.ScheduledAt == nil {
+		return
+	}
+	s.metrics.StateDuration.WithLabelValues(string(state.PENDING)).
+		Observe(job.ScheduledAt.Sub(job.CreatedAt).Seconds())
+}
+
+// recordEmptyPoll counts a poll that claimed nothing. If claimable jobs
+// still exist, other schedulers held their locks (SKIP LOCKED), which
+// is counted as contention. Retries still in backoff aren't claimable.
+func (s *Scheduler) recordEmptyPoll() {
+	s.metrics.SchedulerEmptyPolls.Inc()
+
+	claimable, err := s.repository.PeekClaimable(s.ctx, 1, s.clock.Now(), s.skipTypes()...)
+	if err != nil {
+		slog.Error("Failed to peek claimable jobs", "error", err)
+		return
+	}
+	if len(claimable) > 0 {
+		s.metrics.SchedulerContention.Inc()
 	}
 }
 
 // sendToWorkers sends a job to the worker pool channel.
-func (s *Scheduler) sendToWorkers(job *model.Job) error {
+// Blocks until a worker has room, the deadline fires, or the scheduler stops.
+func (s *Scheduler) sendToWorkers(job *model.Job, deadline <-chan time.Time) error {
 	// Job is already in SCHEDULED state from ClaimPendingJobs
+
+	// Fast path: the buffer has room
 	select {
 	case s.jobChannel <- job:
-		log.Printf("Scheduled job %s (type: %s)", job.ID, job.Type)
+		slog.Info("Scheduled job", "job_id", job.ID, "type", job.Type)
 		return nil
+	default:
+		// Buffer is full, so this send will block
+		s.metrics.ChannelFull.Inc()
+	}
+
+	blockedSince := time.Now()
+	defer func() {
+		s.metrics.SendBlockDuration.Observe(time.Since(blockedSince).Seconds())
+	}()
 
-	case <-time.After(5 * time.Second):
-		return fmt.Errorf("timeout sending job to channel")
+	select {
+	case s.jobChannel <- job:
+		slog.Info("Scheduled job", "job_id", job.ID, "type", job.Type)
+		return nil
+
+	case <-deadline:
+		return errSendTimeout
 
 	case <-s.ctx.Done():
 		return s.ctx.Err()
 	}
 }
+
+// release returns claimed-but-unsent jobs to the queue so the next poll
+// (here or on another instance) can claim them again. Jobs are released
+// in one batch per target state rather than one write each.
+func (s *Scheduler) release(jobs []*model.Job) {
+	// s.ctx may already be cancelled during shutdown
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	byState := make(map[state.State][]string)
+	for _, job := range jobs {
+		to := unclaimedState(job)
+		byState[to] = append(byState[to], job.ID)
+	}
+
+	released := 0
+	for to, ids := range byState {
+		updated, err := s.repository.UpdateStateBatch(ctx, ids, state.SCHEDULED, to, "released after no worker took it in time")
+		if err != nil {
+			// The reaper will pick them up once they go stale
+			slog.Error("Failed to release jobs", "job_ids", ids, "error", err)
+			continue
+		}
+		released += len(updated)
+	}
+	slog.Info("Released unsent jobs back to the queue", "count", released)
+}
+
+// unclaimedState is the state a claimed job came from:
+// first attempts were PENDING, retries were RETRYING.
+func unclaimedState(job *model.Job) state.State {
+	if job.Attempt > 1 {
+		return state.RETRYING
+	}
+	return state.PENDING
+}
@@ -0,0 +1,313 @@
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/dipak0000812/orchestrix/internal/clock"
+	"github.com/dipak0000812/orchestrix/internal/job/model"
+	"github.com/dipak0000812/orchestrix/internal/job/repository"
+	"github.com/dipak0000812/orchestrix/internal/job/service"
+	"github.com/dipak0000812/orchestrix/internal/job/state"
+	"github.com/dipak0000812/orchestrix/internal/metrics"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	dto "github.com/prometheus/client_model/go"
+)
+
+// newTestMetrics returns metrics on a fresh registry, isolated from other tests.
+func newTestMetrics() *metrics.Metrics {
+	return metrics.NewMetrics(prometheus.NewRegistry())
+}
+
+func newTestJob(id string) *model.Job {
+	return &model.Job{
+		ID:          id,
+		Type:        "test",
+		State:       state.SCHEDULED,
+		Attempt:     1,
+		MaxAttempts: 3,
+		CreatedAt:   time.Now(),
+	}
+}
+
+func TestNewJobChannel(t *testing.T) {
+	if got := cap(NewJobChannel(5)); got != 5 {
+		t.Errorf("cap = %d, want 5", got)
+	}
+	if got := cap(NewJobChannel(0)); got != DefaultChannelSize {
+		t.Errorf("cap = %d, want default %d", got, DefaultChannelSize)
+	}
+}
+
+func TestNewScheduler_ClampsSettings(t *testing.T) {
+	tests := []struct {
+		name         string
+		pollInterval time.Duration
+		batchSize    int
+		wantInterval time.Duration
+		wantBatch    int
+	}{
+		{"zero interval and batch", 0, 0, MinPollInterval, DefaultBatchSize},
+		{"negative interval and batch", -time.Second, -5, MinPollInterval, DefaultBatchSize},
+		{"interval below minimum", time.Millisecond, 3, MinPollInterval, 3},
+		{"valid settings kept", time.Second, 20, time.Second, 20},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := NewScheduler(nil, tt.pollInterval, tt.batchSize, NewJobChannel(1), newTestMetrics(), 0)
+			defer s.cancel()
+
+			if s.pollInterval != tt.wantInterval {
+				t.Errorf("pollInterval = %v, want %v", s.pollInterval, tt.wantInterval)
+			}
+			if s.batchSize != tt.wantBatch {
+				t.Errorf("batchSize = %d, want %d", s.batchSize, tt.wantBatch)
+			}
+		})
+	}
+}
+
+func TestSendToWorkers_ChannelFullMetric(t *testing.T) {
+	m := newTestMetrics()
+	jobChannel := NewJobChannel(1)
+	s := NewScheduler(nil, time.Second, 10, jobChannel, m, 0)
+	defer s.cancel()
+
+	before := testutil.ToFloat64(m.ChannelFull)
+
+	// First send fits in the buffer
+	if err := s.sendToWorkers(newTestJob("job_1"), nil); err != nil {
+		t.Fatalf("sendToWorkers failed: %v", err)
+	}
+	if got := testutil.ToFloat64(m.ChannelFull) - before; got != 0 {
+		t.Fatalf("ChannelFull incremented by %v with room in buffer, want 0", got)
+	}
+
+	// Second send overflows the buffer and blocks until a worker reads
+	done := make(chan error, 1)
+	go func() {
+		done <- s.sendToWorkers(newTestJob("job_2"), nil)
+	}()
+
+	time.Sleep(50 * time.Millisecond)
+	<-jobChannel // simulate a worker freeing a slot
+
+	if err := <-done; err != nil {
+		t.Fatalf("sendToWorkers failed: %v", err)
+	}
+	if got := testutil.ToFloat64(m.ChannelFull) - before; got != 1 {
+		t.Errorf("ChannelFull incremented by %v, want 1", got)
+	}
+}
+
+func TestPollAndSchedule_FullChannelReturnsPromptly(t *testing.T) {
+	ctx := context.Background()
+	repo := repository.NewMemoryJobRepository()
+	m := newTestMetrics()
+
+	// 20 pending jobs, but room for only 2 and no workers reading
+	for i := 0; i < 20; i++ {
+		job := newTestJob(fmt.Sprintf("job_%02d", i))
+		job.State = state.PENDING
+		job.CreatedAt = time.Now().Add(time.Duration(i) * time.Millisecond)
+		if err := repo.Create(ctx, job); err != nil {
+			t.Fatalf("Failed to create job: %v", err)
+		}
+	}
+
+	jobChannel := NewJobChannel(2)
+	s := NewScheduler(repo, time.Second, 20, jobChannel, m, 100*time.Millisecond)
+	defer s.cancel()
+
+	start := time.Now()
+	s.pollAndSchedule()
+	elapsed := time.Since(start)
+
+	// One batch-wide timeout, not one per blocked job
+	if elapsed > time.Second {
+		t.Errorf("pollAndSchedule took %v, want well under 1s", elapsed)
+	}
+	if got := len(jobChannel); got != 2 {
+		t.Errorf("Channel holds %d jobs, want 2", got)
+	}
+
+	// Unsent jobs are released and can be claimed again
+	pending, _ := repo.ListByState(ctx, state.PENDING, 100)
+	if len(pending) != 18 {
+		t.Errorf("Pending jobs after poll = %d, want 18", len(pending))
+	}
+	scheduled, _ := repo.ListByState(ctx, state.SCHEDULED, 100)
+	if len(scheduled) != 2 {
+		t.Errorf("Scheduled jobs after poll = %d, want 2", len(scheduled))
+	}
+}
+
+func TestPollAndSchedule_SendTimeoutReleasesJob(t *testing.T) {
+	ctx := context.Background()
+	repo := repository.NewMemoryJobRepository()
+
+	job := newTestJob("job_timeout")
+	job.State = state.PENDING
+	if err := repo.Create(ctx, job); err != nil {
+		t.Fatalf("Failed to create job: %v", err)
+	}
+
+	// The only slot is taken and no worker is reading
+	jobChannel := NewJobChannel(1)
+	jobChannel <- newTestJob("job_blocking")
+
+	s := NewScheduler(repo, time.Second, 10, jobChannel, newTestMetrics(), time.Millisecond)
+	defer s.cancel()
+
+	start := time.Now()
+	s.pollAndSchedule()
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Errorf("pollAndSchedule took %v, want about the 1ms send timeout", elapsed)
+	}
+
+	// The claimed job went back to the queue rather than staying SCHEDULED
+	released, _ := repo.GetByID(ctx, job.ID)
+	if released.State != state.PENDING || released.ScheduledAt != nil {
+		t.Errorf("Job = %s scheduled_at %v, want PENDING with no scheduled_at", released.State, released.ScheduledAt)
+	}
+	if released.LastTransitionReason == nil {
+		t.Error("Expected a transition reason on the released job")
+	}
+}
+
+// contendedClaimer simulates peers holding every claimable row:
+// claims come back empty even though claimable jobs exist.
+type contendedClaimer struct {
+	claimable int
+}
+
+func (c *contendedClaimer) ClaimPendingJobs(ctx context.Context, limit int, now time.Time, skipTypes ...string) ([]*model.Job, error) {
+	return []*model.Job{}, nil
+}
+
+func (c *contendedClaimer) PeekClaimable(ctx context.Context, limit int, now time.Time, skipTypes ...string) ([]*model.Job, error) {
+	jobs := []*model.Job{}
+	for i := 0; i < c.claimable && i < limit; i++ {
+		jobs = append(jobs, newTestJob(fmt.Sprintf("job_%d", i)))
+	}
+	return jobs, nil
+}
+
+func (c *contendedClaimer) UpdateStateBatch(ctx context.Context, ids []string, from, to state.State, reason string) ([]string, error) {
+	return ids, nil
+}
+
+func TestPollAndSchedule_ContentionMetrics(t *testing.T) {
+	m := newTestMetrics()
+
+	tests := []struct {
+		name           string
+		claimable      int
+		wantContention float64
+	}{
+		{"queue empty", 0, 0},
+		{"peers holding jobs", 5, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := NewScheduler(&contendedClaimer{claimable: tt.claimable}, time.Second, 10, NewJobChannel(1), m, 0)
+			defer s.cancel()
+
+			emptyBefore := testutil.ToFloat64(m.SchedulerEmptyPolls)
+			contentionBefore := testutil.ToFloat64(m.SchedulerContention)
+
+			s.pollAndSchedule()
+
+			if got := testutil.ToFloat64(m.SchedulerEmptyPolls) - emptyBefore; got != 1 {
+				t.Errorf("SchedulerEmptyPolls incremented by %v, want 1", got)
+			}
+			if got := testutil.ToFloat64(m.SchedulerContention) - contentionBefore; got != tt.wantContention {
+				t.Errorf("SchedulerContention incremented by %v, want %v", got, tt.wantContention)
+			}
+		})
+	}
+}
+
+func TestPollAndSchedule_ClaimsRetryOnceDue(t *testing.T) {
+	ctx := context.Background()
+	repo := repository.NewMemoryJobRepository()
+	fakeClock := clock.NewFakeClock(time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC))
+
+	retryConfig := service.DefaultRetryConfig()
+	retryConfig.BaseDelay = 10 * time.Second
+	retryConfig.MaxDelay = time.Minute
+	jobService := service.NewJobService(repo, state.NewStateMachine(), service.NewULIDGenerator(), retryConfig)
+	jobService.SetClock(fakeClock)
+
+	jobChannel := NewJobChannel(1)
+	s := NewScheduler(repo, time.Second, 10, jobChannel, newTestMetrics(), 0)
+	s.SetClock(fakeClock)
+	defer s.cancel()
+
+	// First attempt runs and fails, leaving the job RETRYING with a 10s backoff
+	job, _ := jobService.CreateJob(ctx, "test", []byte(`{}`))
+	s.pollAndSchedule()
+	<-jobChannel
+	jobService.TransitionState(ctx, job.ID, state.RUNNING)
+	if _, err := jobService.HandleFailure(ctx, job.ID, fmt.Errorf("boom")); err != nil {
+		t.Fatalf("HandleFailure failed: %v", err)
+	}
+
+	// Backoff hasn't expired: nothing to claim
+	fakeClock.Advance(9 * time.Second)
+	s.pollAndSchedule()
+	if len(jobChannel) != 0 {
+		t.Fatal("Retry was claimed before its backoff expired")
+	}
+
+	// Backoff expired: the retry is claimed
+	fakeClock.Advance(time.Second)
+	s.pollAndSchedule()
+	if len(jobChannel) != 1 {
+		t.Fatal("Expected the due retry to be claimed")
+	}
+
+	retried := <-jobChannel
+	if retried.ID != job.ID || retried.Attempt != 2 {
+		t.Errorf("Claimed %s attempt %d, want %s attempt 2", retried.ID, retried.Attempt, job.ID)
+	}
+	if !retried.ScheduledAt.Equal(fakeClock.Now()) {
+		t.Errorf("ScheduledAt = %v, want fake clock time %v", retried.ScheduledAt, fakeClock.Now())
+	}
+}
+
+func TestPollAndSchedule_ObservesPendingDuration(t *testing.T) {
+	ctx := context.Background()
+	repo := repository.NewMemoryJobRepository()
+	fakeClock := clock.NewFakeClock(time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC))
+
+	jobService := service.NewJobService(repo, state.NewStateMachine(), service.NewULIDGenerator(), service.DefaultRetryConfig())
+	jobService.SetClock(fakeClock)
+
+	m := newTestMetrics()
+	jobChannel := NewJobChannel(1)
+	s := NewScheduler(repo, time.Second, 10, jobChannel, m, 0)
+	s.SetClock(fakeClock)
+	defer s.cancel()
+
+	jobService.CreateJob(ctx, "test", []byte(`{}`))
+	fakeClock.Advance(3 * time.Second)
+	s.pollAndSchedule()
+	<-jobChannel
+
+	var pending dto.Metric
+	if err := m.StateDuration.WithLabelValues(string(state.PENDING)).(prometheus.Histogram).Write(&pending); err != nil {
+		t.Fatalf("Failed to read histogram: %v", err)
+	}
+	if got := pending.GetHistogram().GetSampleCount(); got != 1 {
+		t.Fatalf("PENDING observations = %d, want 1", got)
+	}
+	if got := pending.GetHistogram().GetSampleSum(); got != 3 {
+		t.Errorf("PENDING duration = %vs, want 3s", got)
+	}
+}
@@ -14,10 +14,11 @@ import (
 	"github.com/dipak0000812/orchestrix/internal/job/state"
 	"github.com/dipak0000812/orchestrix/internal/metrics"
 	"github.com/dipak0000812/orchestrix/internal/scheduler"
+	"github.com/prometheus/client_golang/prometheus"
 )
 
-// metricsOnce ensures metrics are only registered once across all tests.
-// Prometheus panics if you register the same metric name twice.
+// testMetrics is shared across tests in this package so a test can read back
+// what the pool it set up recorded. It uses its own registry, not the global one.
 var (
 	testMetrics     *metrics.Metrics
 	testMetricsOnce sync.Once
@@ -25,7 +26,7 @@ var (
 
 func getTestMetrics() *metrics.Metrics {
 	testMetricsOnce.Do(func() {
-		testMetrics = metrics.NewMetrics()
+		testMetrics = metrics.NewMetrics(prometheus.NewRegistry())
 	})
 	return testMetrics
 }
@@ -55,13 +56,12 @@ func setupIntegrationTest(t *testing.T) (
 		t.Fatalf("Failed to create connection pool: %v", err)
 	}
 
-	_, err = pool.Exec(context.Background(), "DELETE FROM jobs")
-	if err != nil {
+	repo := repository.NewPostgresJobRepository(pool)
+	repo.SetAllowDestructive(true)
+	if err := repo.TruncateAll(context.Background()); err != nil {
 		t.Fatalf("Failed to clean test data: %v", err)
 	}
 
-	repo := repository.NewPostgresJobRepository(pool)
-
 	stateMachine := state.NewStateMachine()
 	idGen := service.NewULIDGenerator()
 	retryConfig := service.DefaultRetryConfig()
@@ -71,7 +71,7 @@ func setupIntegrationTest(t *testing.T) (
 	executors.Register("demo_job", executor.NewDemoExecutor(100*time.Millisecond))
 	executors.Register("failing_job", executor.NewFailingExecutor())
 
-	jobChannel := make(chan *model.Job, 10)
+	jobChannel := scheduler.NewJobChannel(10)
 
 	// Use shared metrics instance — not NewMetrics() every time
 	m := getTestMetrics()
@@ -81,9 +81,12 @@ func setupIntegrationTest(t *testing.T) (
 		500*time.Millisecond,
 		5,
 		jobChannel,
+		m,
+		0,
 	)
 
 	workers := NewWorkerPool(
+		3,
 		3,
 		jobChannel,
 		executors,
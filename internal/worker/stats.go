@@ -0,0 +1,77 @@
+package worker
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// WorkerStats reports how a single worker has spent its time.
+// Only completed idle/busy intervals are counted.
+type WorkerStats struct {
+	ID            int
+	Idle          time.Duration
+	Busy          time.Duration
+	JobsProcessed int64
+}
+
+// PoolStats is a point-in-time snapshot of the worker pool.
+type PoolStats struct {
+	Workers []WorkerStats
+}
+
+// TotalIdle returns the idle time summed across all workers.
+func (s PoolStats) TotalIdle() time.Duration {
+	var total time.Duration
+	for _, w := range s.Workers {
+		total += w.Idle
+	}
+	return total
+}
+
+// TotalBusy returns the busy time summed across all workers.
+func (s PoolStats) TotalBusy() time.Duration {
+	var total time.Duration
+	for _, w := range s.Workers {
+		total += w.Busy
+	}
+	return total
+}
+
+// workerTime accumulates idle/busy time for one worker.
+// Atomics keep the hot path to a couple of adds per job.
+type workerTime struct {
+	idleNanos atomic.Int64
+	busyNanos atomic.Int64
+	jobs      atomic.Int64
+}
+
+// Stats returns a snapshot of per-worker idle and busy time.
+// Workers retired by Scale are included with their final totals.
+func (p *WorkerPool) Stats() PoolStats {
+	p.statsMu.Lock()
+	defer p.statsMu.Unlock()
+
+	stats := PoolStats{Workers: make([]WorkerStats, len(p.workerTimes))}
+	for i, wt := range p.workerTimes {
+		stats.Workers[i] = WorkerStats{
+			ID:            i,
+			Idle:          time.Duration(wt.idleNanos.Load()),
+			Busy:          time.Duration(wt.busyNanos.Load()),
+			JobsProcessed: wt.jobs.Load(),
+		}
+	}
+	return stats
+}
+
+// recordIdle adds an idle interval to the worker's totals.
+func (p *WorkerPool) recordIdle(wt *workerTime, d time.Duration) {
+	wt.idleNanos.Add(int64(d))
+	p.metrics.WorkerIdleSeconds.Add(d.Seconds())
+}
+
+// recordBusy adds a busy interval to the worker's totals.
+func (p *WorkerPool) recordBusy(wt *workerTime, d time.Duration) {
+	wt.busyNanos.Add(int64(d))
+	wt.jobs.Add(1)
+	p.metrics.WorkerBusySeconds.Add(d.Seconds())
+}
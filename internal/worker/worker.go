@@ -2,9 +2,12 @@ package worker
 
 import (
 	"context"
+	"errors"
 	"fmt"
-	"log"
+	"log/slog"
+	"slices"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/dipak0000812/orchestrix/internal/executor"
@@ -12,6 +15,7 @@ import (
 	"github.com/dipak0000812/orchestrix/internal/job/service"
 	"github.com/dipak0000812/orchestrix/internal/job/state"
 	"github.com/dipak0000812/orchestrix/internal/metrics"
+	"golang.org/x/sync/semaphore"
 )
 
 // WorkerPool manages a pool of workers that execute jobs.
@@ -23,14 +27,74 @@ type WorkerPool struct {
 	metrics    *metrics.Metrics
 	jobTimeout time.Duration
 
-	ctx    context.Context
-	cancel context.CancelFunc
-	wg     sync.WaitGroup
+	// classifier decides whether a failed execution is retried, for
+	// executors that don't implement ErrorClassifierProvider.
+	classifier executor.ErrorClassifier
+
+	// stopTimeout bounds how long Drain and Stop wait for workers to exit
+	// after running jobs are interrupted. Zero waits indefinitely.
+	stopTimeout time.Duration
+
+	// slots caps how many jobs execute at once across all workers.
+	// Workers block on it, so jobs wait for a slot rather than running.
+	// Nil when the cap is just the worker count, so Scale can change it.
+	slots *semaphore.Weighted
+
+	// workerTimes holds idle/busy accounting, one entry per worker ID,
+	// including workers retired by Scale. stops holds a stop channel per
+	// active worker; both are guarded by statsMu.
+	statsMu     sync.Mutex
+	workerTimes []*workerTime
+	stops       []chan struct{}
+
+	// running maps job IDs to their abort funcs while they execute.
+	// claimed holds the IDs of jobs a worker has taken, from pickup until
+	// executeJob returns, so a job queued twice only runs once.
+	// Both are guarded by runningMu.
+	runningMu sync.Mutex
+	running   map[string]context.CancelCauseFunc
+	claimed   map[string]struct{}
+
+	// inFlight counts jobs that have a slot and are executing;
+	// finished counts jobs that have left executeJob since Start.
+	inFlight atomic.Int64
+	finished atomic.Int64
+
+	// liveWorkers counts running worker goroutines; lastTick is when a
+	// worker last picked up or finished a job, in Unix nanoseconds.
+	liveWorkers atomic.Int64
+	lastTick    atomic.Int64
+
+	// paused stops workers from taking jobs off the channel; pauseChanged
+	// is closed and replaced on every toggle to wake idle workers.
+	// Both are guarded by pauseMu.
+	pauseMu      sync.Mutex
+	paused       bool
+	pauseChanged chan struct{}
+
+	// pausedTypes is the set of job types not executed. Guarded by pauseMu.
+	pausedTypes map[string]struct{}
+
+	// ctx stops workers from taking new jobs; jobCtx is the parent of
+	// every job's context, so cancelling it interrupts running jobs.
+	// Drain cancels ctx first and jobCtx only once its deadline hits.
+	ctx       context.Context
+	cancel    context.CancelFunc
+	jobCtx    context.Context
+	jobCancel context.CancelFunc
+	wg        sync.WaitGroup
 }
 
+// DefaultStopTimeout is how long Drain and Stop wait, by default, for
+// interrupted jobs to return before giving up on their workers.
+const DefaultStopTimeout = 10 * time.Second
+
 // NewWorkerPool creates a new worker pool.
+// maxConcurrent caps the number of jobs executing at once; values <= 0
+// mean no cap beyond the worker count, which then tracks Scale.
 func NewWorkerPool(
 	numWorkers int,
+	maxConcurrent int,
 	jobChannel chan *model.Job,
 	executors *executor.ExecutorRegistry,
 	jobService *service.JobService,
@@ -38,138 +102,646 @@ func NewWorkerPool(
 	jobTimeout time.Duration,
 ) *WorkerPool {
 	ctx, cancel := context.WithCancel(context.Background())
+	jobCtx, jobCancel := context.WithCancel(context.Background())
+
+	// With no explicit cap, the number of workers is the cap
+	var slots *semaphore.Weighted
+	if maxConcurrent > 0 {
+		slots = semaphore.NewWeighted(int64(maxConcurrent))
+	}
 
 	return &WorkerPool{
-		numWorkers: numWorkers,
-		jobChannel: jobChannel,
-		executors:  executors,
-		service:    jobService,
-		metrics:    m,
-		jobTimeout: jobTimeout,
-		ctx:        ctx,
-		cancel:     cancel,
+		numWorkers:   numWorkers,
+		jobChannel:   jobChannel,
+		executors:    executors,
+		service:      jobService,
+		metrics:      m,
+		jobTimeout:   jobTimeout,
+		classifier:   executor.DefaultClassifier,
+		stopTimeout:  DefaultStopTimeout,
+		slots:        slots,
+		running:      make(map[string]context.CancelCauseFunc),
+		claimed:      make(map[string]struct{}),
+		pauseChanged: make(chan struct{}),
+		pausedTypes:  make(map[string]struct{}),
+		ctx:          ctx,
+		cancel:       cancel,
+		jobCtx:       jobCtx,
+		jobCancel:    jobCancel,
 	}
 }
 
 // Start spawns worker goroutines.
 func (p *WorkerPool) Start() {
+	p.statsMu.Lock()
 	for i := 0; i < p.numWorkers; i++ {
-		p.wg.Add(1)
-		go p.worker(i)
+		p.startWorkerLocked()
 	}
-	log.Printf("Worker pool started with %d workers", p.numWorkers)
+	p.statsMu.Unlock()
+	slog.Info("Worker pool started", "workers", p.numWorkers)
 }
 
-// Stop gracefully stops all workers.
-func (p *WorkerPool) Stop() {
-	log.Println("Worker pool stopping...")
+// SetErrorClassifier sets how the pool decides whether a failed job is
+// retried, e.g. to treat errors mentioning "invalid payload" as fatal.
+// Executors implementing ErrorClassifierProvider classify their own
+// errors instead, and a NonRetryableError is never retried either way.
+// Nil restores executor.DefaultClassifier. Call before Start.
+func (p *WorkerPool) SetErrorClassifier(classifier executor.ErrorClassifier) {
+	if classifier == nil {
+		classifier = executor.DefaultClassifier
+	}
+	p.classifier = classifier
+}
+
+// SetStopTimeout sets how long Drain and Stop wait for workers to exit
+// once running jobs have been interrupted. An executor that ignores its
+// context can otherwise hold shutdown open forever; past the timeout its
+// worker is logged as leaked and left behind. Zero or less waits
+// indefinitely. Call before Drain or Stop.
+func (p *WorkerPool) SetStopTimeout(d time.Duration) {
+	p.stopTimeout = d
+}
+
+// Alive reports whether the pool is accepting jobs and has at least one
+// worker goroutine running.
+func (p *WorkerPool) Alive() bool {
+	return p.ctx.Err() == nil && p.liveWorkers.Load() > 0
+}
+
+// LastTick returns when a worker last picked up or finished a job.
+// Zero if no job has run yet; an idle pool is still alive.
+func (p *WorkerPool) LastTick() time.Time {
+	if last := p.lastTick.Load(); last != 0 {
+		return time.Unix(0, last)
+	}
+	return time.Time{}
+}
+
+// Scale changes the number of workers at runtime.
+// New workers start immediately; surplus workers finish their current
+// job and then exit. An explicit maxConcurrent cap still applies.
+func (p *WorkerPool) Scale(n int) error {
+	if n < 1 {
+		return fmt.Errorf("worker count must be at least 1, got %d", n)
+	}
+
+	p.statsMu.Lock()
+	defer p.statsMu.Unlock()
+
+	for len(p.stops) < n {
+		p.startWorkerLocked()
+	}
+	for len(p.stops) > n {
+		last := len(p.stops) - 1
+		close(p.stops[last])
+		p.stops = p.stops[:last]
+	}
+
+	slog.Info("Worker pool scaled", "from", p.numWorkers, "to", n)
+	p.numWorkers = n
+	return nil
+}
+
+// NumWorkers returns the current number of active workers.
+func (p *WorkerPool) NumWorkers() int {
+	p.statsMu.Lock()
+	defer p.statsMu.Unlock()
+	return p.numWorkers
+}
+
+// Pause stops workers from taking new jobs. Jobs already executing finish
+// normally; queued jobs stay SCHEDULED in the channel until Resume.
+// The channel is bounded, so once it fills the scheduler's sends time
+// out and further claims are released back to PENDING.
+func (p *WorkerPool) Pause() {
+	p.setPaused(true)
+}
+
+// Resume undoes Pause.
+func (p *WorkerPool) Resume() {
+	p.setPaused(false)
+}
+
+// Paused reports whether the pool is paused.
+func (p *WorkerPool) Paused() bool {
+	p.pauseMu.Lock()
+	defer p.pauseMu.Unlock()
+	return p.paused
+}
+
+func (p *WorkerPool) setPaused(paused bool) {
+	p.pauseMu.Lock()
+	defer p.pauseMu.Unlock()
+
+	if p.paused == paused {
+		return
+	}
+	p.paused = paused
+	close(p.pauseChanged)
+	p.pauseChanged = make(chan struct{})
+
+	if paused {
+		slog.Info("Worker pool paused")
+	} else {
+		slog.Info("Worker pool resumed")
+	}
+}
+
+// PauseType stops executing jobs of one type while other types carry on,
+// e.g. during a maintenance window for that type's downstream service.
+// Jobs already executing finish normally. Jobs of the type that reach a
+// worker are released back to PENDING/RETRYING rather than held here;
+// pass PausedTypes to Scheduler.SetPausedTypes so they aren't claimed
+// again until ResumeType.
+func (p *WorkerPool) PauseType(jobType string) {
+	p.pauseMu.Lock()
+	defer p.pauseMu.Unlock()
+
+	if _, paused := p.pausedTypes[jobType]; paused {
+		return
+	}
+	p.pausedTypes[jobType] = struct{}{}
+	slog.Info("Job type paused", "job_type", jobType)
+}
+
+// ResumeType undoes PauseType. Released jobs are claimed again by the
+// scheduler's next poll.
+func (p *WorkerPool) ResumeType(jobType string) {
+	p.pauseMu.Lock()
+	defer p.pauseMu.Unlock()
+
+	if _, paused := p.pausedTypes[jobType]; !paused {
+		return
+	}
+	delete(p.pausedTypes, jobType)
+	slog.Info("Job type resumed", "job_type", jobType)
+}
+
+// TypePaused reports whether jobs of a type are paused.
+func (p *WorkerPool) TypePaused(jobType string) bool {
+	p.pauseMu.Lock()
+	defer p.pauseMu.Unlock()
+
+	_, paused := p.pausedTypes[jobType]
+	return paused
+}
+
+// PausedTypes returns the paused job types, sorted.
+func (p *WorkerPool) PausedTypes() []string {
+	p.pauseMu.Lock()
+	defer p.pauseMu.Unlock()
+
+	types := make([]string, 0, len(p.pausedTypes))
+	for jobType := range p.pausedTypes {
+		types = append(types, jobType)
+	}
+	slices.Sort(types)
+	return types
+}
+
+// releaseIfTypePaused gives job back to the queue if its type is paused.
+// Reports whether the job was taken off this worker's hands.
+func (p *WorkerPool) releaseIfTypePaused(logger *slog.Logger, job *model.Job) bool {
+	if !p.TypePaused(job.Type) {
+		return false
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	released, err := p.service.ReleaseScheduled(ctx, job, "released while its type is paused")
+	if err != nil {
+		// Left SCHEDULED; the reaper requeues it once it goes stale
+		logger.Error("Failed to release job of paused type", "state", job.State, "error", err)
+		return true
+	}
+	if released {
+		logger.Info("Released job of paused type", "state", job.State)
+	}
+	return true
+}
+
+// jobSource returns the channel a worker should take jobs from, nil while
+// paused, and a channel that is closed when the pause state next changes.
+func (p *WorkerPool) jobSource() (<-chan *model.Job, <-chan struct{}) {
+	p.pauseMu.Lock()
+	defer p.pauseMu.Unlock()
+
+	if p.paused {
+		return nil, p.pauseChanged
+	}
+	return p.jobChannel, p.pauseChanged
+}
+
+// startWorkerLocked starts one worker with the next unused ID.
+// Caller must hold statsMu.
+func (p *WorkerPool) startWorkerLocked() {
+	id := len(p.workerTimes)
+	wt := &workerTime{}
+	stop := make(chan struct{})
+
+	p.workerTimes = append(p.workerTimes, wt)
+	p.stops = append(p.stops, stop)
+
+	p.wg.Add(1)
+	go p.worker(id, wt, stop)
+}
+
+// DrainResult reports what happened to in-flight jobs during Drain.
+type DrainResult struct {
+	// InFlight is the number of jobs executing when the drain began.
+	InFlight int
+
+	// Completed is how many of those finished before the deadline.
+	Completed int
+
+	// Abandoned is how many were still running at the deadline and were
+	// interrupted. Each is handed back to the queue as PENDING or RETRYING
+	// without using up an attempt, once its executor returns; one that
+	// never returns stays RUNNING until startup recovery.
+	Abandoned int
+
+	// Leaked is how many worker goroutines had still not exited when the
+	// stop timeout ran out, typically stuck in an executor that ignores
+	// its context.
+	Leaked int
+}
+
+// Drain stops taking new jobs and waits for in-flight jobs to finish.
+// When ctx is done, jobs still running are interrupted, and workers that
+// haven't exited within the stop timeout after that are abandoned. Jobs
+// still queued in the channel are not taken and stay SCHEDULED for re-claim.
+func (p *WorkerPool) Drain(ctx context.Context) DrainResult {
+	slog.Info("Worker pool draining...")
 	p.cancel()
-	p.wg.Wait()
-	log.Println("Worker pool stopped")
+	finishedBefore := p.finished.Load()
+
+	done := make(chan struct{})
+	go func() {
+		p.wg.Wait()
+		close(done)
+	}()
+
+	var result DrainResult
+	select {
+	case <-done:
+		result.Completed = int(p.finished.Load() - finishedBefore)
+	case <-ctx.Done():
+		// Count before cancelling: jobs interrupted from here on are abandoned
+		result.Completed = int(p.finished.Load() - finishedBefore)
+		result.Abandoned = int(p.inFlight.Load())
+		p.jobCancel()
+		result.Leaked = p.waitForWorkers(done)
+	}
+	p.jobCancel()
+
+	result.InFlight = result.Completed + result.Abandoned
+	slog.Info("Worker pool stopped",
+		"in_flight", result.InFlight, "completed", result.Completed,
+		"abandoned", result.Abandoned, "leaked", result.Leaked)
+	return result
+}
+
+// waitForWorkers waits for done, giving up after the stop timeout.
+// Returns how many workers were still running when it gave up.
+func (p *WorkerPool) waitForWorkers(done <-chan struct{}) int {
+	if p.stopTimeout <= 0 {
+		<-done
+		return 0
+	}
+
+	timer := time.NewTimer(p.stopTimeout)
+	defer timer.Stop()
+
+	select {
+	case <-done:
+		return 0
+	case <-timer.C:
+	}
+
+	p.runningMu.Lock()
+	jobIDs := make([]string, 0, len(p.running))
+	for jobID := range p.running {
+		jobIDs = append(jobIDs, jobID)
+	}
+	p.runningMu.Unlock()
+
+	leaked := int(p.liveWorkers.Load())
+	slog.Error("Workers did not stop in time, leaking them",
+		"leaked", leaked, "job_ids", jobIDs, "stop_timeout", p.stopTimeout)
+	return leaked
+}
+
+// Stop stops all workers immediately, interrupting any running jobs.
+// Use Drain to let running jobs finish first.
+func (p *WorkerPool) Stop() {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	p.Drain(ctx)
+}
+
+// ErrJobAborted is the cancellation cause for jobs stopped via Abort.
+var ErrJobAborted = errors.New("job aborted")
+
+// Abort cancels a job if it is currently executing in this pool.
+// Returns false if the job isn't running here.
+//
+// The job's state is left alone; the caller is expected to have
+// already recorded the outcome (e.g. via JobService.ForceFail).
+func (p *WorkerPool) Abort(jobID string) bool {
+	p.runningMu.Lock()
+	abort, ok := p.running[jobID]
+	p.runningMu.Unlock()
+
+	if ok {
+		abort(ErrJobAborted)
+	}
+	return ok
+}
+
+// trackRunning registers an abort func for a job and returns a func that removes it.
+func (p *WorkerPool) trackRunning(jobID string, abort context.CancelCauseFunc) func() {
+	p.runningMu.Lock()
+	p.running[jobID] = abort
+	p.runningMu.Unlock()
+
+	return func() {
+		p.runningMu.Lock()
+		delete(p.running, jobID)
+		p.runningMu.Unlock()
+	}
 }
 
 // worker is the main worker loop.
-func (p *WorkerPool) worker(id int) {
+func (p *WorkerPool) worker(id int, wt *workerTime, stop <-chan struct{}) {
 	defer p.wg.Done()
 
-	log.Printf("Worker %d started", id)
+	p.liveWorkers.Add(1)
+	defer p.liveWorkers.Add(-1)
+
+	slog.Debug("Worker started", "worker_id", id)
 
+	idleSince := time.Now()
 	for {
+		// A nil channel never receives, so a paused worker only waits
+		// for the pause to change or to be stopped
+		jobs, pauseChanged := p.jobSource()
+
 		select {
-		case job := <-p.jobChannel:
+		case job := <-jobs:
+			busySince := time.Now()
+			p.lastTick.Store(busySince.UnixNano())
+			p.recordIdle(wt, busySince.Sub(idleSince))
+
 			p.executeJob(id, job)
 
+			idleSince = time.Now()
+			p.lastTick.Store(idleSince.UnixNano())
+			p.recordBusy(wt, idleSince.Sub(busySince))
+
+		case <-pauseChanged:
+
+		case <-stop:
+			slog.Debug("Worker retired", "worker_id", id)
+			return
+
 		case <-p.ctx.Done():
-			log.Printf("Worker %d stopping", id)
+			slog.Debug("Worker stopping", "worker_id", id)
 			return
 		}
 	}
 }
 
+// claim marks a job as taken by a worker. It returns false if another
+// worker already has it, e.g. because it was released and re-claimed
+// while still sitting in the channel; the caller must skip it then.
+// On success, the returned func releases the claim.
+func (p *WorkerPool) claim(jobID string) (func(), bool) {
+	p.runningMu.Lock()
+	defer p.runningMu.Unlock()
+
+	if _, taken := p.claimed[jobID]; taken {
+		return nil, false
+	}
+	p.claimed[jobID] = struct{}{}
+
+	return func() {
+		p.runningMu.Lock()
+		delete(p.claimed, jobID)
+		p.runningMu.Unlock()
+	}, true
+}
+
+// jobLogger returns a logger carrying the fields every job log line has,
+// so a job's lines can be queried in a log aggregator. Each line adds a
+// "state" field with the job's state as of that line.
+func jobLogger(workerID int, job *model.Job) *slog.Logger {
+	return slog.With(
+		"worker_id", workerID,
+		"job_id", job.ID,
+		"job_type", job.Type,
+		"attempt", job.Attempt,
+	)
+}
+
 // executeJob executes a single job.
 func (p *WorkerPool) executeJob(workerID int, job *model.Job) {
+	logger := jobLogger(workerID, job)
+
+	// A duplicate of a job another worker holds is dropped; one picked up
+	// after the other finished fails the SCHEDULED -> RUNNING transition
+	release, ok := p.claim(job.ID)
+	if !ok {
+		logger.Warn("Skipping job already taken by another worker", "state", job.State)
+		return
+	}
+	defer release()
+
+	if p.releaseIfTypePaused(logger, job) {
+		return
+	}
+
+	// Wait for a global execution slot
+	if p.slots != nil {
+		if err := p.slots.Acquire(p.ctx, 1); err != nil {
+			logger.Warn("Pool stopped before job could start", "state", job.State)
+			return
+		}
+		defer p.slots.Release(1)
+	}
+
+	p.inFlight.Add(1)
+	defer func() {
+		p.inFlight.Add(-1)
+		p.finished.Add(1)
+	}()
+
 	defer func() {
 		if r := recover(); r != nil {
-			log.Printf("Worker %d: PANIC during job %s: %v", workerID, job.ID, r)
-			ctx, cancel := context.WithTimeout(p.ctx, 5*time.Second)
+			logger.Error("PANIC during job", "state", state.RUNNING, "panic", r)
+			ctx, cancel := context.WithTimeout(p.jobCtx, 5*time.Second)
 			defer cancel()
-			p.handleFailure(ctx, job, fmt.Errorf("panic: %v", r), false)
+			p.handleFailure(ctx, logger, job, fmt.Errorf("panic: %v", r), false)
 		}
 	}()
 
-	log.Printf("Worker %d executing job %s (type: %s, attempt: %d)",
-		workerID, job.ID, job.Type, job.Attempt)
+	logger.Info("Executing job", "state", job.State)
 
-	ctx, cancel := context.WithTimeout(p.ctx, p.jobTimeout)
+	timeout := p.timeoutFor(job.Type)
+	ctx, cancel := context.WithTimeout(p.jobCtx, timeout)
 	defer cancel()
 
 	// Transition to RUNNING
 	if err := p.service.TransitionState(ctx, job.ID, state.RUNNING); err != nil {
-		log.Printf("Worker %d failed to transition job %s to RUNNING: %v",
-			workerID, job.ID, err)
+		logger.Error("Failed to transition job to RUNNING", "state", job.State, "error", err)
 		return
 	}
 
+	// Time spent waiting between creation and execution
+	startedAt := time.Now()
+	p.metrics.QueueWaitDuration.Observe(startedAt.Sub(job.CreatedAt).Seconds())
+	if job.ScheduledAt != nil {
+		p.metrics.StateDuration.WithLabelValues(string(state.SCHEDULED)).
+			Observe(startedAt.Sub(*job.ScheduledAt).Seconds())
+	}
+
+	// However the run ends, the job has left RUNNING by the time we return
+	p.metrics.JobsRunning.Inc()
+	defer func() {
+		p.metrics.JobsRunning.Dec()
+		p.metrics.StateDuration.WithLabelValues(string(state.RUNNING)).
+			Observe(time.Since(startedAt).Seconds())
+	}()
+
 	// Get executor for this job type
 	exec, err := p.executors.Get(job.Type)
 	if err != nil {
-		log.Printf("Worker %d: no executor for job type '%s'", workerID, job.Type)
-		p.handleFailure(ctx, job, err, false)
+		logger.Error("No executor for job type", "state", state.RUNNING)
+		p.handleFailure(ctx, logger, job, err, false)
+		return
+	}
+
+	// Externally stored payloads are fetched here; a blob store outage is retryable
+	payload, err := p.service.LoadPayload(ctx, job)
+	if err != nil {
+		logger.Error("Failed to load job payload", "state", state.RUNNING, "error", err)
+		p.handleFailure(ctx, logger, job, err, true)
 		return
 	}
 
-	// Execute the job
+	// Execute the job, abortable via Abort
+	runCtx, abort := context.WithCancelCause(executor.WithJobContext(ctx, executor.JobContext{
+		ID:          job.ID,
+		Type:        job.Type,
+		Attempt:     job.Attempt,
+		MaxAttempts: job.MaxAttempts,
+	}))
+	untrack := p.trackRunning(job.ID, abort)
+
 	startTime := time.Now()
-	err = exec.Execute(ctx, job.Payload)
+	err = exec.Execute(runCtx, payload)
 	duration := time.Since(startTime)
 
+	untrack()
+	abort(nil)
+
 	p.metrics.JobDuration.Observe(duration.Seconds())
 
+	// Aborted jobs already have their final state recorded
+	if errors.Is(context.Cause(runCtx), ErrJobAborted) {
+		logger.Info("Job aborted", "state", state.RUNNING, "duration", duration)
+		return
+	}
+
+	// A done ctx can't record the outcome, so those cases use a fresh one
+	switch {
+	case err != nil && errors.Is(ctx.Err(), context.Canceled):
+		// The pool is stopping, which isn't the job's fault: it goes
+		// back to the queue without using up an attempt
+		logger.Warn("Job interrupted by shutdown", "state", state.RUNNING, "duration", duration, "error", err)
+		releaseCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), 5*time.Second)
+		defer cancel()
+		if err := p.service.ReleaseInterrupted(releaseCtx, job.ID); err != nil {
+			logger.Error("Failed to requeue interrupted job", "state", state.RUNNING, "error", err)
+		}
+	case err != nil && errors.Is(ctx.Err(), context.DeadlineExceeded):
+		// Retried like any other failure, but with an error saying why
+		logger.Warn("Job timed out", "state", state.RUNNING, "duration", duration, "timeout", timeout, "error", err)
+		p.metrics.JobsTimedOut.Inc()
+		failCtx, cancel := context.WithTimeout(p.jobCtx, 5*time.Second)
+		defer cancel()
+		p.handleFailure(failCtx, logger, job, fmt.Errorf("job timed out after %s", timeout), p.isRetryable(exec, err))
+	case err != nil:
+		logger.Warn("Job failed", "state", state.RUNNING, "duration", duration, "error", err)
+		p.handleFailure(ctx, logger, job, err, p.isRetryable(exec, err))
+	default:
+		logger.Info("Job succeeded", "state", state.RUNNING, "duration", duration)
+		p.handleSuccess(ctx, logger, job)
+	}
+}
+
+// isRetryable classifies an execution error: NonRetryableError is always
+// fatal, otherwise the executor's own classifier or the pool's decides.
+func (p *WorkerPool) isRetryable(exec executor.Executor, err error) bool {
+	if !executor.DefaultClassifier(err) {
+		return false
+	}
+	if cp, ok := exec.(executor.ErrorClassifierProvider); ok {
+		return cp.IsRetryable(err)
+	}
+	return p.classifier(err)
+}
+
+// timeoutFor returns the execution deadline for a job type: the
+// executor's own default if it declares one, otherwise the pool's.
+func (p *WorkerPool) timeoutFor(jobType string) time.Duration {
+	exec, err := p.executors.Get(jobType)
 	if err != nil {
-		log.Printf("Worker %d: job %s failed after %v: %v",
-			workerID, job.ID, duration, err)
-		p.handleFailure(ctx, job, err, true)
-	} else {
-		log.Printf("Worker %d: job %s succeeded in %v",
-			workerID, job.ID, duration)
-		p.handleSuccess(ctx, job)
+		return p.jobTimeout
 	}
+	if tp, ok := exec.(executor.TimeoutProvider); ok && tp.DefaultTimeout() > 0 {
+		return tp.DefaultTimeout()
+	}
+	return p.jobTimeout
 }
 
 // handleSuccess handles successful job execution.
-func (p *WorkerPool) handleSuccess(ctx context.Context, job *model.Job) {
+// logger is the job's jobLogger.
+func (p *WorkerPool) handleSuccess(ctx context.Context, logger *slog.Logger, job *model.Job) {
 	if err := p.service.TransitionState(ctx, job.ID, state.SUCCEEDED); err != nil {
-		log.Printf("Failed to transition job %s to SUCCEEDED: %v", job.ID, err)
+		logger.Error("Failed to transition job to SUCCEEDED", "state", state.RUNNING, "error", err)
 		return
 	}
 	p.metrics.JobsSucceeded.Inc()
+	p.metrics.JobAttempts.Observe(float64(job.Attempt))
 }
 
 // handleFailure handles failed job execution.
-func (p *WorkerPool) handleFailure(ctx context.Context, job *model.Job, execErr error, retryable bool) {
+// logger is the job's jobLogger.
+func (p *WorkerPool) handleFailure(ctx context.Context, logger *slog.Logger, job *model.Job, execErr error, retryable bool) {
 	if !retryable {
-		log.Printf("Job %s failed permanently: %v", job.ID, execErr)
-		if err := p.service.TransitionState(ctx, job.ID, state.FAILED); err != nil {
-			log.Printf("Failed to transition job %s to FAILED: %v", job.ID, err)
+		logger.Warn("Job failed permanently", "state", state.RUNNING, "error", execErr)
+		if err := p.service.TransitionStateWithReason(ctx, job.ID, state.FAILED, execErr.Error()); err != nil {
+			logger.Error("Failed to transition job to FAILED", "state", state.RUNNING, "error", err)
 			return
 		}
 		p.metrics.JobsFailed.Inc()
+		p.metrics.JobAttempts.Observe(float64(job.Attempt))
 		return
 	}
 
 	// Retryable error
-	if err := p.service.HandleFailure(ctx, job.ID, execErr); err != nil {
-		log.Printf("Failed to handle job failure for %s: %v", job.ID, err)
-		return
-	}
-
-	// Check if retries are now exhausted
-	updatedJob, err := p.service.GetJob(ctx, job.ID)
+	exhausted, err := p.service.HandleFailure(ctx, job.ID, execErr)
 	if err != nil {
-		log.Printf("Failed to get job %s after failure: %v", job.ID, err)
+		logger.Error("Failed to handle job failure", "state", state.RUNNING, "error", err)
 		return
 	}
-	if updatedJob.State == state.FAILED {
+
+	if exhausted {
+		// Retries ran out, unlike the non-retryable path above.
+		// A failed job keeps the attempt it failed on.
+		logger.Error("Job exhausted retries", "state", state.FAILED, "error", execErr)
 		p.metrics.JobsFailed.Inc()
+		p.metrics.JobsExhausted.WithLabelValues(job.Type).Inc()
+		p.metrics.JobAttempts.Observe(float64(job.Attempt))
 	}
 }
@@ -0,0 +1,1013 @@
+package worker
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/dipak0000812/orchestrix/internal/executor"
+	"github.com/dipak0000812/orchestrix/internal/job/model"
+	"github.com/dipak0000812/orchestrix/internal/job/repository"
+	"github.com/dipak0000812/orchestrix/internal/job/service"
+	"github.com/dipak0000812/orchestrix/internal/job/state"
+	"github.com/dipak0000812/orchestrix/internal/scheduler"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	dto "github.com/prometheus/client_model/go"
+)
+
+// concurrencyExecutor records the peak number of concurrent executions.
+type concurrencyExecutor struct {
+	mu      sync.Mutex
+	current int
+	peak    int
+	delay   time.Duration
+}
+
+func (e *concurrencyExecutor) Execute(ctx context.Context, payload []byte) error {
+	e.mu.Lock()
+	e.current++
+	if e.current > e.peak {
+		e.peak = e.current
+	}
+	e.mu.Unlock()
+
+	time.Sleep(e.delay)
+
+	e.mu.Lock()
+	e.current--
+	e.mu.Unlock()
+	return nil
+}
+
+// setupUnitTest creates a worker pool backed by the in-memory repository.
+func setupUnitTest(numWorkers, maxConcurrent int, executors *executor.ExecutorRegistry) (
+	*service.JobService,
+	*repository.MemoryJobRepository,
+	*WorkerPool,
+	chan *model.Job,
+) {
+	repo := repository.NewMemoryJobRepository()
+	jobService := service.NewJobService(
+		repo,
+		state.NewStateMachine(),
+		service.NewULIDGenerator(),
+		service.DefaultRetryConfig(),
+	)
+
+	jobChannel := scheduler.NewJobChannel(10)
+	workers := NewWorkerPool(
+		numWorkers,
+		maxConcurrent,
+		jobChannel,
+		executors,
+		jobService,
+		getTestMetrics(),
+		5*time.Second,
+	)
+
+	return jobService, repo, workers, jobChannel
+}
+
+// waitForState polls until the job reaches the wanted state or the timeout expires.
+func waitForState(t *testing.T, jobService *service.JobService, id string, want state.State, timeout time.Duration) {
+	t.Helper()
+
+	deadline := time.Now().Add(timeout)
+	for time.Now().Before(deadline) {
+		job, err := jobService.GetJob(context.Background(), id)
+		if err == nil && job.State == want {
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatalf("job %s did not reach %s within %v", id, want, timeout)
+}
+
+// histogramCount returns the number of observations recorded by h.
+func histogramCount(t *testing.T, h prometheus.Histogram) uint64 {
+	t.Helper()
+
+	var m dto.Metric
+	if err := h.Write(&m); err != nil {
+		t.Fatalf("Failed to read histogram: %v", err)
+	}
+	return m.GetHistogram().GetSampleCount()
+}
+
+// histogramSum returns the sum of all observations recorded by h.
+func histogramSum(t *testing.T, h prometheus.Histogram) float64 {
+	t.Helper()
+
+	var m dto.Metric
+	if err := h.Write(&m); err != nil {
+		t.Fatalf("Failed to read histogram: %v", err)
+	}
+	return m.GetHistogram().GetSampleSum()
+}
+
+func TestWorkerPool_MaxConcurrent(t *testing.T) {
+	exec := &concurrencyExecutor{delay: 50 * time.Millisecond}
+	executors := executor.NewExecutorRegistry()
+	executors.Register("tracked_job", exec)
+
+	jobService, repo, workers, jobChannel := setupUnitTest(3, 1, executors)
+	ctx := context.Background()
+
+	payload, _ := json.Marshal(map[string]string{"test": "data"})
+	for i := 0; i < 3; i++ {
+		if _, err := jobService.CreateJob(ctx, "tracked_job", payload); err != nil {
+			t.Fatalf("Failed to create job: %v", err)
+		}
+	}
+
+	claimed, err := repo.ClaimPendingJobs(ctx, 10, time.Now())
+	if err != nil {
+		t.Fatalf("ClaimPendingJobs failed: %v", err)
+	}
+
+	workers.Start()
+	defer workers.Stop()
+
+	for _, job := range claimed {
+		jobChannel <- job
+	}
+	for _, job := range claimed {
+		waitForState(t, jobService, job.ID, state.SUCCEEDED, 2*time.Second)
+	}
+
+	exec.mu.Lock()
+	defer exec.mu.Unlock()
+	if exec.peak != 1 {
+		t.Errorf("Peak concurrency = %d, want 1", exec.peak)
+	}
+}
+
+func TestWorkerPool_ObservesQueueWait(t *testing.T) {
+	executors := executor.NewExecutorRegistry()
+	executors.Register("demo_job", executor.NewDemoExecutor(10*time.Millisecond))
+
+	jobService, repo, workers, jobChannel := setupUnitTest(1, 1, executors)
+	ctx := context.Background()
+	m := getTestMetrics()
+
+	before := histogramCount(t, m.QueueWaitDuration)
+
+	job, _ := jobService.CreateJob(ctx, "demo_job", []byte(`{}`))
+	claimed, _ := repo.ClaimPendingJobs(ctx, 1, time.Now())
+
+	workers.Start()
+	defer workers.Stop()
+
+	jobChannel <- claimed[0]
+	waitForState(t, jobService, job.ID, state.SUCCEEDED, 2*time.Second)
+
+	if got := histogramCount(t, m.QueueWaitDuration) - before; got != 1 {
+		t.Errorf("QueueWaitDuration observations = %d, want 1", got)
+	}
+}
+
+func TestWorkerPool_StatsBusyTime(t *testing.T) {
+	executors := executor.NewExecutorRegistry()
+	executors.Register("slow_job", executor.NewDemoExecutor(100*time.Millisecond))
+
+	jobService, repo, workers, jobChannel := setupUnitTest(1, 1, executors)
+	ctx := context.Background()
+
+	job, _ := jobService.CreateJob(ctx, "slow_job", []byte(`{}`))
+	claimed, _ := repo.ClaimPendingJobs(ctx, 1, time.Now())
+
+	workers.Start()
+	defer workers.Stop()
+
+	time.Sleep(20 * time.Millisecond) // let the worker sit idle first
+	jobChannel <- claimed[0]
+	waitForState(t, jobService, job.ID, state.SUCCEEDED, 2*time.Second)
+
+	// The busy interval is recorded right after executeJob returns
+	deadline := time.Now().Add(time.Second)
+	for workers.Stats().Workers[0].JobsProcessed == 0 && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	stats := workers.Stats()
+	if len(stats.Workers) != 1 {
+		t.Fatalf("Expected stats for 1 worker, got %d", len(stats.Workers))
+	}
+
+	w := stats.Workers[0]
+	if w.JobsProcessed != 1 {
+		t.Errorf("JobsProcessed = %d, want 1", w.JobsProcessed)
+	}
+	if w.Busy < 100*time.Millisecond {
+		t.Errorf("Busy = %v, want at least 100ms", w.Busy)
+	}
+	if w.Idle < 20*time.Millisecond {
+		t.Errorf("Idle = %v, want at least 20ms", w.Idle)
+	}
+}
+
+// blockingExecutor runs until its context is cancelled.
+type blockingExecutor struct {
+	started chan struct{}
+}
+
+func (e *blockingExecutor) Execute(ctx context.Context, payload []byte) error {
+	close(e.started)
+	<-ctx.Done()
+	return ctx.Err()
+}
+
+func TestWorkerPool_Abort(t *testing.T) {
+	exec := &blockingExecutor{started: make(chan struct{})}
+	executors := executor.NewExecutorRegistry()
+	executors.Register("blocking_job", exec)
+
+	jobService, repo, workers, jobChannel := setupUnitTest(1, 1, executors)
+	ctx := context.Background()
+
+	job, _ := jobService.CreateJob(ctx, "blocking_job", []byte(`{}`))
+	claimed, _ := repo.ClaimPendingJobs(ctx, 1, time.Now())
+
+	workers.Start()
+	defer workers.Stop()
+
+	if workers.Abort(job.ID) {
+		t.Error("Abort() = true before the job started, want false")
+	}
+
+	jobChannel <- claimed[0]
+	<-exec.started
+
+	if _, err := jobService.ForceFail(ctx, job.ID, "wedged"); err != nil {
+		t.Fatalf("ForceFail failed: %v", err)
+	}
+	if !workers.Abort(job.ID) {
+		t.Fatal("Abort() = false for a running job, want true")
+	}
+
+	// The worker must not overwrite the force-failed state with a retry
+	time.Sleep(50 * time.Millisecond)
+	updated, _ := jobService.GetJob(ctx, job.ID)
+	if updated.State != state.FAILED {
+		t.Errorf("State = %s, want FAILED", updated.State)
+	}
+	if updated.Attempt != 1 {
+		t.Errorf("Attempt = %d, want 1 (no retry scheduled)", updated.Attempt)
+	}
+}
+
+// attemptRecorder fails its first call and records the JobContext of every call.
+type attemptRecorder struct {
+	mu       sync.Mutex
+	contexts []executor.JobContext
+}
+
+func (e *attemptRecorder) Execute(ctx context.Context, payload []byte) error {
+	jc, _ := executor.JobContextFrom(ctx)
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.contexts = append(e.contexts, jc)
+	if len(e.contexts) == 1 {
+		return errors.New("first attempt fails")
+	}
+	return nil
+}
+
+func TestWorkerPool_JobContextOnRetry(t *testing.T) {
+	exec := &attemptRecorder{}
+	executors := executor.NewExecutorRegistry()
+	executors.Register("flaky_job", exec)
+
+	jobService, repo, workers, jobChannel := setupUnitTest(1, 1, executors)
+	ctx := context.Background()
+
+	job, _ := jobService.CreateJob(ctx, "flaky_job", []byte(`{}`))
+
+	workers.Start()
+	defer workers.Stop()
+
+	// First attempt fails and moves the job to RETRYING
+	claimed, _ := repo.ClaimPendingJobs(ctx, 1, time.Now())
+	jobChannel <- claimed[0]
+	waitForState(t, jobService, job.ID, state.RETRYING, 2*time.Second)
+
+	// Retry succeeds (claimed as of a time past its backoff)
+	claimed, _ = repo.ClaimPendingJobs(ctx, 1, time.Now().Add(time.Minute))
+	jobChannel <- claimed[0]
+	waitForState(t, jobService, job.ID, state.SUCCEEDED, 2*time.Second)
+
+	exec.mu.Lock()
+	defer exec.mu.Unlock()
+	if len(exec.contexts) != 2 {
+		t.Fatalf("Executor called %d times, want 2", len(exec.contexts))
+	}
+
+	retry := exec.contexts[1]
+	if retry.ID != job.ID || retry.Type != "flaky_job" {
+		t.Errorf("JobContext = %+v, want ID %s type flaky_job", retry, job.ID)
+	}
+	if retry.Attempt != 2 || retry.MaxAttempts != job.MaxAttempts {
+		t.Errorf("Retry JobContext attempt = %d/%d, want 2/%d", retry.Attempt, retry.MaxAttempts, job.MaxAttempts)
+	}
+}
+
+func TestWorkerPool_ObservesAttempts(t *testing.T) {
+	executors := executor.NewExecutorRegistry()
+	executors.Register("flaky_job", &attemptRecorder{})
+
+	jobService, repo, workers, jobChannel := setupUnitTest(1, 1, executors)
+	ctx := context.Background()
+	m := getTestMetrics()
+
+	beforeCount := histogramCount(t, m.JobAttempts)
+	beforeSum := histogramSum(t, m.JobAttempts)
+
+	job, _ := jobService.CreateJob(ctx, "flaky_job", []byte(`{}`))
+
+	workers.Start()
+	defer workers.Stop()
+
+	// First attempt fails: not terminal, so nothing is observed yet
+	claimed, _ := repo.ClaimPendingJobs(ctx, 1, time.Now())
+	jobChannel <- claimed[0]
+	waitForState(t, jobService, job.ID, state.RETRYING, 2*time.Second)
+	if got := histogramCount(t, m.JobAttempts) - beforeCount; got != 0 {
+		t.Fatalf("JobAttempts observations after a retry = %d, want 0", got)
+	}
+
+	// Second attempt succeeds
+	claimed, _ = repo.ClaimPendingJobs(ctx, 1, time.Now().Add(time.Minute))
+	jobChannel <- claimed[0]
+	waitForState(t, jobService, job.ID, state.SUCCEEDED, 2*time.Second)
+
+	// The state is written before the metric, so give it a moment
+	deadline := time.Now().Add(time.Second)
+	for histogramCount(t, m.JobAttempts) == beforeCount && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	if got := histogramCount(t, m.JobAttempts) - beforeCount; got != 1 {
+		t.Fatalf("JobAttempts observations = %d, want 1", got)
+	}
+	if got := histogramSum(t, m.JobAttempts) - beforeSum; got != 2 {
+		t.Errorf("JobAttempts observed %v, want 2", got)
+	}
+}
+
+func TestWorkerPool_DemoExecutorWithBehavior(t *testing.T) {
+	executors := executor.NewExecutorRegistry()
+	executors.Register("demo_job", executor.NewDemoExecutorWithBehavior(0, 2))
+
+	jobService, repo, workers, jobChannel := setupUnitTest(1, 1, executors)
+	ctx := context.Background()
+
+	job, _ := jobService.CreateJob(ctx, "demo_job", []byte(`{}`))
+
+	workers.Start()
+	defer workers.Stop()
+
+	// Attempts 1 and 2 fail; each retry is claimed as of a time past its backoff
+	claimAt := time.Now()
+	for attempt := 1; attempt <= 2; attempt++ {
+		claimed, _ := repo.ClaimPendingJobs(ctx, 1, claimAt)
+		jobChannel <- claimed[0]
+		waitForState(t, jobService, job.ID, state.RETRYING, 2*time.Second)
+		claimAt = claimAt.Add(time.Hour)
+	}
+
+	claimed, _ := repo.ClaimPendingJobs(ctx, 1, claimAt)
+	jobChannel <- claimed[0]
+	waitForState(t, jobService, job.ID, state.SUCCEEDED, 2*time.Second)
+
+	final, _ := jobService.GetJob(ctx, job.ID)
+	if final.Attempt != 3 {
+		t.Errorf("Succeeded on attempt %d, want 3", final.Attempt)
+	}
+}
+
+func TestWorkerPool_SingleAttemptFailsWithoutRetry(t *testing.T) {
+	executors := executor.NewExecutorRegistry()
+	executors.Register("failing_job", executor.NewFailingExecutor())
+
+	jobService, repo, workers, jobChannel := setupUnitTest(1, 1, executors)
+	ctx := context.Background()
+
+	job, err := jobService.CreateJobWithOptions(ctx, "failing_job", []byte(`{}`), service.JobOptions{MaxAttempts: 1})
+	if err != nil {
+		t.Fatalf("CreateJobWithOptions failed: %v", err)
+	}
+
+	workers.Start()
+	defer workers.Stop()
+
+	claimed, _ := repo.ClaimPendingJobs(ctx, 1, time.Now())
+	jobChannel <- claimed[0]
+	waitForState(t, jobService, job.ID, state.FAILED, 2*time.Second)
+
+	// A RETRYING detour would have bumped the attempt and set a backoff
+	failed, _ := jobService.GetJob(ctx, job.ID)
+	if failed.Attempt != 1 {
+		t.Errorf("Attempt = %d, want 1", failed.Attempt)
+	}
+	if failed.NextRetryAt != nil {
+		t.Errorf("NextRetryAt = %v, want nil", failed.NextRetryAt)
+	}
+
+	attemptErrs, _ := jobService.ListJobErrors(ctx, job.ID)
+	if len(attemptErrs) != 1 {
+		t.Errorf("Recorded %d attempt errors, want 1", len(attemptErrs))
+	}
+
+	// Nothing is left to claim
+	if again, _ := repo.ClaimPendingJobs(ctx, 1, time.Now().Add(time.Hour)); len(again) != 0 {
+		t.Errorf("Claimed %d jobs after final failure, want 0", len(again))
+	}
+}
+
+// errorExecutor always fails with err.
+type errorExecutor struct {
+	err error
+}
+
+func (e *errorExecutor) Execute(ctx context.Context, payload []byte) error {
+	return e.err
+}
+
+func TestWorkerPool_ErrorClassifier(t *testing.T) {
+	executors := executor.NewExecutorRegistry()
+	executors.Register("permanent_job", &errorExecutor{err: errors.New("permanent: bad input")})
+	executors.Register("transient_job", &errorExecutor{err: errors.New("timeout talking to upstream")})
+
+	jobService, repo, workers, jobChannel := setupUnitTest(1, 1, executors)
+	workers.SetErrorClassifier(func(err error) bool {
+		return !strings.Contains(err.Error(), "permanent")
+	})
+	ctx := context.Background()
+
+	permanent, _ := jobService.CreateJob(ctx, "permanent_job", []byte(`{}`))
+	transient, _ := jobService.CreateJob(ctx, "transient_job", []byte(`{}`))
+	claimed, _ := repo.ClaimPendingJobs(ctx, 2, time.Now())
+
+	workers.Start()
+	defer workers.Stop()
+	for _, job := range claimed {
+		jobChannel <- job
+	}
+
+	waitForState(t, jobService, permanent.ID, state.FAILED, 2*time.Second)
+	waitForState(t, jobService, transient.ID, state.RETRYING, 2*time.Second)
+
+	failed, _ := jobService.GetJob(ctx, permanent.ID)
+	if failed.Attempt != 1 {
+		t.Errorf("Permanent job attempt = %d, want 1 (no retries used)", failed.Attempt)
+	}
+	if failed.LastTransitionReason == nil || *failed.LastTransitionReason != "permanent: bad input" {
+		t.Errorf("Permanent job reason = %v, want the execution error", failed.LastTransitionReason)
+	}
+}
+
+func TestWorkerPool_Scale(t *testing.T) {
+	exec := &concurrencyExecutor{delay: 100 * time.Millisecond}
+	executors := executor.NewExecutorRegistry()
+	executors.Register("tracked_job", exec)
+
+	// No explicit cap, so concurrency follows the worker count
+	jobService, repo, workers, jobChannel := setupUnitTest(2, 0, executors)
+	ctx := context.Background()
+
+	workers.Start()
+	defer workers.Stop()
+
+	if err := workers.Scale(4); err != nil {
+		t.Fatalf("Scale(4) failed: %v", err)
+	}
+	if got := workers.NumWorkers(); got != 4 {
+		t.Errorf("NumWorkers() = %d, want 4", got)
+	}
+
+	for i := 0; i < 4; i++ {
+		jobService.CreateJob(ctx, "tracked_job", []byte(`{}`))
+	}
+	claimed, _ := repo.ClaimPendingJobs(ctx, 4, time.Now())
+	for _, job := range claimed {
+		jobChannel <- job
+	}
+	for _, job := range claimed {
+		waitForState(t, jobService, job.ID, state.SUCCEEDED, 2*time.Second)
+	}
+
+	exec.mu.Lock()
+	peak := exec.peak
+	exec.mu.Unlock()
+	if peak != 4 {
+		t.Errorf("Peak concurrency = %d, want 4 after scaling up", peak)
+	}
+
+	// Scale back down; zero is rejected
+	if err := workers.Scale(1); err != nil {
+		t.Fatalf("Scale(1) failed: %v", err)
+	}
+	if got := workers.NumWorkers(); got != 1 {
+		t.Errorf("NumWorkers() = %d, want 1", got)
+	}
+	if err := workers.Scale(0); err == nil {
+		t.Error("Expected error scaling to zero workers")
+	}
+}
+
+// sleepExecutor signals when it starts, then succeeds after a delay.
+type sleepExecutor struct {
+	started chan struct{}
+	delay   time.Duration
+}
+
+func (e *sleepExecutor) Execute(ctx context.Context, payload []byte) error {
+	close(e.started)
+	time.Sleep(e.delay)
+	return nil
+}
+
+func TestWorkerPool_DrainCounts(t *testing.T) {
+	quick := &sleepExecutor{started: make(chan struct{}), delay: 50 * time.Millisecond}
+	stuck := &blockingExecutor{started: make(chan struct{})}
+	executors := executor.NewExecutorRegistry()
+	executors.Register("quick_job", quick)
+	executors.Register("stuck_job", stuck)
+
+	jobService, repo, workers, jobChannel := setupUnitTest(2, 2, executors)
+	ctx := context.Background()
+
+	quickJob, _ := jobService.CreateJob(ctx, "quick_job", []byte(`{}`))
+	jobService.CreateJob(ctx, "stuck_job", []byte(`{}`))
+	claimed, _ := repo.ClaimPendingJobs(ctx, 2, time.Now())
+
+	workers.Start()
+	for _, job := range claimed {
+		jobChannel <- job
+	}
+	<-quick.started
+	<-stuck.started
+
+	drainCtx, cancel := context.WithTimeout(ctx, 500*time.Millisecond)
+	defer cancel()
+	result := workers.Drain(drainCtx)
+
+	want := DrainResult{InFlight: 2, Completed: 1, Abandoned: 1}
+	if result != want {
+		t.Errorf("Drain() = %+v, want %+v", result, want)
+	}
+
+	// The quick job was allowed to finish normally
+	finished, _ := jobService.GetJob(ctx, quickJob.ID)
+	if finished.State != state.SUCCEEDED {
+		t.Errorf("Quick job state = %s, want SUCCEEDED", finished.State)
+	}
+}
+
+func TestWorkerPool_JobsRunningGauge(t *testing.T) {
+	executors := executor.NewExecutorRegistry()
+	executors.Register("slow_job", executor.NewDemoExecutor(300*time.Millisecond))
+
+	jobService, repo, workers, jobChannel := setupUnitTest(1, 1, executors)
+	running := getTestMetrics().JobsRunning
+	before := testutil.ToFloat64(running)
+	ctx := context.Background()
+
+	job, _ := jobService.CreateJob(ctx, "slow_job", []byte(`{}`))
+	claimed, _ := repo.ClaimPendingJobs(ctx, 1, time.Now())
+
+	workers.Start()
+	defer workers.Stop()
+	jobChannel <- claimed[0]
+
+	waitForState(t, jobService, job.ID, state.RUNNING, time.Second)
+	if got := testutil.ToFloat64(running) - before; got != 1 {
+		t.Errorf("JobsRunning while the job runs = %v, want 1", got)
+	}
+
+	waitForState(t, jobService, job.ID, state.SUCCEEDED, 2*time.Second)
+	deadline := time.Now().Add(time.Second)
+	for testutil.ToFloat64(running) != before && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	if got := testutil.ToFloat64(running) - before; got != 0 {
+		t.Errorf("JobsRunning after the job finished = %v, want 0", got)
+	}
+}
+
+// wedgedExecutor ignores its context and blocks until released.
+type wedgedExecutor struct {
+	started chan struct{}
+	release chan struct{}
+}
+
+func (e *wedgedExecutor) Execute(ctx context.Context, payload []byte) error {
+	close(e.started)
+	<-e.release
+	return nil
+}
+
+func TestWorkerPool_StopGivesUpOnWedgedWorker(t *testing.T) {
+	wedged := &wedgedExecutor{started: make(chan struct{}), release: make(chan struct{})}
+	defer close(wedged.release)
+	executors := executor.NewExecutorRegistry()
+	executors.Register("wedged_job", wedged)
+
+	jobService, repo, workers, jobChannel := setupUnitTest(1, 1, executors)
+	workers.SetStopTimeout(50 * time.Millisecond)
+	ctx := context.Background()
+
+	jobService.CreateJob(ctx, "wedged_job", []byte(`{}`))
+	claimed, _ := repo.ClaimPendingJobs(ctx, 1, time.Now())
+
+	workers.Start()
+	jobChannel <- claimed[0]
+	<-wedged.started
+
+	stopped := make(chan struct{})
+	start := time.Now()
+	go func() {
+		workers.Stop()
+		close(stopped)
+	}()
+
+	select {
+	case <-stopped:
+	case <-time.After(2 * time.Second):
+		t.Fatal("Stop blocked on a worker that ignores its context")
+	}
+	if elapsed := time.Since(start); elapsed < 50*time.Millisecond {
+		t.Errorf("Stop returned after %v, want it to wait out the 50ms stop timeout", elapsed)
+	}
+}
+
+// timeoutExecutor runs until cancelled, declaring its own deadline.
+type timeoutExecutor struct {
+	timeout time.Duration
+}
+
+func (e *timeoutExecutor) DefaultTimeout() time.Duration { return e.timeout }
+
+func (e *timeoutExecutor) Execute(ctx context.Context, payload []byte) error {
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-time.After(5 * time.Second):
+		return nil
+	}
+}
+
+func TestWorkerPool_ExecutorDefaultTimeout(t *testing.T) {
+	executors := executor.NewExecutorRegistry()
+	executors.Register("slow_job", &timeoutExecutor{timeout: 50 * time.Millisecond})
+
+	// The pool's own 5s timeout would let the job succeed
+	jobService, repo, workers, jobChannel := setupUnitTest(1, 1, executors)
+	ctx := context.Background()
+
+	job, _ := jobService.CreateJob(ctx, "slow_job", []byte(`{}`))
+
+	workers.Start()
+	defer workers.Stop()
+
+	timedOutBefore := testutil.ToFloat64(workers.metrics.JobsTimedOut)
+
+	start := time.Now()
+	claimed, _ := repo.ClaimPendingJobs(ctx, 1, time.Now())
+	jobChannel <- claimed[0]
+	waitForState(t, jobService, job.ID, state.RETRYING, 2*time.Second)
+
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("Job ran for %v, want it cut off near 50ms", elapsed)
+	}
+	failed, _ := jobService.GetJob(ctx, job.ID)
+	if failed.LastError == nil || *failed.LastError != "job timed out after 50ms" {
+		t.Errorf("LastError = %v, want %q", failed.LastError, "job timed out after 50ms")
+	}
+	if failed.Attempt != 2 {
+		t.Errorf("Attempt = %d, want 2 (a timeout uses up an attempt)", failed.Attempt)
+	}
+	if got := testutil.ToFloat64(workers.metrics.JobsTimedOut) - timedOutBefore; got != 1 {
+		t.Errorf("JobsTimedOut increased by %v, want 1", got)
+	}
+}
+
+func TestWorkerPool_ShutdownKeepsAttempt(t *testing.T) {
+	exec := &blockingExecutor{started: make(chan struct{})}
+	executors := executor.NewExecutorRegistry()
+	executors.Register("stuck_job", exec)
+
+	jobService, repo, workers, jobChannel := setupUnitTest(1, 1, executors)
+	ctx := context.Background()
+
+	job, _ := jobService.CreateJob(ctx, "stuck_job", []byte(`{}`))
+	timedOutBefore := testutil.ToFloat64(workers.metrics.JobsTimedOut)
+
+	workers.Start()
+	claimed, _ := repo.ClaimPendingJobs(ctx, 1, time.Now())
+	jobChannel <- claimed[0]
+	<-exec.started
+
+	// Stop interrupts the running job
+	workers.Stop()
+
+	released, _ := jobService.GetJob(ctx, job.ID)
+	if released.State != state.PENDING {
+		t.Errorf("State = %s, want PENDING", released.State)
+	}
+	if released.Attempt != 1 {
+		t.Errorf("Attempt = %d, want 1 (shutdown shouldn't use up an attempt)", released.Attempt)
+	}
+	if released.LastError != nil {
+		t.Errorf("LastError = %q, want none", *released.LastError)
+	}
+	if got := testutil.ToFloat64(workers.metrics.JobsTimedOut) - timedOutBefore; got != 0 {
+		t.Errorf("JobsTimedOut increased by %v, want 0", got)
+	}
+}
+
+func TestWorkerPool_CountsExhaustedByType(t *testing.T) {
+	executors := executor.NewExecutorRegistry()
+	executors.Register("exhausting_job", executor.NewFailingExecutor())
+
+	jobService, repo, workers, jobChannel := setupUnitTest(1, 1, executors)
+	ctx := context.Background()
+	// Metrics are shared across the package, so compare against a baseline
+	exhausted := getTestMetrics().JobsExhausted
+	before := testutil.ToFloat64(exhausted.WithLabelValues("exhausting_job"))
+	beforeUnknown := testutil.ToFloat64(exhausted.WithLabelValues("unregistered_job"))
+
+	workers.Start()
+	defer workers.Stop()
+
+	// No executor is a non-retryable failure: FAILED, but not exhausted
+	unknown, _ := jobService.CreateJob(ctx, "unregistered_job", []byte(`{}`))
+	claimed, _ := repo.ClaimPendingJobs(ctx, 1, time.Now())
+	jobChannel <- claimed[0]
+	waitForState(t, jobService, unknown.ID, state.FAILED, 2*time.Second)
+
+	job, _ := jobService.CreateJobWithOptions(ctx, "exhausting_job", []byte(`{}`), service.JobOptions{MaxAttempts: 2})
+
+	// A retry isn't exhaustion either
+	claimed, _ = repo.ClaimPendingJobs(ctx, 1, time.Now())
+	jobChannel <- claimed[0]
+	waitForState(t, jobService, job.ID, state.RETRYING, 2*time.Second)
+	if got := testutil.ToFloat64(exhausted.WithLabelValues("exhausting_job")) - before; got != 0 {
+		t.Fatalf("JobsExhausted after a retry = %v, want 0", got)
+	}
+
+	claimed, _ = repo.ClaimPendingJobs(ctx, 1, time.Now().Add(time.Hour))
+	jobChannel <- claimed[0]
+	waitForState(t, jobService, job.ID, state.FAILED, 2*time.Second)
+
+	// The state is written before the metric, so give it a moment
+	deadline := time.Now().Add(time.Second)
+	for testutil.ToFloat64(exhausted.WithLabelValues("exhausting_job")) == before && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	if got := testutil.ToFloat64(exhausted.WithLabelValues("exhausting_job")) - before; got != 1 {
+		t.Errorf("JobsExhausted{exhausting_job} = %v, want 1", got)
+	}
+	if got := testutil.ToFloat64(exhausted.WithLabelValues("unregistered_job")) - beforeUnknown; got != 0 {
+		t.Errorf("JobsExhausted{unregistered_job} = %v, want 0 for a non-retryable failure", got)
+	}
+}
+
+func TestWorkerPool_PausedExecutionKeepsScheduling(t *testing.T) {
+	executors := executor.NewExecutorRegistry()
+	executors.Register("test_job", executor.NewDemoExecutor(0))
+
+	jobService, repo, workers, jobChannel := setupUnitTest(2, 2, executors)
+	ctx := context.Background()
+
+	sched := scheduler.NewScheduler(repo, 10*time.Millisecond, 10, jobChannel, getTestMetrics(), 0)
+
+	var ids []string
+	for i := 0; i < 5; i++ {
+		job, _ := jobService.CreateJob(ctx, "test_job", []byte(`{}`))
+		ids = append(ids, job.ID)
+	}
+
+	workers.Pause()
+	workers.Start()
+	defer workers.Stop()
+	sched.Start()
+	defer sched.Stop()
+
+	for _, id := range ids {
+		waitForState(t, jobService, id, state.SCHEDULED, 2*time.Second)
+	}
+
+	// Give the paused workers time to (wrongly) pick something up
+	time.Sleep(100 * time.Millisecond)
+	for _, id := range ids {
+		job, _ := jobService.GetJob(ctx, id)
+		if job.State != state.SCHEDULED {
+			t.Errorf("Job %s is %s while execution is paused, want SCHEDULED", id, job.State)
+		}
+	}
+	if !workers.Alive() {
+		t.Error("Paused pool should still report alive")
+	}
+
+	workers.Resume()
+	for _, id := range ids {
+		waitForState(t, jobService, id, state.SUCCEEDED, 2*time.Second)
+	}
+}
+
+// countingExecutor counts its calls, each taking delay.
+type countingExecutor struct {
+	calls atomic.Int64
+	delay time.Duration
+}
+
+func (e *countingExecutor) Execute(ctx context.Context, payload []byte) error {
+	e.calls.Add(1)
+	time.Sleep(e.delay)
+	return nil
+}
+
+func TestWorkerPool_DuplicateJobExecutesOnce(t *testing.T) {
+	exec := &countingExecutor{delay: 50 * time.Millisecond}
+	executors := executor.NewExecutorRegistry()
+	executors.Register("test_job", exec)
+
+	jobService, repo, workers, jobChannel := setupUnitTest(2, 2, executors)
+	ctx := context.Background()
+
+	job, _ := jobService.CreateJob(ctx, "test_job", []byte(`{}`))
+	claimed, _ := repo.ClaimPendingJobs(ctx, 1, time.Now())
+
+	// The same job twice, as after a release and re-claim
+	jobChannel <- claimed[0]
+	jobChannel <- claimed[0]
+
+	workers.Start()
+	defer workers.Stop()
+
+	waitForState(t, jobService, job.ID, state.SUCCEEDED, 2*time.Second)
+	time.Sleep(100 * time.Millisecond) // let the duplicate be picked up and dropped
+
+	if got := exec.calls.Load(); got != 1 {
+		t.Errorf("Executor ran %d times, want 1", got)
+	}
+	if len(jobChannel) != 0 {
+		t.Errorf("%d jobs left in the channel, want 0", len(jobChannel))
+	}
+}
+
+func TestWorkerPool_PauseType(t *testing.T) {
+	video := &countingExecutor{}
+	email := &countingExecutor{}
+	executors := executor.NewExecutorRegistry()
+	executors.Register("process_video", video)
+	executors.Register("send_email", email)
+
+	jobService, repo, workers, jobChannel := setupUnitTest(2, 2, executors)
+	ctx := context.Background()
+
+	workers.PauseType("process_video")
+	workers.Start()
+	defer workers.Stop()
+
+	// Both claimed before the pause reached the scheduler
+	videoJob, _ := jobService.CreateJob(ctx, "process_video", []byte(`{}`))
+	emailJob, _ := jobService.CreateJob(ctx, "send_email", []byte(`{}`))
+	claimed, _ := repo.ClaimPendingJobs(ctx, 2, time.Now())
+	for _, job := range claimed {
+		jobChannel <- job
+	}
+
+	waitForState(t, jobService, emailJob.ID, state.SUCCEEDED, 2*time.Second)
+
+	// The paused type's job goes back to the queue instead of running
+	waitForState(t, jobService, videoJob.ID, state.PENDING, 2*time.Second)
+	released, _ := jobService.GetJob(ctx, videoJob.ID)
+	if released.Attempt != 1 {
+		t.Errorf("Released job attempt = %d, want 1", released.Attempt)
+	}
+	if got := video.calls.Load(); got != 0 {
+		t.Errorf("Paused type ran %d times, want 0", got)
+	}
+
+	workers.ResumeType("process_video")
+	claimed, _ = repo.ClaimPendingJobs(ctx, 1, time.Now())
+	jobChannel <- claimed[0]
+	waitForState(t, jobService, videoJob.ID, state.SUCCEEDED, 2*time.Second)
+	if got := email.calls.Load(); got != 1 {
+		t.Errorf("Other type ran %d times, want 1", got)
+	}
+}
+
+func TestWorkerPool_PausedTypeNotClaimed(t *testing.T) {
+	video := &countingExecutor{}
+	executors := executor.NewExecutorRegistry()
+	executors.Register("process_video", video)
+	executors.Register("send_email", executor.NewDemoExecutor(0))
+
+	jobService, repo, workers, jobChannel := setupUnitTest(2, 2, executors)
+	ctx := context.Background()
+
+	sched := scheduler.NewScheduler(repo, 10*time.Millisecond, 10, jobChannel, getTestMetrics(), 0)
+	sched.SetPausedTypes(workers.PausedTypes)
+
+	workers.PauseType("process_video")
+	workers.Start()
+	defer workers.Stop()
+	sched.Start()
+	defer sched.Stop()
+
+	videoJob, _ := jobService.CreateJob(ctx, "process_video", []byte(`{}`))
+	emailJob, _ := jobService.CreateJob(ctx, "send_email", []byte(`{}`))
+	waitForState(t, jobService, emailJob.ID, state.SUCCEEDED, 2*time.Second)
+
+	// Many polls later the paused type's job still hasn't been claimed
+	time.Sleep(100 * time.Millisecond)
+	held, _ := jobService.GetJob(ctx, videoJob.ID)
+	if held.State != state.PENDING || held.ScheduledAt != nil {
+		t.Errorf("Paused type's job is %s (scheduled at %v), want PENDING and never claimed", held.State, held.ScheduledAt)
+	}
+
+	workers.ResumeType("process_video")
+	waitForState(t, jobService, videoJob.ID, state.SUCCEEDED, 2*time.Second)
+	if got := video.calls.Load(); got != 1 {
+		t.Errorf("Resumed type ran %d times, want 1", got)
+	}
+}
+
+// lockedBuffer is a bytes.Buffer safe for concurrent log writes.
+type lockedBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *lockedBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *lockedBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+func TestWorkerPool_JobLogFields(t *testing.T) {
+	var logs lockedBuffer
+	previous := slog.Default()
+	slog.SetDefault(slog.New(slog.NewJSONHandler(&logs, nil)))
+	t.Cleanup(func() { slog.SetDefault(previous) })
+
+	executors := executor.NewExecutorRegistry()
+	executors.Register("failing_job", executor.NewFailingExecutor())
+
+	jobService, repo, workers, jobChannel := setupUnitTest(1, 1, executors)
+	ctx := context.Background()
+
+	job, _ := jobService.CreateJob(ctx, "failing_job", []byte(`{}`))
+	claimed, _ := repo.ClaimPendingJobs(ctx, 1, time.Now())
+
+	workers.Start()
+	jobChannel <- claimed[0]
+	waitForState(t, jobService, job.ID, state.RETRYING, 2*time.Second)
+	workers.Stop()
+
+	var entry map[string]any
+	for _, line := range strings.Split(logs.String(), "\n") {
+		if strings.Contains(line, `"msg":"Job failed"`) {
+			if err := json.Unmarshal([]byte(line), &entry); err != nil {
+				t.Fatalf("Failed to decode log line %q: %v", line, err)
+			}
+			break
+		}
+	}
+	if entry == nil {
+		t.Fatalf("No \"Job failed\" line in logs:\n%s", logs.String())
+	}
+
+	want := map[string]any{
+		"worker_id": float64(0),
+		"job_id":    job.ID,
+		"job_type":  "failing_job",
+		"attempt":   float64(1),
+		"state":     string(state.RUNNING),
+	}
+	for key, value := range want {
+		if entry[key] != value {
+			t.Errorf("%s = %v, want %v", key, entry[key], value)
+		}
+	}
+	if entry["error"] == nil {
+		t.Error("Expected the error to be logged")
+	}
+}
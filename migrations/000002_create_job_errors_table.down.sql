@@ -0,0 +1,3 @@
+-- Rollback: Drop the error history table
+DROP INDEX IF EXISTS idx_job_errors_job_id;
+DROP TABLE IF EXISTS job_errors;
@@ -0,0 +1,11 @@
+-- Per-attempt error history, kept even when a job eventually succeeds
+CREATE TABLE IF NOT EXISTS job_errors (
+    id BIGSERIAL PRIMARY KEY,
+    job_id TEXT NOT NULL REFERENCES jobs(id) ON DELETE CASCADE,
+    attempt INTEGER NOT NULL,
+    error TEXT NOT NULL,
+    occurred_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+-- Errors are always fetched per job, in attempt order
+CREATE INDEX IF NOT EXISTS idx_job_errors_job_id ON job_errors(job_id, occurred_at);
@@ -0,0 +1,2 @@
+-- Rollback: Drop the transition reason column
+ALTER TABLE jobs DROP COLUMN IF EXISTS last_transition_reason;
@@ -0,0 +1,2 @@
+-- Why the job last changed state (e.g. "user cancelled via API")
+ALTER TABLE jobs ADD COLUMN IF NOT EXISTS last_transition_reason TEXT;
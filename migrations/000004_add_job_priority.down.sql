@@ -0,0 +1,3 @@
+-- Rollback: Drop the priority column and its index
+DROP INDEX IF EXISTS idx_jobs_state_priority_created_at;
+ALTER TABLE jobs DROP COLUMN IF EXISTS priority;
@@ -0,0 +1,5 @@
+-- Job priority: higher values are claimed first
+ALTER TABLE jobs ADD COLUMN IF NOT EXISTS priority INTEGER NOT NULL DEFAULT 0;
+
+-- Scheduler claims by state, then priority, then age
+CREATE INDEX IF NOT EXISTS idx_jobs_state_priority_created_at ON jobs(state, priority DESC, created_at);
@@ -0,0 +1,2 @@
+-- Rollback: Drop the next_retry_at column
+ALTER TABLE jobs DROP COLUMN IF EXISTS next_retry_at;
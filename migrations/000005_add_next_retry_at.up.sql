@@ -0,0 +1,2 @@
+-- When a RETRYING job's backoff expires; the scheduler skips it until then
+ALTER TABLE jobs ADD COLUMN IF NOT EXISTS next_retry_at TIMESTAMP;
@@ -0,0 +1,5 @@
+-- Rollback: Drop the recurring job columns and their index
+DROP INDEX IF EXISTS idx_jobs_parent_id;
+ALTER TABLE jobs DROP COLUMN IF EXISTS parent_id;
+ALTER TABLE jobs DROP COLUMN IF EXISTS schedule;
+ALTER TABLE jobs DROP COLUMN IF EXISTS run_at;
@@ -0,0 +1,7 @@
+-- Recurring jobs: each occurrence carries the cron schedule and links back
+-- to the first job of its series; run_at holds occurrences until they're due
+ALTER TABLE jobs ADD COLUMN IF NOT EXISTS run_at TIMESTAMP;
+ALTER TABLE jobs ADD COLUMN IF NOT EXISTS schedule TEXT;
+ALTER TABLE jobs ADD COLUMN IF NOT EXISTS parent_id TEXT;
+
+CREATE INDEX IF NOT EXISTS idx_jobs_parent_id ON jobs(parent_id) WHERE parent_id IS NOT NULL;
@@ -0,0 +1,5 @@
+-- Rollback: Restore the NOT NULL payload and drop payload_ref.
+-- Jobs with externally stored payloads lose them.
+UPDATE jobs SET payload = '{}'::jsonb WHERE payload IS NULL;
+ALTER TABLE jobs ALTER COLUMN payload SET NOT NULL;
+ALTER TABLE jobs DROP COLUMN IF EXISTS payload_ref;
@@ -0,0 +1,4 @@
+-- Large payloads live in an external blob store; the row keeps only the key.
+-- payload is NULL exactly when payload_ref is set.
+ALTER TABLE jobs ADD COLUMN IF NOT EXISTS payload_ref TEXT;
+ALTER TABLE jobs ALTER COLUMN payload DROP NOT NULL;
@@ -0,0 +1,3 @@
+-- Rollback: Drop the result column and its index
+DROP INDEX IF EXISTS idx_jobs_result;
+ALTER TABLE jobs DROP COLUMN IF EXISTS result;
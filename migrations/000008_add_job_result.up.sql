@@ -0,0 +1,4 @@
+-- Result: JSON output recorded when a job succeeds, queried by containment
+ALTER TABLE jobs ADD COLUMN IF NOT EXISTS result JSONB;
+
+CREATE INDEX IF NOT EXISTS idx_jobs_result ON jobs USING GIN (result jsonb_path_ops);
@@ -0,0 +1,2 @@
+-- Rollback: Drop the rerun_count column
+ALTER TABLE jobs DROP COLUMN IF EXISTS rerun_count;
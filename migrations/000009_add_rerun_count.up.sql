@@ -0,0 +1,2 @@
+-- Rerun count: how many times a finished job was manually run again
+ALTER TABLE jobs ADD COLUMN IF NOT EXISTS rerun_count INTEGER NOT NULL DEFAULT 0;
@@ -0,0 +1,3 @@
+-- Rollback: Drop the idempotency key and its unique index
+DROP INDEX IF EXISTS idx_jobs_idempotency_key;
+ALTER TABLE jobs DROP COLUMN IF EXISTS idempotency_key;
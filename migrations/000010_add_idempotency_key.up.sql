@@ -0,0 +1,5 @@
+-- Idempotency key: lets clients retry a create without making a second job
+ALTER TABLE jobs ADD COLUMN IF NOT EXISTS idempotency_key TEXT;
+
+-- One job per key and type; jobs without a key (NULL) never conflict
+CREATE UNIQUE INDEX IF NOT EXISTS idx_jobs_idempotency_key ON jobs(idempotency_key, type);